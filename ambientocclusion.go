@@ -0,0 +1,162 @@
+package tetra3d
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	AmbientOcclusionQualityLow    = iota // Ambient occlusion is sampled 8 times per pixel
+	AmbientOcclusionQualityMedium        // Ambient occlusion is sampled 16 times per pixel
+	AmbientOcclusionQualityHigh          // Ambient occlusion is sampled 32 times per pixel
+)
+
+// ambientOcclusionShaderSrc darkens each pixel of the color texture (image 1) according to how many of the surrounding pixels in the depth
+// texture (image 0) are closer to the Camera than it is, within a range, which darkens creases, corners, and areas where objects meet.
+var ambientOcclusionShaderSrc = []byte(
+	`package main
+
+	var Samples float
+	var Radius float
+	var Strength float
+	var Bias float
+	var Range float
+
+	func decodeDepth(rgba vec4) float {
+		return rgba.r + (rgba.g / 255) + (rgba.b / 65025)
+	}
+
+	func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+
+		colorTex := imageSrc1At(texCoord)
+		depthTex := imageSrc0At(texCoord)
+
+		if depthTex.a == 0 {
+			return colorTex
+		}
+
+		depth := decodeDepth(depthTex)
+		occlusion := 0.0
+
+		for i := 0; i < 32; i++ {
+
+			if float(i) >= Samples {
+				break
+			}
+
+			// Samples are spread out in a spiral using the golden angle, so they cover the area around the pixel evenly.
+			angle := float(i) * 2.39996
+			dist := Radius * sqrt((float(i)+0.5)/Samples)
+			offset := vec2(cos(angle), sin(angle)) * dist
+
+			sample := imageSrc0At((position.xy + offset) / imageSrcTextureSize())
+
+			if sample.a > 0 {
+				diff := depth - decodeDepth(sample)
+				if diff > Bias {
+					occlusion += 1 - smoothstep(0, Range, diff)
+				}
+			}
+
+		}
+
+		ao := 1 - Strength*occlusion/Samples
+
+		return vec4(colorTex.rgb*ao, colorTex.a)
+
+	}
+	`,
+)
+
+// AmbientOcclusion holds settings for a Camera's screen-space ambient occlusion. When on, the Camera darkens creases, corners, and areas where
+// objects meet or touch by sampling its depth texture around each pixel, which adds a lot of depth to flat, vertex-lit scenes. Ambient occlusion
+// requires the Camera to render depth, and is applied when the Camera's ColorTexture() is retrieved after rendering, so it's only applied once
+// no matter how many times Render() is called in a frame. It costs a full-screen shader pass, with the cost depending on the Quality setting.
+type AmbientOcclusion struct {
+	On       bool
+	Quality  int     // The quality of the ambient occlusion, determining how many samples are taken per pixel; one of the AmbientOcclusionQuality constants. Defaults to AmbientOcclusionQualityMedium.
+	Radius   float64 // The radius around each pixel that's sampled, as a fraction of the Camera's texture height. Defaults to 0.02.
+	Strength float64 // How dark fully occluded areas get, ranging from 0 to 1. Defaults to 0.75.
+	Bias     float64 // How much closer to the Camera (in world units) surrounding surfaces must be to occlude a pixel, which avoids flat surfaces occluding themselves. Defaults to 0.05.
+	Range    float64 // How much closer to the Camera (in world units) surrounding surfaces can be before their occlusion fades out, which avoids dark halos around objects in front of distant ones. Defaults to 1.
+
+	shader  *ebiten.Shader
+	texture *ebiten.Image
+	options *ebiten.DrawRectShaderOptions
+	dirty   bool
+}
+
+func newAmbientOcclusion() AmbientOcclusion {
+	return AmbientOcclusion{
+		Quality:  AmbientOcclusionQualityMedium,
+		Radius:   0.02,
+		Strength: 0.75,
+		Bias:     0.05,
+		Range:    1,
+	}
+}
+
+// ambientOcclusionTexture returns the Camera's color texture with ambient occlusion applied, applying it if the Camera has rendered since
+// it was last applied.
+func (camera *Camera) ambientOcclusionTexture() *ebiten.Image {
+
+	ao := &camera.AmbientOcclusion
+
+	w, h := camera.resultColorTexture.Size()
+
+	if ao.texture != nil {
+		if tw, th := ao.texture.Size(); tw != w || th != h {
+			ao.texture.Dispose()
+			ao.texture = nil
+		}
+	}
+
+	if ao.texture == nil {
+		ao.texture = ebiten.NewImage(w, h)
+		ao.dirty = true
+	}
+
+	if !ao.dirty {
+		return ao.texture
+	}
+
+	ao.dirty = false
+
+	if ao.shader == nil {
+
+		shader, err := ebiten.NewShader(ambientOcclusionShaderSrc)
+
+		if err != nil {
+			panic(err)
+		}
+
+		ao.shader = shader
+		ao.options = &ebiten.DrawRectShaderOptions{
+			CompositeMode: ebiten.CompositeModeCopy,
+			Uniforms:      map[string]interface{}{},
+		}
+
+	}
+
+	samples := 16
+	switch ao.Quality {
+	case AmbientOcclusionQualityLow:
+		samples = 8
+	case AmbientOcclusionQualityHigh:
+		samples = 32
+	}
+
+	// Depth is stored in the depth texture as a fraction of the Camera's far plane, so world distances are converted to match.
+	ao.options.Uniforms["Samples"] = float32(samples)
+	ao.options.Uniforms["Radius"] = float32(ao.Radius * float64(h))
+	ao.options.Uniforms["Strength"] = float32(ao.Strength)
+	ao.options.Uniforms["Bias"] = float32(ao.Bias / camera.Far)
+	ao.options.Uniforms["Range"] = float32(ao.Range / camera.Far)
+
+	ao.options.Images[0] = camera.resultDepthTexture
+	ao.options.Images[1] = camera.resultColorTexture
+
+	ao.texture.DrawRectShader(w, h, ao.shader, ao.options)
+
+	return ao.texture
+
+}
@@ -2,15 +2,17 @@ package tetra3d
 
 import (
 	"log"
+	"sort"
 	"time"
 
 	"github.com/kvartborg/vector"
 )
 
 const (
-	TrackTypePosition = "Pos"
-	TrackTypeScale    = "Sca"
-	TrackTypeRotation = "Rot"
+	TrackTypePosition     = "Pos"
+	TrackTypeScale        = "Sca"
+	TrackTypeRotation     = "Rot"
+	TrackTypeMorphWeights = "Mor" // Keyframes hold a []float64 of a Model's MorphWeights
 
 	InterpolationLinear = iota
 	InterpolationConstant
@@ -29,6 +31,10 @@ func (data *Data) AsQuaternion() *Quaternion {
 	return data.contents.(*Quaternion)
 }
 
+func (data *Data) AsFloats() []float64 {
+	return data.contents.([]float64)
+}
+
 type Keyframe struct {
 	Time float64
 	Data Data
@@ -51,6 +57,13 @@ func (track *AnimationTrack) AddKeyframe(time float64, data interface{}) {
 	track.Keyframes = append(track.Keyframes, newKeyframe(time, Data{data}))
 }
 
+// surroundingKeyframes returns the keyframes immediately before and at-or-after the given time using a binary search. The time should be
+// between the first and last keyframes' times.
+func (track *AnimationTrack) surroundingKeyframes(time float64) (*Keyframe, *Keyframe) {
+	index := sort.Search(len(track.Keyframes), func(i int) bool { return track.Keyframes[i].Time >= time })
+	return track.Keyframes[index-1], track.Keyframes[index]
+}
+
 func (track *AnimationTrack) ValueAsVector(time float64) vector.Vector {
 
 	if len(track.Keyframes) == 0 {
@@ -63,19 +76,7 @@ func (track *AnimationTrack) ValueAsVector(time float64) vector.Vector {
 		return last.Data.AsVector()
 	} else {
 
-		var first *Keyframe
-		var last *Keyframe
-
-		for _, k := range track.Keyframes {
-
-			if k.Time < time {
-				first = k
-			} else {
-				last = k
-				break
-			}
-
-		}
+		first, last := track.surroundingKeyframes(time)
 
 		if time == first.Time {
 			return first.Data.AsVector()
@@ -117,19 +118,7 @@ func (track *AnimationTrack) ValueAsQuaternion(time float64) *Quaternion {
 		return last.Data.AsQuaternion()
 	} else {
 
-		var first *Keyframe
-		var last *Keyframe
-
-		for _, k := range track.Keyframes {
-
-			if k.Time < time {
-				first = k
-			} else {
-				last = k
-				break
-			}
-
-		}
+		first, last := track.surroundingKeyframes(time)
 
 		if time == first.Time {
 			return first.Data.AsQuaternion()
@@ -150,6 +139,146 @@ func (track *AnimationTrack) ValueAsQuaternion(time float64) *Quaternion {
 
 }
 
+// sampleVector samples the track's vector value at the given time, writing it into out (which is allocated if it's nil) rather than allocating
+// a new vector or returning a keyframe's vector. If the track has no keyframes, nil is returned.
+func (track *AnimationTrack) sampleVector(time float64, out vector.Vector) vector.Vector {
+
+	if len(track.Keyframes) == 0 {
+		return nil
+	}
+
+	if len(out) < 3 {
+		out = vector.Vector{0, 0, 0}
+	}
+
+	var fd, ld vector.Vector
+	t := 0.0
+
+	if first := track.Keyframes[0]; time <= first.Time {
+		fd = first.Data.AsVector()
+	} else if last := track.Keyframes[len(track.Keyframes)-1]; time >= last.Time {
+		fd = last.Data.AsVector()
+	} else {
+
+		first, last := track.surroundingKeyframes(time)
+
+		fd = first.Data.AsVector()
+
+		if track.Interpolation != InterpolationConstant && time != first.Time {
+			ld = last.Data.AsVector()
+			t = (time - first.Time) / (last.Time - first.Time)
+		}
+
+	}
+
+	if ld == nil {
+		out[0], out[1], out[2] = fd[0], fd[1], fd[2]
+	} else {
+		out[0] = fd[0] + (ld[0]-fd[0])*t
+		out[1] = fd[1] + (ld[1]-fd[1])*t
+		out[2] = fd[2] + (ld[2]-fd[2])*t
+	}
+
+	return out
+
+}
+
+// sampleQuaternion samples the track's rotation at the given time, writing it into out (which is allocated if it's nil) rather than allocating
+// a new Quaternion or returning a keyframe's Quaternion. If the track has no keyframes, nil is returned.
+func (track *AnimationTrack) sampleQuaternion(time float64, out *Quaternion) *Quaternion {
+
+	if len(track.Keyframes) == 0 {
+		return nil
+	}
+
+	if out == nil {
+		out = NewQuaternion(0, 0, 0, 1)
+	}
+
+	var fd, ld *Quaternion
+	t := 0.0
+
+	if first := track.Keyframes[0]; time <= first.Time {
+		fd = first.Data.AsQuaternion()
+	} else if last := track.Keyframes[len(track.Keyframes)-1]; time >= last.Time {
+		fd = last.Data.AsQuaternion()
+	} else {
+
+		first, last := track.surroundingKeyframes(time)
+
+		fd = first.Data.AsQuaternion()
+
+		if time != first.Time {
+			ld = last.Data.AsQuaternion()
+			t = (time - first.Time) / (last.Time - first.Time)
+		}
+
+	}
+
+	if ld == nil {
+		*out = *fd
+		return out
+	}
+
+	// This is the same as Quaternion.Lerp(), but without allocating.
+	sign := 1.0
+	if fd.Dot(ld) < 0 {
+		sign = -1
+	}
+
+	out.X = fd.X - t*(fd.X-ld.X*sign)
+	out.Y = fd.Y - t*(fd.Y-ld.Y*sign)
+	out.Z = fd.Z - t*(fd.Z-ld.Z*sign)
+	out.W = fd.W - t*(fd.W-ld.W*sign)
+
+	return out
+
+}
+
+// sampleFloats samples the track's float values (i.e. morph weights) at the given time, writing them into out (which is resized if it's too
+// short) rather than allocating a new slice or returning a keyframe's slice. If the track has no keyframes, nil is returned.
+func (track *AnimationTrack) sampleFloats(time float64, out []float64) []float64 {
+
+	if len(track.Keyframes) == 0 {
+		return nil
+	}
+
+	var fd, ld []float64
+	t := 0.0
+
+	if first := track.Keyframes[0]; time <= first.Time {
+		fd = first.Data.AsFloats()
+	} else if last := track.Keyframes[len(track.Keyframes)-1]; time >= last.Time {
+		fd = last.Data.AsFloats()
+	} else {
+
+		first, last := track.surroundingKeyframes(time)
+
+		fd = first.Data.AsFloats()
+
+		if track.Interpolation != InterpolationConstant && time != first.Time {
+			ld = last.Data.AsFloats()
+			t = (time - first.Time) / (last.Time - first.Time)
+		}
+
+	}
+
+	if len(out) != len(fd) {
+		out = make([]float64, len(fd))
+	}
+
+	for i := range fd {
+		if ld == nil || i >= len(ld) {
+			out[i] = fd[i]
+		} else {
+			out[i] = fd[i] + (ld[i]-fd[i])*t
+		}
+	}
+
+	return out
+
+}
+
 func newAnimationTrack(trackType string) *AnimationTrack {
 	return &AnimationTrack{
 		Type:      trackType,
@@ -216,11 +345,12 @@ const (
 	FinishModeStop            // Stop on animation completion
 )
 
-// AnimationValues indicate the current position, scale, and rotation for a Node.
+// AnimationValues indicate the current position, scale, and rotation for a Node, as well as its morph weights if it's a Model.
 type AnimationValues struct {
-	Position vector.Vector
-	Scale    vector.Vector
-	Rotation *Quaternion
+	Position     vector.Vector
+	Scale        vector.Vector
+	Rotation     *Quaternion
+	MorphWeights []float64
 }
 
 // AnimationPlayer is an object that allows you to play back an animation on a Node.
@@ -239,6 +369,12 @@ type AnimationPlayer struct {
 	prevAnimatedProperties map[INode]*AnimationValues                // The previous properties that have been animated from the previously Play()'d animation
 	BlendTime              float64                                   // How much time in seconds to blend between two animations
 	blendStart             time.Time                                 // The time that the blend started
+	blendElapsed           float64                                   // How much time in seconds has been blended through; used in deterministic mode
+	blending               bool                                      // Whether the player is currently blending between two animations
+	channelOrder           []*AnimationChannel                       // The Animation's channels, sorted by name so they're processed in a fixed order
+	nodeOrder              []INode                                   // The animated Nodes, in the order they were assigned to channels
+	blendScratch           vector.Vector                             // Scratch vector used when blending to avoid allocating
+	blendQuat              Quaternion                                // Scratch quaternion used when blending to avoid allocating
 	// If the AnimationPlayer should play the last frame or not. For example, if you have an animation that starts on frame 1 and goes to frame 10,
 	// then if PlayLastFrame is on, it will play all frames, INCLUDING frame 10, and only then repeat (if it's set to repeat).
 	// Otherwise, it will only play frames 1 - 9, which can be good if your last frame is a repeat of the first to make a cyclical animation.
@@ -267,6 +403,8 @@ func (ap *AnimationPlayer) Clone() *AnimationPlayer {
 		newAP.ChannelsToNodes[channel] = node
 	}
 	newAP.ChannelsUpdated = ap.ChannelsUpdated
+	newAP.channelOrder = append([]*AnimationChannel{}, ap.channelOrder...)
+	newAP.nodeOrder = append([]INode{}, ap.nodeOrder...)
 
 	newAP.Animation = ap.Animation
 	newAP.Playhead = ap.Playhead
@@ -304,6 +442,8 @@ func (ap *AnimationPlayer) Play(animation *Animation) {
 			ap.prevAnimatedProperties[n] = v
 		}
 		ap.blendStart = time.Now()
+		ap.blendElapsed = 0
+		ap.blending = true
 	}
 
 }
@@ -318,36 +458,51 @@ func (ap *AnimationPlayer) assignChannels() {
 
 		ap.ChannelsToNodes = map[*AnimationChannel]INode{}
 
+		// Channels are sorted by name so that they're always processed in the same order (rather than Go's random map iteration order),
+		// as multiple channels can animate the same Node.
+		ap.channelOrder = ap.channelOrder[:0]
+		for _, channel := range ap.Animation.Channels {
+			ap.channelOrder = append(ap.channelOrder, channel)
+		}
+		sort.Slice(ap.channelOrder, func(i, j int) bool { return ap.channelOrder[i].Name < ap.channelOrder[j].Name })
+
+		ap.nodeOrder = ap.nodeOrder[:0]
+
 		childrenRecursive := ap.RootNode.ChildrenRecursive()
 
-		for _, channel := range ap.Animation.Channels {
+		for _, channel := range ap.channelOrder {
+
+			var node INode
 
 			if ap.RootNode.Name() == channel.Name {
-				ap.ChannelsToNodes[channel] = ap.RootNode
-				ap.AnimatedProperties[ap.RootNode] = &AnimationValues{}
-				continue
-			}
+				node = ap.RootNode
+			} else {
 
-			found := false
+				for _, n := range childrenRecursive {
 
-			for _, n := range childrenRecursive {
+					if n.Name() == channel.Name {
+						node = n
+						break
+					}
 
-				if n.Name() == channel.Name {
-					ap.ChannelsToNodes[channel] = n
-					ap.AnimatedProperties[n] = &AnimationValues{}
-					found = true
-					break
 				}
 
 			}
 
 			// If no channel matches, we'll just go with the root
 
-			if !found {
-				ap.ChannelsToNodes[channel] = ap.RootNode
-				ap.AnimatedProperties[ap.RootNode] = &AnimationValues{}
+			if node == nil {
+				node = ap.RootNode
+			}
+
+			ap.ChannelsToNodes[channel] = node
+
+			if _, exists := ap.AnimatedProperties[node]; !exists {
+				ap.nodeOrder = append(ap.nodeOrder, node)
 			}
 
+			ap.AnimatedProperties[node] = &AnimationValues{}
+
 		}
 
 	}
@@ -366,7 +521,7 @@ func (ap *AnimationPlayer) updateValues(dt float64) {
 				ap.assignChannels()
 			}
 
-			for _, channel := range ap.Animation.Channels {
+			for _, channel := range ap.channelOrder {
 
 				node := ap.ChannelsToNodes[channel]
 
@@ -374,20 +529,23 @@ func (ap *AnimationPlayer) updateValues(dt float64) {
 					log.Println("Error: Cannot find matching node for channel " + channel.Name + " for root " + ap.RootNode.Name())
 				} else {
 
+					// The AnimationValues are reused from frame to frame, so sampling the tracks writes into them rather than allocating.
+					props := ap.AnimatedProperties[node]
+
 					if track, exists := channel.Tracks[TrackTypePosition]; exists {
-						// node.SetLocalPosition(track.ValueAsVector(ap.Playhead))
-						ap.AnimatedProperties[node].Position = track.ValueAsVector(ap.Playhead)
+						props.Position = track.sampleVector(ap.Playhead, props.Position)
 					}
 
 					if track, exists := channel.Tracks[TrackTypeScale]; exists {
-						// node.SetLocalScale(track.ValueAsVector(ap.Playhead))
-						ap.AnimatedProperties[node].Scale = track.ValueAsVector(ap.Playhead)
+						props.Scale = track.sampleVector(ap.Playhead, props.Scale)
 					}
 
 					if track, exists := channel.Tracks[TrackTypeRotation]; exists {
-						quat := track.ValueAsQuaternion(ap.Playhead)
-						// node.SetLocalRotation(NewMatrix4RotateFromQuaternion(quat))
-						ap.AnimatedProperties[node].Rotation = quat
+						props.Rotation = track.sampleQuaternion(ap.Playhead, props.Rotation)
+					}
+
+					if track, exists := channel.Tracks[TrackTypeMorphWeights]; exists {
+						props.MorphWeights = track.sampleFloats(ap.Playhead, props.MorphWeights)
 					}
 
 				}
@@ -465,10 +623,13 @@ func (ap *AnimationPlayer) updateValues(dt float64) {
 // Update updates the animation player by the delta specified in seconds (usually 1/FPS or 1/TARGET FPS), animating the transformation properties of the root node's tree.
 func (ap *AnimationPlayer) Update(dt float64) {
 
+	stageBegin(ProfileStageAnimation)
+	defer stageEnd(ProfileStageAnimation)
+
 	ap.updateValues(dt)
 
-	if !ap.Playing && !ap.blendStart.IsZero() {
-		ap.blendStart = time.Time{}
+	if !ap.Playing && ap.blending {
+		ap.blending = false
 		ap.prevAnimatedProperties = map[INode]*AnimationValues{}
 	}
 
@@ -476,22 +637,39 @@ func (ap *AnimationPlayer) Update(dt float64) {
 		return
 	}
 
-	for node, props := range ap.AnimatedProperties {
+	bp := 0.0
 
-		_, prevExists := ap.prevAnimatedProperties[node]
+	if ap.blending {
 
-		if !ap.blendStart.IsZero() && prevExists {
+		if Deterministic {
+			ap.blendElapsed += dt
+			bp = ap.blendElapsed / ap.BlendTime
+		} else {
+			bp = float64(time.Since(ap.blendStart).Milliseconds()) / (ap.BlendTime * 1000)
+		}
 
-			bp := float64(time.Since(ap.blendStart).Milliseconds()) / (ap.BlendTime * 1000)
-			if bp > 1 {
-				bp = 1
-			}
+		if bp > 1 {
+			bp = 1
+		}
+
+	}
+
+	for _, node := range ap.nodeOrder {
+
+		props := ap.AnimatedProperties[node]
+
+		_, prevExists := ap.prevAnimatedProperties[node]
+
+		if ap.blending && prevExists {
 
 			start := ap.prevAnimatedProperties[node]
 
+			if ap.blendScratch == nil {
+				ap.blendScratch = vector.Vector{0, 0, 0}
+			}
+
 			if start.Position != nil && props.Position != nil {
-				diff := props.Position.Sub(start.Position)
-				node.SetLocalPosition(start.Position.Add(diff.Scale(bp)))
+				node.SetLocalPosition(ap.blendVectors(start.Position, props.Position, bp))
 			} else if props.Position != nil {
 				node.SetLocalPosition(props.Position)
 			} else if start.Position != nil {
@@ -499,8 +677,7 @@ func (ap *AnimationPlayer) Update(dt float64) {
 			}
 
 			if start.Scale != nil && props.Scale != nil {
-				diff := props.Scale.Sub(start.Scale)
-				node.SetLocalScale(start.Scale.Add(diff.Scale(bp)))
+				node.SetLocalScale(ap.blendVectors(start.Scale, props.Scale, bp))
 			} else if props.Scale != nil {
 				node.SetLocalScale(props.Scale)
 			} else if start.Scale != nil {
@@ -508,17 +685,17 @@ func (ap *AnimationPlayer) Update(dt float64) {
 			}
 
 			if start.Rotation != nil && props.Rotation != nil {
-				rot := start.Rotation.Lerp(props.Rotation, bp).Normalized()
-				node.SetLocalRotation(NewMatrix4RotateFromQuaternion(rot))
+				node.SetLocalRotation(NewMatrix4RotateFromQuaternion(ap.blendQuaternions(start.Rotation, props.Rotation, bp)))
 			} else if props.Rotation != nil {
 				node.SetLocalRotation(NewMatrix4RotateFromQuaternion(props.Rotation))
 			} else if start.Rotation != nil {
 				node.SetLocalRotation(NewMatrix4RotateFromQuaternion(start.Rotation))
 			}
 
-			if bp == 1 {
-				ap.blendStart = time.Time{}
-				ap.prevAnimatedProperties = map[INode]*AnimationValues{}
+			if props.MorphWeights != nil {
+				ap.setMorphWeights(node, start.MorphWeights, props.MorphWeights, bp)
+			} else if start.MorphWeights != nil {
+				ap.setMorphWeights(node, nil, start.MorphWeights, 1)
 			}
 
 		} else {
@@ -532,11 +709,85 @@ func (ap *AnimationPlayer) Update(dt float64) {
 			if props.Rotation != nil {
 				node.SetLocalRotation(NewMatrix4RotateFromQuaternion(props.Rotation))
 			}
+			if props.MorphWeights != nil {
+				ap.setMorphWeights(node, nil, props.MorphWeights, 1)
+			}
+
+		}
+
+	}
+
+	if ap.blending && bp == 1 {
+		ap.blending = false
+		ap.prevAnimatedProperties = map[INode]*AnimationValues{}
+	}
+
+}
+
+// setMorphWeights sets the morph weights of the Node provided (if it's a Model), blending them from the start weights (if they're not nil)
+// to the end weights by the percentage provided.
+func (ap *AnimationPlayer) setMorphWeights(node INode, start, end []float64, percentage float64) {
+
+	model, isModel := node.(*Model)
+
+	if !isModel {
+		return
+	}
 
+	for len(model.MorphWeights) < len(end) {
+		model.MorphWeights = append(model.MorphWeights, 0)
+	}
+
+	for i, weight := range end {
+		if start != nil && i < len(start) {
+			weight = start[i] + (weight-start[i])*percentage
 		}
+		model.MorphWeights[i] = weight
+	}
+
+}
+
+// blendVectors linearly interpolates between the start and end vectors, writing the result into the player's scratch vector.
+func (ap *AnimationPlayer) blendVectors(start, end vector.Vector, percentage float64) vector.Vector {
+	ap.blendScratch[0] = start[0] + (end[0]-start[0])*percentage
+	ap.blendScratch[1] = start[1] + (end[1]-start[1])*percentage
+	ap.blendScratch[2] = start[2] + (end[2]-start[2])*percentage
+	return ap.blendScratch
+}
+
+// blendQuaternions interpolates between the start and end Quaternions and normalizes the result (like Quaternion.Lerp().Normalized()),
+// writing the result into the player's scratch Quaternion.
+func (ap *AnimationPlayer) blendQuaternions(start, end *Quaternion, percentage float64) *Quaternion {
 
+	q := &ap.blendQuat
+
+	if percentage <= 0 {
+		*q = *start
+	} else if percentage >= 1 {
+		*q = *end
+	} else {
+
+		sign := 1.0
+		if start.Dot(end) < 0 {
+			sign = -1
+		}
+
+		q.X = start.X - percentage*(start.X-end.X*sign)
+		q.Y = start.Y - percentage*(start.Y-end.Y*sign)
+		q.Z = start.Z - percentage*(start.Z-end.Z*sign)
+		q.W = start.W - percentage*(start.W-end.W*sign)
+
+	}
+
+	if m := q.Magnitude(); m != 0 {
+		q.X /= m
+		q.Y /= m
+		q.Z /= m
+		q.W /= m
 	}
 
+	return q
+
 }
 
 // func (anim *Animation) TracksToString() string {
@@ -0,0 +1,257 @@
+package tetra3d
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"sync"
+)
+
+// AudioPlayer represents a sound player whose volume an AudioEmitter can control. *audio.Player from ebiten's audio package satisfies this interface.
+type AudioPlayer interface {
+	SetVolume(volume float64)
+}
+
+const (
+	AudioAttenuationLinear  = iota // Volume falls off linearly from full volume at MinDistance to silence at MaxDistance
+	AudioAttenuationInverse        // Volume falls off realistically (inversely proportional to distance) past MinDistance, reaching silence at MaxDistance
+	AudioAttenuationNone           // Volume doesn't fall off with distance
+)
+
+// AudioEmitter is a Node that represents a positional sound source in 3D space. Each time AudioEmitter.Update() is called, the AudioEmitter
+// attenuates the volume of its AudioPlayer according to its distance to its Listener Node (usually the Camera), calculates stereo panning
+// from the relative direction of the AudioEmitter to the Listener, and (optionally) calculates a doppler pitch factor from their relative
+// velocities. To apply the panning to a sound, wrap its stream using NewStereoPanStream() before creating the player.
+type AudioEmitter struct {
+	*Node
+	Player   AudioPlayer // The AudioPlayer to control the volume of. Can be nil, in which case the AudioEmitter just calculates volume, panning, and doppler values.
+	Listener INode       // The Node that hears the sound, usually the Camera. If nil, the sound isn't attenuated or panned.
+
+	Volume      float64 // The base volume of the AudioEmitter, ranging from 0 to 1. Defaults to 1.
+	MinDistance float64 // The distance within which the sound is heard at full volume. Defaults to 1.
+	MaxDistance float64 // The distance past which the sound can't be heard. Defaults to 50.
+	Attenuation int     // How the sound's volume falls off with distance; should be one of the AudioAttenuation constants. Defaults to AudioAttenuationInverse.
+	Rolloff     float64 // How quickly the sound's volume falls off when using AudioAttenuationInverse. Defaults to 1.
+
+	Panning      bool    // Whether the AudioEmitter calculates stereo panning. Defaults to true.
+	PanStrength  float64 // How strongly the sound is panned to either side, ranging from 0 to 1. Defaults to 1.
+	Doppler      bool    // Whether the AudioEmitter calculates a doppler pitch factor. Defaults to false.
+	DopplerScale float64 // A multiplier for the velocities used to calculate the doppler effect. Defaults to 1.
+	SpeedOfSound float64 // The speed of sound in world units per second, used to calculate the doppler effect. Defaults to 343.
+
+	currentVolume   float64
+	pan             float64
+	panLock         sync.Mutex // Guards pan, as it's read from the audio thread
+	dopplerFactor   float64
+	prevPosition    Vector3
+	prevListenerPos Vector3
+	hasPrevious     bool
+}
+
+// NewAudioEmitter returns a new AudioEmitter with the name and AudioPlayer provided (which can be nil).
+func NewAudioEmitter(name string, player AudioPlayer) *AudioEmitter {
+	return &AudioEmitter{
+		Node:          NewNode(name),
+		Player:        player,
+		Volume:        1,
+		MinDistance:   1,
+		MaxDistance:   50,
+		Attenuation:   AudioAttenuationInverse,
+		Rolloff:       1,
+		Panning:       true,
+		PanStrength:   1,
+		DopplerScale:  1,
+		SpeedOfSound:  343,
+		currentVolume: 1,
+		dopplerFactor: 1,
+	}
+}
+
+// Clone returns a clone of the AudioEmitter. Note that the clone doesn't share the original's AudioPlayer (as they would fight over its volume),
+// so you'll need to set the clone's Player yourself. The Listener is shared.
+func (emitter *AudioEmitter) Clone() INode {
+
+	clone := NewAudioEmitter(emitter.name, nil)
+	clone.Listener = emitter.Listener
+	clone.Volume = emitter.Volume
+	clone.MinDistance = emitter.MinDistance
+	clone.MaxDistance = emitter.MaxDistance
+	clone.Attenuation = emitter.Attenuation
+	clone.Rolloff = emitter.Rolloff
+	clone.Panning = emitter.Panning
+	clone.PanStrength = emitter.PanStrength
+	clone.Doppler = emitter.Doppler
+	clone.DopplerScale = emitter.DopplerScale
+	clone.SpeedOfSound = emitter.SpeedOfSound
+
+	clone.Node = emitter.Node.Clone().(*Node)
+	for _, child := range clone.children {
+		child.setParent(clone)
+	}
+
+	return clone
+
+}
+
+// Update updates the AudioEmitter's volume, panning, and doppler values, and sets its AudioPlayer's volume. dt is the time (in seconds) since the
+// last time Update() was called (so 1.0 / 60 for a game running at 60 FPS); it's used to calculate velocities for the doppler effect.
+func (emitter *AudioEmitter) Update(dt float64) {
+
+	if emitter.Listener == nil {
+
+		emitter.currentVolume = emitter.Volume
+		emitter.setPan(0)
+		emitter.dopplerFactor = 1
+		emitter.hasPrevious = false
+
+	} else {
+
+		position := NewVector3FromVector(emitter.WorldPosition())
+		listenerPos := NewVector3FromVector(emitter.Listener.WorldPosition())
+
+		diff := position.Sub(listenerPos)
+		distance := diff.Magnitude()
+
+		emitter.currentVolume = emitter.Volume * emitter.attenuate(distance)
+
+		if emitter.Panning && distance > 0 {
+			right := NewVector3FromVector(emitter.Listener.WorldRotation().Right())
+			emitter.setPan(math.Max(math.Min(diff.Unit().Dot(right)*emitter.PanStrength, 1), -1))
+		} else {
+			emitter.setPan(0)
+		}
+
+		emitter.dopplerFactor = 1
+
+		if emitter.Doppler && emitter.hasPrevious && dt > 0 && distance > 0 && emitter.SpeedOfSound > 0 {
+
+			toEmitter := diff.Unit()
+			maxSpeed := emitter.SpeedOfSound * 0.99
+
+			emitterSpeed := position.Sub(emitter.prevPosition).Scale(emitter.DopplerScale / dt).Dot(toEmitter)
+			listenerSpeed := listenerPos.Sub(emitter.prevListenerPos).Scale(emitter.DopplerScale / dt).Dot(toEmitter)
+
+			emitterSpeed = math.Max(math.Min(emitterSpeed, maxSpeed), -maxSpeed)
+			listenerSpeed = math.Max(math.Min(listenerSpeed, maxSpeed), -maxSpeed)
+
+			emitter.dopplerFactor = (emitter.SpeedOfSound + listenerSpeed) / (emitter.SpeedOfSound + emitterSpeed)
+
+		}
+
+		emitter.prevPosition = position
+		emitter.prevListenerPos = listenerPos
+		emitter.hasPrevious = true
+
+	}
+
+	if emitter.Player != nil {
+		emitter.Player.SetVolume(emitter.currentVolume)
+	}
+
+}
+
+func (emitter *AudioEmitter) attenuate(distance float64) float64 {
+
+	if emitter.Attenuation == AudioAttenuationNone || distance <= emitter.MinDistance {
+		return 1
+	}
+
+	if distance >= emitter.MaxDistance {
+		return 0
+	}
+
+	if emitter.Attenuation == AudioAttenuationLinear {
+		return 1 - ((distance - emitter.MinDistance) / (emitter.MaxDistance - emitter.MinDistance))
+	}
+
+	// Inverse attenuation never quite reaches 0 on its own, so we fade it out towards MaxDistance.
+	inverse := emitter.MinDistance / (emitter.MinDistance + emitter.Rolloff*(distance-emitter.MinDistance))
+	fade := 1 - ((distance - emitter.MinDistance) / (emitter.MaxDistance - emitter.MinDistance))
+	return inverse * math.Min(fade*4, 1)
+
+}
+
+func (emitter *AudioEmitter) setPan(pan float64) {
+	emitter.panLock.Lock()
+	emitter.pan = pan
+	emitter.panLock.Unlock()
+}
+
+// CurrentVolume returns the AudioEmitter's volume as calculated by the last call to Update(), ranging from 0 to 1.
+func (emitter *AudioEmitter) CurrentVolume() float64 {
+	return emitter.currentVolume
+}
+
+// Pan returns the AudioEmitter's stereo pan as calculated by the last call to Update(), ranging from -1 (fully left) to 1 (fully right).
+// It's safe to call Pan() from another goroutine (i.e. the audio thread).
+func (emitter *AudioEmitter) Pan() float64 {
+	emitter.panLock.Lock()
+	defer emitter.panLock.Unlock()
+	return emitter.pan
+}
+
+// DopplerFactor returns the AudioEmitter's doppler pitch factor as calculated by the last call to Update(). A value above 1 means the sound
+// should be pitched up (i.e. the AudioEmitter and Listener are approaching each other), and below 1 means it should be pitched down. If
+// Doppler is false, this returns 1. Tetra3D doesn't resample audio, so applying the pitch factor is up to you.
+func (emitter *AudioEmitter) DopplerFactor() float64 {
+	return emitter.dopplerFactor
+}
+
+/////
+
+// AddChildren parents the provided children Nodes to the passed parent Node, inheriting its transformations and being under it in the scenegraph
+// hierarchy. If the children are already parented to other Nodes, they are unparented before doing so.
+func (emitter *AudioEmitter) AddChildren(children ...INode) {
+	emitter.addChildren(emitter, children...)
+}
+
+// Unparent unparents the AudioEmitter from its parent, removing it from the scenegraph.
+func (emitter *AudioEmitter) Unparent() {
+	if emitter.parent != nil {
+		emitter.parent.RemoveChildren(emitter)
+	}
+}
+
+// Type returns the NodeType for this object.
+func (emitter *AudioEmitter) Type() NodeType {
+	return NodeTypeAudioEmitter
+}
+
+// StereoPanStream wraps an audio stream of 16-bit little-endian stereo samples (the format ebiten's audio package uses), panning it according
+// to an AudioEmitter's current pan as it's read.
+type StereoPanStream struct {
+	io.ReadSeeker
+	Emitter *AudioEmitter
+}
+
+// NewStereoPanStream returns a new StereoPanStream, wrapping the source stream provided and panning it according to the AudioEmitter given.
+func NewStereoPanStream(source io.ReadSeeker, emitter *AudioEmitter) *StereoPanStream {
+	return &StereoPanStream{
+		ReadSeeker: source,
+		Emitter:    emitter,
+	}
+}
+
+// Read reads from the underlying stream, panning the samples read.
+func (stream *StereoPanStream) Read(p []byte) (int, error) {
+
+	n, err := stream.ReadSeeker.Read(p)
+
+	if stream.Emitter == nil {
+		return n, err
+	}
+
+	pan := stream.Emitter.Pan()
+	leftScale := math.Min(1-pan, 1)
+	rightScale := math.Min(1+pan, 1)
+
+	for i := 0; i+4 <= n; i += 4 {
+		left := float64(int16(binary.LittleEndian.Uint16(p[i:])))
+		right := float64(int16(binary.LittleEndian.Uint16(p[i+2:])))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(left*leftScale)))
+		binary.LittleEndian.PutUint16(p[i+2:], uint16(int16(right*rightScale)))
+	}
+
+	return n, err
+
+}
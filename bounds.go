@@ -45,8 +45,9 @@ func (col *Collision) add(intersection *Intersection) *Collision {
 }
 
 // sort the intersections by distance from starting point (which should be the same for all collisions except for triangle-triangle) to contact point.
+// The sort is stable, so that intersections at equal distances are always returned in the same order.
 func (col *Collision) sortResults() {
-	sort.Slice(col.Intersections, func(i, j int) bool {
+	sort.SliceStable(col.Intersections, func(i, j int) bool {
 		return fastVectorDistanceSquared(col.Intersections[i].StartingPoint, col.Intersections[i].ContactPoint) >
 			fastVectorDistanceSquared(col.Intersections[j].StartingPoint, col.Intersections[j].ContactPoint)
 	})
@@ -726,8 +727,9 @@ func commonCollisionTest(node INode, dx, dy, dz float64, others ...BoundingObjec
 		}
 	}
 
-	// Sort the IntersectionResults by distance (closer intersections come up "sooner").
-	sort.Slice(intersections, func(i, j int) bool {
+	// Sort the IntersectionResults by distance (closer intersections come up "sooner"). This is stable so that collisions at equal
+	// distances come up in the order the other objects were passed.
+	sort.SliceStable(intersections, func(i, j int) bool {
 		return fastVectorDistanceSquared(intersections[i].AverageContactPoint(), intersections[i].Intersections[0].StartingPoint) >
 			fastVectorDistanceSquared(intersections[j].AverageContactPoint(), intersections[j].Intersections[0].StartingPoint)
 	})
@@ -18,7 +18,7 @@ func NewBoundingTriangles(name string, mesh *Mesh) *BoundingTriangles {
 	margin := 0.25 // An additional margin to help ensure the broadphase is crossed before checking for collisions
 	return &BoundingTriangles{
 		Node:         NewNode(name),
-		BoundingAABB: NewBoundingAABB("triangle broadphase aabb", mesh.Dimensions.Width()+margin, mesh.Dimensions.Height()+margin, mesh.Dimensions.Depth()+margin),
+		BoundingAABB: NewBoundingAABB("triangle broadphase aabb", mesh.Bounds().Width()+margin, mesh.Bounds().Height()+margin, mesh.Bounds().Depth()+margin),
 		Mesh:         mesh,
 	}
 }
@@ -31,7 +31,7 @@ func (bt *BoundingTriangles) Transform() Matrix4 {
 
 	if transformDirty {
 		bt.BoundingAABB.SetWorldTransform(transform)
-		rot := bt.WorldRotation().MultVec(bt.Mesh.Dimensions.Center())
+		rot := bt.WorldRotation().MultVec(bt.Mesh.Bounds().Center())
 		bt.BoundingAABB.MoveVec(rot)
 		bt.BoundingAABB.Transform()
 	}
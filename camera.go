@@ -2,6 +2,7 @@ package tetra3d
 
 import (
 	"fmt"
+	"image"
 	"image/color"
 	"math"
 	"sort"
@@ -32,6 +33,154 @@ type DebugInfo struct {
 	ActiveLightCount int // Total active number of lights
 }
 
+// RenderStats is a struct that holds detailed statistics regarding the rendering of the current frame for a Camera. Unlike DebugInfo, the values are
+// not averaged over time. These values are reset when Camera.Clear() is called.
+type RenderStats struct {
+	TrianglesSubmitted int // Number of triangles from MeshParts that passed frustum culling and were submitted for rendering
+	TrianglesCulled    int // Number of submitted triangles that were culled because they were backfacing or entirely off-screen
+	TrianglesClipped   int // Number of submitted triangles that were discarded because they were entirely behind the camera or beyond the far plane
+	TrianglesDrawn     int // Number of triangles that were actually drawn
+
+	ModelsRendered    int // Number of Models that were rendered (i.e. at least partially visible)
+	ModelsCulled      int // Number of Models that were frustum culled, including those culled along with a branch of the scene tree or a cell of the Scene's Octree
+	ModelsSkipped     int // Number of Models that were skipped to stay within the Camera's FrameBudget
+	MeshPartsRendered int // Number of MeshParts that were rendered
+	MeshPartsCulled   int // Number of MeshParts that were frustum culled
+
+	LightsProcessed int // Number of active lights used to light the scene
+	DrawCalls       int // Number of draw calls issued to the GPU (including intermediate depth and compositing draws)
+
+	TransformTime time.Duration // CPU time spent transforming vertices (including skinning)
+	SortTime      time.Duration // CPU time spent sorting triangles
+	LightingTime  time.Duration // CPU time spent lighting vertices
+	RasterizeTime time.Duration // CPU time spent issuing draw calls to rasterize triangles
+}
+
+// AdaptiveResolution holds settings for a Camera's adaptive internal resolution. When on, the Camera automatically lowers its
+// internal render scale when the frame time exceeds a budget, and raises it back when there's headroom again, keeping the
+// frame rate stable on weak hardware. Frame time is measured as the time between Camera.Clear() calls. Note that because
+// frame time is capped by vsync, FrameTimeBudget should be somewhat above the display's refresh interval (i.e. 20ms for a 60Hz
+// display), or you should turn vsync off.
+type AdaptiveResolution struct {
+	On              bool
+	FrameTimeBudget time.Duration // The maximum average frame time before the render scale is lowered. Defaults to 20 milliseconds.
+	HeadroomRatio   float64       // The render scale is raised when the average frame time is below FrameTimeBudget * HeadroomRatio. Defaults to 0.9.
+	MinScale        float64       // The minimum render scale. Defaults to 0.5.
+	Step            float64       // How much the render scale changes with each adjustment. Defaults to 0.1.
+	AdjustInterval  time.Duration // How often the frame time is evaluated (and so, how often the render scale can change). Defaults to 500 milliseconds.
+
+	frameTime  time.Duration
+	frameCount int
+	lastClear  time.Time
+	lastAdjust time.Time
+}
+
+func newAdaptiveResolution() AdaptiveResolution {
+	return AdaptiveResolution{
+		FrameTimeBudget: time.Millisecond * 20,
+		HeadroomRatio:   0.9,
+		MinScale:        0.5,
+		Step:            0.1,
+		AdjustInterval:  time.Millisecond * 500,
+	}
+}
+
+// FrameBudget holds settings for a Camera's frame budget-based level of detail. When on, the Camera measures how much time it spends rendering
+// each frame, and if that exceeds the budget, it gradually lowers its detail level (a value ranging from MinDetail to 1), raising it back up when
+// there's headroom again. As the detail level lowers, the Camera skips rendering Models that are further away (with Models beyond the Camera's
+// far plane multiplied by the detail level being skipped). The game can participate through the OnDetailChange and ShouldRender callbacks
+// (for example, to lower particle counts or swap Models to lower detail Meshes).
+type FrameBudget struct {
+	On            bool
+	Budget        time.Duration // The maximum time the Camera should spend rendering each frame. Defaults to 8 milliseconds.
+	HeadroomRatio float64       // The detail level is raised when the render time is below Budget * HeadroomRatio. Defaults to 0.8.
+	MinDetail     float64       // The minimum detail level. Defaults to 0.25.
+	Step          float64       // How much the detail level changes each frame when over or under budget. Defaults to 0.05.
+
+	// OnDetailChange, if set, is called when the detail level changes, allowing the game to scale its own costs (like particle counts)
+	// according to the new detail level.
+	OnDetailChange func(detail float64)
+
+	// ShouldRender, if set, is called for each Model before rendering it, and returns whether the Model should be rendered. distance is the distance
+	// from the Camera to the Model's bounding sphere, and defaultResult is whether the Camera would render it by default (i.e. if it's within
+	// the Camera's far plane multiplied by the detail level). distance / detail can be used as an effective distance to select lower level of
+	// detail Meshes as the detail level lowers.
+	ShouldRender func(model *Model, distance, detail float64, defaultResult bool) bool
+
+	detail     float64
+	renderTime time.Duration
+}
+
+func newFrameBudget() FrameBudget {
+	return FrameBudget{
+		Budget:        time.Millisecond * 8,
+		HeadroomRatio: 0.8,
+		MinDetail:     0.25,
+		Step:          0.05,
+		detail:        1,
+	}
+}
+
+// Detail returns the current detail level, ranging from FrameBudget.MinDetail to 1.
+func (fb *FrameBudget) Detail() float64 {
+	return fb.detail
+}
+
+// update adjusts the detail level according to the time the Camera spent rendering in the previous frame.
+func (fb *FrameBudget) update() {
+
+	renderTime := fb.renderTime
+	fb.renderTime = 0
+
+	detail := fb.detail
+
+	if !fb.On {
+		detail = 1
+	} else if renderTime > fb.Budget {
+		detail = math.Max(detail-fb.Step, fb.MinDetail)
+	} else if float64(renderTime) < float64(fb.Budget)*fb.HeadroomRatio {
+		detail = math.Min(detail+fb.Step, 1)
+	}
+
+	if detail != fb.detail {
+		fb.detail = detail
+		if fb.OnDetailChange != nil {
+			fb.OnDetailChange(detail)
+		}
+	}
+
+}
+
+// shouldRender returns if the Model should be rendered by the Camera according to the FrameBudget's detail level.
+func (fb *FrameBudget) shouldRender(camera *Camera, model *Model) bool {
+
+	if !fb.On {
+		return true
+	}
+
+	model.Transform() // Make sure the bounding sphere is up-to-date
+
+	distance := math.Sqrt(fastVectorDistanceSquared(camera.WorldPosition(), model.BoundingSphere.WorldPosition()))
+	distance = math.Max(distance-model.BoundingSphere.WorldRadius(), 0)
+
+	render := fb.detail >= 1 || distance <= camera.Far*fb.detail
+
+	if fb.ShouldRender != nil {
+		render = fb.ShouldRender(model, distance, fb.detail, render)
+	}
+
+	return render
+
+}
+
+const (
+	PerformancePresetDefault = iota // The default settings for a Camera, favoring visual accuracy.
+	// A preset for low-spec targets (particularly WebAssembly builds, which run at a fraction of native speed). The depth texture is turned off
+	// (so Models are sorted, rather than depth tested, against each other), triangles are sorted coarsely, MeshParts are batched by Material,
+	// and adaptive resolution is turned on.
+	PerformancePresetLowSpec
+)
+
 const (
 	AccumlateColorModeNone            = iota // No accumulation buffer rendering
 	AccumlateColorModeBelow                  // Accumulation buffer is on and applies over time, renders ColorTexture after the accumulation result (which is, then, below)
@@ -39,18 +188,75 @@ const (
 	AccumlateColorModeSingleLastFrame        // Accumulation buffer is on and renders just the previous frame's ColorTexture result
 )
 
+const (
+	DebugGizmoLights  = 1 << iota // Draw gizmos for Lights: point and directional lights are drawn at their positions, along with point lights' ranges and directional lights' directions
+	DebugGizmoPaths               // Draw gizmos for Paths: Paths are drawn as lines connecting their points, along with the points' indices
+	DebugGizmoCameras             // Draw gizmos for Cameras: Cameras are drawn as pyramids showing their view direction and the shape of their frustum
+
+	DebugGizmoAll = DebugGizmoLights | DebugGizmoPaths | DebugGizmoCameras // Draw all gizmos
+)
+
 // Camera represents a camera (where you look from) in Tetra3D.
 type Camera struct {
 	*Node
 
 	RenderDepth bool // If the Camera should attempt to render a depth texture; if this is true, then DepthTexture will hold the depth texture render results.
 
+	// HierarchicalCulling indicates if the Camera should frustum cull entire branches of the scene tree at once when rendering with RenderNodes().
+	// Each Node caches a bounding sphere encompassing all Models underneath it, so a Node with many children (for example, a building and all
+	// of its props) can be culled with a single test. Defaults to true.
+	HierarchicalCulling bool
+
+	// SortOpaqueTriangles indicates if the triangles of opaque MeshParts should be sorted by depth when rendering. If this is false
+	// and the Camera is rendering depth (i.e. Camera.RenderDepth is true), opaque triangles are drawn unsorted, saving rendering time.
+	// Note that depth testing doesn't work between triangles of the same MeshPart, so turning this off can cause visual glitches on
+	// self-overlapping Models. Defaults to true.
+	SortOpaqueTriangles bool
+
+	// CoarseSorting indicates if triangles should be sorted by depth quantized to 256 levels rather than 65536 levels. This makes sorting
+	// roughly twice as fast, at the cost of triangles that are close in depth occasionally being drawn out of order. Defaults to false.
+	CoarseSorting bool
+
+	// BatchByMaterial indicates if the Camera should render consecutive MeshParts that share a Material (and have the same color blending
+	// results) in a single draw call, rather than issuing a draw call for each. If the Camera is rendering depth, opaque MeshParts are also
+	// grouped by Material to maximize batching. This can be a big speed-up for scenes with many small props, but as with dynamic batching,
+	// triangles rendered in the same draw call aren't depth tested against each other. While the Camera is rendering picking IDs
+	// (Camera.RenderPicking is true), only MeshParts belonging to the same Model are batched together. Defaults to false.
+	BatchByMaterial bool
+
+	// DepthPrePass indicates if the Camera should render the depth of all opaque MeshParts first, before rendering their colors. In the
+	// following color pass, fragments that are occluded are discarded rather than composited onto the color texture. This costs an additional
+	// vertex transformation pass for opaque MeshParts, but can be a win for scenes with a lot of overdraw (like forests and cities), particularly
+	// with custom fragment shaders. Only has an effect if the Camera is rendering depth (Camera.RenderDepth is true). Defaults to false.
+	DepthPrePass bool
+
+	// RenderPicking indicates if the Camera should render an ID for each rendered Model to a picking buffer, allowing you to find out exactly
+	// which Model is under a given screen position with Camera.NodeAtScreenPosition(). If the Camera is rendering depth, the picking buffer is
+	// depth tested; otherwise, Models are drawn over each other in rendering order. Note that dynamically batched Models are picked as the
+	// Model they're batched to, and that picking limits batching by material (see Camera.BatchByMaterial). Defaults to false.
+	RenderPicking bool
+
+	// RecordingScale is the scale that frames are captured at while the Camera is recording its output with Camera.StartRecording(),
+	// with 1 being the full size of the Camera's color texture. It's read when recording starts. Defaults to 0.5.
+	RecordingScale float64
+	recorder       *cameraRecorder
+
 	resultColorTexture    *ebiten.Image // ColorTexture holds the color results of rendering any models.
 	resultDepthTexture    *ebiten.Image // DepthTexture holds the depth results of rendering any models, if Camera.RenderDepth is on.
 	colorIntermediate     *ebiten.Image
 	depthIntermediate     *ebiten.Image
 	clipAlphaIntermediate *ebiten.Image
 	clipBehind            *ebiten.Image
+	pickingTexture        *ebiten.Image
+	pickingNodes          []INode
+	pickingIDs            map[INode]int
+
+	renderTargets        []renderTarget
+	renderTargetTextures map[image.Point]cameraTextures
+
+	// flatColorFunc, if set, replaces the appearance of each rendered Model with the flat Color it returns (ignoring vertex colors and
+	// fragment shaders); it's used by the Minimap.
+	flatColorFunc func(model *Model) *Color
 
 	resultAccumulatedColorTexture *ebiten.Image // ResultAccumulatedColorTexture holds the previous frame's render result of rendering any models.
 	accumulatedBackBuffer         *ebiten.Image
@@ -62,10 +268,31 @@ type Camera struct {
 	FieldOfView float64 // Vertical field of view in degrees for a perspective projection camera
 	OrthoScale  float64 // Scale of the view for an orthographic projection camera in units horizontally
 
-	DebugInfo DebugInfo
+	DebugInfo   DebugInfo
+	RenderStats RenderStats // Detailed statistics regarding the current frame's rendering.
+
+	// AdaptiveResolution holds the settings for automatically scaling the Camera's internal resolution according to frame time.
+	// When the render scale is below 1, the Camera's textures are smaller than the size set with NewCamera() or Camera.Resize(),
+	// so they should be scaled up by 1 / Camera.RenderScale() when drawing them to the screen.
+	AdaptiveResolution AdaptiveResolution
+	renderScale        float64
+	baseWidth          int
+	baseHeight         int
+	resizePending      bool // If the Camera was resized while it had a render target, so its textures need to be resized once it's popped
+
+	// FrameBudget holds the settings for automatically lowering the Camera's level of detail when rendering takes longer than a given budget.
+	FrameBudget FrameBudget
+
+	// AmbientOcclusion holds the settings for the Camera's screen-space ambient occlusion, which darkens creases and areas where objects meet.
+	AmbientOcclusion AmbientOcclusion
+
+	// ColorGrading holds the settings for the Camera's color grading, which adjusts the tone of everything the Camera renders as a final pass.
+	ColorGrading ColorGrading
+
+	renderBuffers *renderBuffers
 
-	backfacePool             *VectorPool
 	depthShader              *ebiten.Shader
+	depthEqualShader         *ebiten.Shader
 	clipAlphaCompositeShader *ebiten.Shader
 	clipAlphaRenderShader    *ebiten.Shader
 	colorShader              *ebiten.Shader
@@ -84,13 +311,21 @@ type Camera struct {
 func NewCamera(w, h int) *Camera {
 
 	cam := &Camera{
-		Node:        NewNode("Camera"),
-		RenderDepth: true,
-		Near:        0.1,
-		Far:         100,
+		Node:                NewNode("Camera"),
+		RenderDepth:         true,
+		HierarchicalCulling: true,
+		SortOpaqueTriangles: true,
+		Near:                0.1,
+		Far:                 100,
 
-		backfacePool:          NewVectorPool(3),
 		AccumulateDrawOptions: &ebiten.DrawImageOptions{},
+		renderBuffers:         newRenderBuffers(),
+		AdaptiveResolution:    newAdaptiveResolution(),
+		FrameBudget:           newFrameBudget(),
+		AmbientOcclusion:      newAmbientOcclusion(),
+		ColorGrading:          newColorGrading(),
+		renderScale:           1,
+		RecordingScale:        0.5,
 	}
 
 	depthShaderText := []byte(
@@ -130,6 +365,43 @@ func NewCamera(w, h int) *Camera {
 		panic(err)
 	}
 
+	// The depth equal shader is used in the color pass after a depth pre-pass; it allows fragments through
+	// only if they're at (or closer than) the depth already written in the pre-pass.
+	depthEqualShaderText := []byte(
+		`package main
+
+		func encodeDepth(depth float) vec4 {
+			r := floor(depth * 255) / 255
+			g := floor(fract(depth * 255) * 255) / 255
+			b := fract(depth * 255*255)
+			return vec4(r, g, b, 1);
+		}
+
+		func decodeDepth(rgba vec4) float {
+			return rgba.r + (rgba.g / 255) + (rgba.b / 65025)
+		}
+
+		func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+
+			existingDepth := imageSrc0At(position.xy / imageSrcTextureSize())
+
+			if existingDepth.a == 0 || decodeDepth(existingDepth) >= color.r - 0.0001 {
+				return encodeDepth(color.r)
+			}
+
+			return vec4(0.0, 0.0, 0.0, 0.0)
+
+		}
+
+		`,
+	)
+
+	cam.depthEqualShader, err = ebiten.NewShader(depthEqualShaderText)
+
+	if err != nil {
+		panic(err)
+	}
+
 	clipAlphaShaderText := []byte(
 		`package main
 
@@ -249,6 +521,43 @@ func (camera *Camera) Clone() INode {
 	clone := NewCamera(w, h)
 
 	clone.RenderDepth = camera.RenderDepth
+	clone.HierarchicalCulling = camera.HierarchicalCulling
+	clone.SortOpaqueTriangles = camera.SortOpaqueTriangles
+	clone.BatchByMaterial = camera.BatchByMaterial
+	clone.DepthPrePass = camera.DepthPrePass
+	clone.CoarseSorting = camera.CoarseSorting
+	clone.RenderPicking = camera.RenderPicking
+	clone.RecordingScale = camera.RecordingScale
+
+	clone.AdaptiveResolution.On = camera.AdaptiveResolution.On
+	clone.AdaptiveResolution.FrameTimeBudget = camera.AdaptiveResolution.FrameTimeBudget
+	clone.AdaptiveResolution.HeadroomRatio = camera.AdaptiveResolution.HeadroomRatio
+	clone.AdaptiveResolution.MinScale = camera.AdaptiveResolution.MinScale
+	clone.AdaptiveResolution.Step = camera.AdaptiveResolution.Step
+	clone.AdaptiveResolution.AdjustInterval = camera.AdaptiveResolution.AdjustInterval
+
+	clone.FrameBudget.On = camera.FrameBudget.On
+	clone.FrameBudget.Budget = camera.FrameBudget.Budget
+	clone.FrameBudget.HeadroomRatio = camera.FrameBudget.HeadroomRatio
+	clone.FrameBudget.MinDetail = camera.FrameBudget.MinDetail
+	clone.FrameBudget.Step = camera.FrameBudget.Step
+	clone.FrameBudget.OnDetailChange = camera.FrameBudget.OnDetailChange
+	clone.FrameBudget.ShouldRender = camera.FrameBudget.ShouldRender
+
+	clone.AmbientOcclusion.On = camera.AmbientOcclusion.On
+	clone.AmbientOcclusion.Quality = camera.AmbientOcclusion.Quality
+	clone.AmbientOcclusion.Radius = camera.AmbientOcclusion.Radius
+	clone.AmbientOcclusion.Strength = camera.AmbientOcclusion.Strength
+	clone.AmbientOcclusion.Bias = camera.AmbientOcclusion.Bias
+	clone.AmbientOcclusion.Range = camera.AmbientOcclusion.Range
+
+	clone.ColorGrading.On = camera.ColorGrading.On
+	clone.ColorGrading.Exposure = camera.ColorGrading.Exposure
+	clone.ColorGrading.Contrast = camera.ColorGrading.Contrast
+	clone.ColorGrading.Saturation = camera.ColorGrading.Saturation
+	clone.ColorGrading.Gamma = camera.ColorGrading.Gamma
+	clone.ColorGrading.LUT = camera.ColorGrading.LUT
+
 	clone.Near = camera.Near
 	clone.Far = camera.Far
 	clone.Perspective = camera.Perspective
@@ -258,8 +567,8 @@ func (camera *Camera) Clone() INode {
 	clone.AccumulateDrawOptions = camera.AccumulateDrawOptions
 
 	clone.Node = camera.Node.Clone().(*Node)
-	for _, child := range camera.children {
-		child.setParent(camera)
+	for _, child := range clone.children {
+		child.setParent(clone)
 	}
 
 	return clone
@@ -267,6 +576,131 @@ func (camera *Camera) Clone() INode {
 }
 
 func (camera *Camera) Resize(w, h int) {
+	camera.baseWidth = w
+	camera.baseHeight = h
+	// The Camera's own textures aren't in use while it has a render target, so they're resized when the last target is popped.
+	if len(camera.renderTargets) > 0 {
+		camera.resizePending = true
+		return
+	}
+	camera.resizeTextures(int(float64(w)*camera.renderScale), int(float64(h)*camera.renderScale))
+}
+
+// SetPerformancePreset sets a number of the Camera's performance-related settings at once according to the preset provided (i.e.
+// PerformancePresetDefault or PerformancePresetLowSpec). Settings can still be changed individually afterwards.
+func (camera *Camera) SetPerformancePreset(preset int) {
+
+	switch preset {
+
+	case PerformancePresetDefault:
+		camera.RenderDepth = true
+		camera.SortOpaqueTriangles = true
+		camera.CoarseSorting = false
+		camera.BatchByMaterial = false
+		camera.DepthPrePass = false
+		camera.HierarchicalCulling = true
+		camera.AdaptiveResolution.On = false
+		camera.SetRenderScale(1)
+
+	case PerformancePresetLowSpec:
+		camera.RenderDepth = false
+		camera.SortOpaqueTriangles = true
+		camera.CoarseSorting = true
+		camera.BatchByMaterial = true
+		camera.DepthPrePass = false
+		camera.HierarchicalCulling = true
+		camera.AdaptiveResolution.On = true
+
+	default:
+		panic("Error: Camera.SetPerformancePreset() called with an unknown preset.")
+
+	}
+
+}
+
+// RenderScale returns the Camera's current internal render scale, which can change if Camera.AdaptiveResolution is on. The Camera's
+// textures are the size set with NewCamera() or Camera.Resize() multiplied by this value.
+func (camera *Camera) RenderScale() float64 {
+	return camera.renderScale
+}
+
+// SetRenderScale sets the Camera's internal render scale, resizing its textures to the size set with NewCamera() or Camera.Resize()
+// multiplied by the provided scale. The scale is clamped to be between 0 and 1.
+func (camera *Camera) SetRenderScale(scale float64) {
+
+	if scale > 1 {
+		scale = 1
+	} else if scale <= 0 {
+		scale = 0.01
+	}
+
+	// While the Camera has a render target, it renders at the target's size, so the scale is restored when the last target is popped.
+	if len(camera.renderTargets) > 0 {
+		camera.renderTargets[0].renderScale = scale
+	} else {
+		camera.renderScale = scale
+	}
+
+	camera.Resize(camera.baseWidth, camera.baseHeight)
+
+}
+
+// updateAdaptiveResolution measures the frame time and adjusts the render scale if the Camera's adaptive resolution is on.
+func (camera *Camera) updateAdaptiveResolution() {
+
+	ar := &camera.AdaptiveResolution
+
+	if !ar.On || len(camera.renderTargets) > 0 {
+		ar.lastClear = time.Time{}
+		return
+	}
+
+	now := time.Now()
+
+	if !ar.lastClear.IsZero() {
+		ar.frameTime += now.Sub(ar.lastClear)
+		ar.frameCount++
+	}
+
+	ar.lastClear = now
+
+	if ar.lastAdjust.IsZero() {
+		ar.lastAdjust = now
+	}
+
+	if now.Sub(ar.lastAdjust) < ar.AdjustInterval || ar.frameCount == 0 {
+		return
+	}
+
+	avg := ar.frameTime / time.Duration(ar.frameCount)
+
+	ar.frameTime = 0
+	ar.frameCount = 0
+	ar.lastAdjust = now
+
+	scale := camera.renderScale
+
+	if avg > ar.FrameTimeBudget {
+		scale = math.Max(scale-ar.Step, ar.MinScale)
+	} else if float64(avg) < float64(ar.FrameTimeBudget)*ar.HeadroomRatio {
+		scale = math.Min(scale+ar.Step, 1)
+	}
+
+	if scale != camera.renderScale {
+		camera.SetRenderScale(scale)
+	}
+
+}
+
+func (camera *Camera) resizeTextures(w, h int) {
+
+	if w < 1 {
+		w = 1
+	}
+
+	if h < 1 {
+		h = 1
+	}
 
 	if camera.resultColorTexture != nil {
 
@@ -283,6 +717,13 @@ func (camera *Camera) Resize(w, h int) {
 		camera.depthIntermediate.Dispose()
 		camera.clipAlphaIntermediate.Dispose()
 		camera.clipBehind.Dispose()
+
+		if camera.pickingTexture != nil {
+			camera.pickingTexture.Dispose()
+			camera.pickingTexture = nil
+		}
+
+		camera.renderBuffers.disposeShaderDepthTextures()
 	}
 
 	camera.resultAccumulatedColorTexture = ebiten.NewImage(w, h)
@@ -345,10 +786,10 @@ func (camera *Camera) SetOrthographic(orthoScale float64) {
 }
 
 // We do this for each vertex for each triangle for each model, so we want to avoid allocating vectors if possible. clipToScreen
-// does this by taking outVec, a vertex (vector.Vector) that it stores the values in and returns, which avoids reallocation.
-func (camera *Camera) clipToScreen(vert, outVec vector.Vector, vertID int, mat *Material, width, height float64) vector.Vector {
+// does this by taking and returning Vector4 values, which avoids allocation (unless the Material has a VertexClipFunction).
+func (camera *Camera) clipToScreen(vert Vector4, vertID int, mat *Material, width, height float64) Vector4 {
 
-	v3 := vert[3]
+	v3 := vert.W
 
 	if !camera.Perspective {
 		v3 = 1.0
@@ -362,13 +803,15 @@ func (camera *Camera) clipToScreen(vert, outVec vector.Vector, vertID int, mat *
 
 	// Again, this function should only be called with pre-transformed 4D vertex arguments.
 
-	outVec[0] = (vert[0]/v3)*width + (width / 2)
-	outVec[1] = (vert[1]/v3*-1)*height + (height / 2)
-	outVec[2] = vert[2] / v3
-	outVec[3] = 1
+	outVec := Vector4{
+		X: (vert.X/v3)*width + (width / 2),
+		Y: (vert.Y/v3*-1)*height + (height / 2),
+		Z: vert.Z / v3,
+		W: 1,
+	}
 
 	if mat != nil && mat.VertexClipFunction != nil {
-		outVec = mat.VertexClipFunction(outVec, vertID)
+		outVec = NewVector4FromVector(mat.VertexClipFunction(outVec.ToVector(), vertID))
 	}
 
 	return outVec
@@ -378,7 +821,7 @@ func (camera *Camera) clipToScreen(vert, outVec vector.Vector, vertID int, mat *
 // ClipToScreen projects the pre-transformed vertex in View space and remaps it to screen coordinates.
 func (camera *Camera) ClipToScreen(vert vector.Vector) vector.Vector {
 	width, height := camera.resultColorTexture.Size()
-	return camera.clipToScreen(vert, vector.Vector{0, 0, 0, 0}, -1, nil, float64(width), float64(height))
+	return camera.clipToScreen(NewVector4FromVector(vert), -1, nil, float64(width), float64(height)).ToVector()
 }
 
 // WorldToScreen transforms a 3D position in the world to screen coordinates.
@@ -447,11 +890,13 @@ func (camera *Camera) PointInFrustum(point vector.Vector) bool {
 
 // SphereInFrustum returns true if the sphere would be visible through the camera frustum.
 func (camera *Camera) SphereInFrustum(sphere *BoundingSphere) bool {
+	return camera.sphereInFrustum(NewVector3FromVector(sphere.WorldPosition()), sphere.WorldRadius())
+}
 
-	radius := sphere.WorldRadius()
+func (camera *Camera) sphereInFrustum(center Vector3, radius float64) bool {
 
-	diff := fastVectorSub(sphere.WorldPosition(), camera.WorldPosition())
-	pcZ := diff.Dot(camera.cameraForward)
+	diff := center.Sub(NewVector3FromVector(camera.WorldPosition()))
+	pcZ := diff.Dot(NewVector3FromVector(camera.cameraForward))
 
 	if pcZ > camera.Far+radius || pcZ < camera.Near-radius {
 		return false
@@ -462,7 +907,7 @@ func (camera *Camera) SphereInFrustum(sphere *BoundingSphere) bool {
 		d := camera.sphereFactorY * radius
 		pcZ *= camera.sphereFactorTang
 
-		pcY := diff.Dot(camera.cameraUp)
+		pcY := diff.Dot(NewVector3FromVector(camera.cameraUp))
 
 		if pcY > pcZ+d || pcY < -pcZ-d {
 			return false
@@ -471,7 +916,7 @@ func (camera *Camera) SphereInFrustum(sphere *BoundingSphere) bool {
 		pcZ *= camera.AspectRatio()
 		d = camera.sphereFactorX * radius
 
-		pcX := diff.Dot(camera.cameraRight)
+		pcX := diff.Dot(NewVector3FromVector(camera.cameraRight))
 
 		if pcX > pcZ+d || pcX < -pcZ-d {
 			return false
@@ -482,13 +927,13 @@ func (camera *Camera) SphereInFrustum(sphere *BoundingSphere) bool {
 		width := camera.OrthoScale
 		height := width / camera.AspectRatio()
 
-		pcY := diff.Dot(camera.cameraUp)
+		pcY := diff.Dot(NewVector3FromVector(camera.cameraUp))
 
 		if -height/2-radius > pcY || pcY > height/2+radius {
 			return false
 		}
 
-		pcX := diff.Dot(camera.cameraRight)
+		pcX := diff.Dot(NewVector3FromVector(camera.cameraRight))
 
 		if -width/2-radius > pcX || pcX > width/2+radius {
 			return false
@@ -500,6 +945,55 @@ func (camera *Camera) SphereInFrustum(sphere *BoundingSphere) bool {
 
 }
 
+// FrustumCorners returns the eight world-space corners of the Camera's view volume between the near and far distances provided (which don't have to
+// match the Camera's own Near and Far values, so you can, for example, fit a shadow volume to just the first few units in front of the Camera). The
+// corners are ordered bottom-left, bottom-right, top-right, and top-left for the near end first, and then the same for the far end.
+func (camera *Camera) FrustumCorners(near, far float64) [8]vector.Vector {
+
+	rot := camera.WorldRotation()
+	pos := NewVector3FromVector(camera.WorldPosition())
+	right := NewVector3FromVector(rot.Right())
+	up := NewVector3FromVector(rot.Up())
+	forward := NewVector3FromVector(rot.Forward()).Invert()
+	aspectRatio := camera.AspectRatio()
+
+	corners := [8]vector.Vector{}
+
+	for i, dist := range []float64{near, far} {
+
+		var halfWidth, halfHeight float64
+
+		if camera.Perspective {
+			halfHeight = dist * math.Tan(camera.FieldOfView*math.Pi/360)
+			halfWidth = halfHeight * aspectRatio
+		} else {
+			// OrthoScale is the full width of the view: Projection() maps -OrthoScale to OrthoScale onto clip-space X from -1 to 1,
+			// but clipToScreen() only shows clip-space X from -0.5 to 0.5, so the view extends OrthoScale/2 to each side.
+			halfWidth = camera.OrthoScale / 2
+			halfHeight = halfWidth / aspectRatio
+		}
+
+		center := pos.Add(forward.Scale(dist))
+		r := right.Scale(halfWidth)
+		u := up.Scale(halfHeight)
+
+		corners[i*4] = center.Sub(r).Sub(u).ToVector()
+		corners[i*4+1] = center.Add(r).Sub(u).ToVector()
+		corners[i*4+2] = center.Add(r).Add(u).ToVector()
+		corners[i*4+3] = center.Sub(r).Add(u).ToVector()
+
+	}
+
+	return corners
+
+}
+
+// Frustum returns the Camera's view frustum in world space as a Frustum. The Frustum is extracted from the Camera's view-projection matrix
+// (see Matrix4.FrustumPlanes()), so it matches what the Camera renders.
+func (camera *Camera) Frustum() Frustum {
+	return NewFrustumFromMatrix(camera.ViewMatrix().Mult(camera.Projection()))
+}
+
 // AspectRatio returns the camera's aspect ratio (width / height).
 func (camera *Camera) AspectRatio() float64 {
 	w, h := camera.resultColorTexture.Size()
@@ -507,9 +1001,20 @@ func (camera *Camera) AspectRatio() float64 {
 }
 
 // Clear should be called at the beginning of a single rendered frame and clears the Camera's backing textures before rendering.
-// It also resets the debug values.
+// It also resets the debug values, and if the Camera is recording (see Camera.StartRecording()), captures the previous frame's render.
 func (camera *Camera) Clear() {
 
+	if camera.recorder != nil && len(camera.renderTargets) == 0 {
+		camera.recorder.capture(camera.ColorTexture())
+	}
+
+	camera.updateAdaptiveResolution()
+
+	camera.FrameBudget.update()
+
+	// The previous frame has been drawn by now, so the uniform data passed to custom fragment shaders can be reused.
+	camera.renderBuffers.uniformData = camera.renderBuffers.uniformData[:0]
+
 	if camera.AccumulateColorMode != AccumlateColorModeNone {
 		camera.accumulatedBackBuffer.Clear()
 		camera.accumulatedBackBuffer.DrawImage(camera.resultAccumulatedColorTexture, nil)
@@ -532,6 +1037,25 @@ func (camera *Camera) Clear() {
 		camera.resultDepthTexture.Clear()
 	}
 
+	camera.AmbientOcclusion.dirty = true
+	camera.ColorGrading.dirty = true
+
+	if camera.RenderPicking {
+
+		if camera.pickingTexture == nil {
+			w, h := camera.resultColorTexture.Size()
+			camera.pickingTexture = ebiten.NewImage(w, h)
+			camera.pickingIDs = map[INode]int{}
+		}
+
+		camera.pickingTexture.Clear()
+		camera.pickingNodes = camera.pickingNodes[:0]
+		for node := range camera.pickingIDs {
+			delete(camera.pickingIDs, node)
+		}
+
+	}
+
 	if time.Since(camera.DebugInfo.tickTime).Milliseconds() >= 100 {
 
 		if !camera.DebugInfo.tickTime.IsZero() {
@@ -558,6 +1082,8 @@ func (camera *Camera) Clear() {
 	camera.DebugInfo.LightCount = 0
 	camera.DebugInfo.ActiveLightCount = 0
 
+	camera.RenderStats = RenderStats{}
+
 	cameraRot := camera.WorldRotation()
 	camera.cameraForward = cameraRot.Forward().Invert()
 	camera.cameraRight = cameraRot.Right()
@@ -567,149 +1093,662 @@ func (camera *Camera) Clear() {
 
 // RenderNodes renders all nodes starting with the provided rootNode using the Scene's properties (fog, for example). Note that if Camera.RenderDepth
 // is false, scenes rendered one after another in multiple RenderNodes() calls will be rendered on top of each other in the Camera's texture buffers.
-// Note that for Models, each MeshPart of a Model has a maximum renderable triangle count of 21845.
+// Note that for Models, each MeshPart of a Model has a maximum renderable triangle count of 21845. If the Scene's Octree is on and rootNode is the
+// Scene's Root, the Octree is used to cull the Scene; otherwise, the tree is culled hierarchically if Camera.HierarchicalCulling is on.
 func (camera *Camera) RenderNodes(scene *Scene, rootNode INode) {
 
-	meshes := []*Model{}
+	buffers := camera.renderBuffers
 
-	if model, isModel := rootNode.(*Model); isModel {
-		meshes = append(meshes, model)
+	// The factors used for frustum culling are calculated along with the projection matrix, so this makes sure they're up to date before culling.
+	camera.Projection()
+
+	if scene.Octree.On && rootNode == scene.Root {
+		scene.Octree.update(rootNode)
+		buffers.models = append(buffers.models[:0], scene.Octree.unculled.models...)
+		buffers.models = camera.appendOctreeModels(buffers.models, scene.Octree.root)
+	} else if camera.HierarchicalCulling {
+		buffers.models = camera.appendUnculledModels(buffers.models[:0], rootNode, true)
+	} else {
+		buffers.models = appendModelsRecursive(buffers.models[:0], rootNode)
 	}
 
-	nodes := rootNode.ChildrenRecursive()
+	camera.Render(scene, buffers.models...)
+
+}
+
+// appendUnculledModels appends the node (if it's a Model) and all Models in its subtree to the provided slice, returning the result. Unlike
+// appendModelsRecursive(), it skips any branches of the tree that are entirely outside of the Camera's view frustum.
+func (camera *Camera) appendUnculledModels(models []*Model, node INode, isRoot bool) []*Model {
+
+	// Leaf Models are culled individually when rendering; the root node is skipped as it presumably contains the whole scene.
+	if !isRoot && len(rawChildren(node)) > 0 {
 
-	for _, node := range nodes {
-		if model, ok := node.(*Model); ok {
-			meshes = append(meshes, model)
+		bounds := updateSubtreeBounds(node)
+
+		if bounds.empty {
+			return models
+		}
+
+		if !bounds.unculled && !camera.sphereInFrustum(bounds.center, bounds.radius) {
+			camera.RenderStats.ModelsCulled += bounds.models
+			return models
 		}
+
 	}
 
-	camera.Render(scene, meshes...)
+	if model, isModel := node.(*Model); isModel {
+		models = append(models, model)
+	}
 
-}
+	for _, child := range rawChildren(node) {
+		models = camera.appendUnculledModels(models, child, false)
+	}
+
+	return models
 
-type renderPair struct {
-	Model    *Model
-	MeshPart *MeshPart
 }
 
-// Render renders all of the models passed using the provided Scene's properties (fog, for example). Note that if Camera.RenderDepth
-// is false, scenes rendered one after another in multiple Render() calls will be rendered on top of each other in the Camera's texture buffers.
-// Note that for Models, each MeshPart of a Model has a maximum renderable triangle count of 21845.
-func (camera *Camera) Render(scene *Scene, models ...*Model) {
+// appendModelsRecursive appends the node (if it's a Model) and all Models in its subtree to the provided slice, returning the result. This is
+// used instead of Node.ChildrenRecursive() when rendering to avoid allocating new slices each frame.
+func appendModelsRecursive(models []*Model, node INode) []*Model {
 
-	frametimeStart := time.Now()
+	if model, isModel := node.(*Model); isModel {
+		models = append(models, model)
+	}
 
-	lights := []Light{}
+	for _, child := range rawChildren(node) {
+		models = appendModelsRecursive(models, child)
+	}
 
-	if scene.LightingOn {
+	return models
 
-		for _, l := range scene.Root.ChildrenRecursive() {
-			if light, isLight := l.(Light); isLight {
-				camera.DebugInfo.LightCount++
-				if light.isOn() {
-					lights = append(lights, light)
-					light.beginRender()
-					camera.DebugInfo.ActiveLightCount++
-				}
-			}
-		}
+}
 
+// appendLightsRecursive appends all Lights in the subtree of the given node to the provided slice, returning the result.
+func appendLightsRecursive(lights []Light, node INode) []Light {
+
+	for _, child := range rawChildren(node) {
+		if light, isLight := child.(Light); isLight {
+			lights = append(lights, light)
+		}
+		lights = appendLightsRecursive(lights, child)
 	}
 
-	// By multiplying the camera's position against the view matrix (which contains the negated camera position), we're left with just the rotation
-	// matrix, which we feed into model.TransformedVertices() to draw vertices in order of distance.
-	vpMatrix := camera.ViewMatrix().Mult(camera.Projection())
+	return lights
 
-	rectShaderOptions := &ebiten.DrawRectShaderOptions{}
-	rectShaderOptions.Images[0] = camera.colorIntermediate
-	rectShaderOptions.Images[1] = camera.depthIntermediate
+}
 
-	if scene != nil {
+// appendVolumesRecursive appends all ReflectionProbes and DarknessVolumes in the subtree of the given node to the renderBuffers' slices.
+func (buffers *renderBuffers) appendVolumesRecursive(node INode) {
 
-		rectShaderOptions.Uniforms = map[string]interface{}{
-			"Fog":      scene.fogAsFloatSlice(),
-			"FogRange": scene.FogRange,
+	for _, child := range rawChildren(node) {
+		switch volume := child.(type) {
+		case *ReflectionProbe:
+			buffers.probes = append(buffers.probes, volume)
+		case *DarknessVolume:
+			buffers.darknessVolumes = append(buffers.darknessVolumes, volume)
 		}
+		buffers.appendVolumesRecursive(child)
+	}
 
-	} else {
+}
 
-		rectShaderOptions.Uniforms = map[string]interface{}{
-			"Fog":      []float32{0, 0, 0, 0},
-			"FogRange": []float32{0, 1},
-		}
+// modelDarknessVolumes returns the DarknessVolumes that are on and could overlap the given Model, using the renderBuffers' slices to avoid allocating.
+func (buffers *renderBuffers) modelDarknessVolumes(model *Model) []*DarknessVolume {
 
+	buffers.modelVolumes = buffers.modelVolumes[:0]
+
+	if len(buffers.darknessVolumes) == 0 {
+		return buffers.modelVolumes
 	}
 
-	// Reusing vectors rather than reallocating for all triangles for all models
-	p0 := vector.Vector{0, 0, 0, 0}
-	p1 := vector.Vector{0, 0, 0, 0}
-	p2 := vector.Vector{0, 0, 0, 0}
+	center := NewVector3FromVector(model.BoundingSphere.WorldPosition())
+	radius := model.BoundingSphere.WorldRadius()
 
-	solids := []renderPair{}
-	transparents := []renderPair{}
+	for _, volume := range buffers.darknessVolumes {
+		if volume.On && volume.overlapsSphere(center, radius) {
+			buffers.modelVolumes = append(buffers.modelVolumes, volume)
+		}
+	}
 
-	depths := map[*Model]float64{}
+	return buffers.modelVolumes
 
-	sorting := len(solids) > 0 && !camera.RenderDepth
+}
 
-	for _, model := range models {
+// nearestProbe returns the nearest captured ReflectionProbe whose Radius the given Model is within, or nil if there isn't one.
+func (buffers *renderBuffers) nearestProbe(model *Model) *ReflectionProbe {
 
-		if len(model.DynamicBatchModels) > 0 {
+	var nearest *ReflectionProbe
+	nearestDist := 0.0
 
-			dynamicDepths := map[*Model]float64{}
+	center := model.WorldPosition()
 
-			transparent := false
+	for _, probe := range buffers.probes {
 
-			for _, child := range model.DynamicBatchModels {
+		if !probe.Captured() {
+			continue
+		}
 
-				dynamicDepths[child] = camera.WorldToScreen(child.WorldPosition())[2]
+		dist := fastVectorDistanceSquared(probe.WorldPosition(), center)
 
-				for _, mp := range child.Mesh.MeshParts {
-					if child.isTransparent(mp) {
-						transparent = true
-					}
-				}
-			}
+		if dist <= probe.Radius*probe.Radius && (nearest == nil || dist < nearestDist) {
+			nearest = probe
+			nearestDist = dist
+		}
 
-			sort.SliceStable(model.DynamicBatchModels, func(i, j int) bool {
-				return dynamicDepths[model.DynamicBatchModels[i]] > dynamicDepths[model.DynamicBatchModels[j]]
-			})
+	}
 
-			if transparent {
-				transparents = append(transparents, renderPair{model, model.Mesh.MeshParts[0]})
-			} else {
-				solids = append(solids, renderPair{model, model.Mesh.MeshParts[0]})
-			}
+	return nearest
 
-		} else if model.DynamicBatchOwner == nil && model.Mesh != nil {
+}
 
-			for _, mp := range model.Mesh.MeshParts {
-				if model.isTransparent(mp) {
-					transparents = append(transparents, renderPair{model, mp})
-				} else {
-					solids = append(solids, renderPair{model, mp})
-				}
-			}
+// nearestLights returns the Lights provided that should light the given Model. If the Model's MaxLights is above 0 and there are more Lights
+// than that, only the nearest ones are returned, using the renderBuffers' slices to avoid allocating.
+func (buffers *renderBuffers) nearestLights(model *Model, lights []Light) []Light {
 
-			if sorting {
-				depths[model] = camera.WorldToScreen(model.WorldPosition())[2]
-			}
+	if model.MaxLights <= 0 || len(lights) <= model.MaxLights {
+		return lights
+	}
 
-		}
+	buffers.modelLights = append(buffers.modelLights[:0], lights...)
+	buffers.lightDists = buffers.lightDists[:0]
 
+	center := model.WorldPosition()
+
+	for _, light := range lights {
+		dist := 0.0
+		// Ambient and directional lights light everything equally, so only point lights have a distance.
+		if point, ok := light.(*PointLight); ok {
+			dist = fastVectorDistanceSquared(point.WorldPosition(), center)
+		}
+		buffers.lightDists = append(buffers.lightDists, dist)
 	}
 
-	// If the camera isn't rendering depth, then we should sort models by distance to ensure things draw in something like the correct order
-	if sorting {
+	// MaxLights should be small, so a partial selection sort to just find the nearest Lights is fine here.
+	for i := 0; i < model.MaxLights; i++ {
 
-		sort.SliceStable(solids, func(i, j int) bool {
-			return depths[solids[i].Model] > depths[solids[j].Model]
-		})
+		nearest := i
 
-	}
+		for j := i + 1; j < len(buffers.modelLights); j++ {
+			if buffers.lightDists[j] < buffers.lightDists[nearest] {
+				nearest = j
+			}
+		}
 
-	camWidth, camHeight := camera.resultColorTexture.Size()
+		buffers.modelLights[i], buffers.modelLights[nearest] = buffers.modelLights[nearest], buffers.modelLights[i]
+		buffers.lightDists[i], buffers.lightDists[nearest] = buffers.lightDists[nearest], buffers.lightDists[i]
+
+	}
+
+	return buffers.modelLights[:model.MaxLights]
+
+}
+
+type renderPair struct {
+	Model    *Model
+	MeshPart *MeshPart
+}
+
+// renderBuffers holds slices, maps, and options that are reused from frame to frame when rendering, so that Camera.Render() doesn't need
+// to allocate them each time it's called.
+type renderBuffers struct {
+	models       []*Model
+	lights       []Light
+	activeLights []Light
+	modelLights  []Light
+	lightDists   []float64
+	solids       []renderPair
+	transparents []renderPair
+	depths       map[*Model]float64
+
+	probes          []*ReflectionProbe
+	darknessVolumes []*DarknessVolume
+	modelVolumes    []*DarknessVolume
+
+	colorVertices []ebiten.Vertex // The vertices of the triangles drawn in the current draw call
+	depthVertices []ebiten.Vertex // The vertices of the triangles drawn to the depth texture in the current draw call
+	indices       []uint16
+
+	renderPairSorter      *renderPairSorter
+	materialSorter        *materialSorter
+	modelDepthSorter      *modelDepthSorter
+	rectShaderOptions     *ebiten.DrawRectShaderOptions
+	trianglesOptions      *ebiten.DrawTrianglesOptions
+	depthShaderOptions    *ebiten.DrawTrianglesShaderOptions
+	clipShaderOptions     *ebiten.DrawRectShaderOptions
+	materialShaderOptions *ebiten.DrawTrianglesShaderOptions
+	pickingOptions        *ebiten.DrawImageOptions
+	shaderDepthOptions    *ebiten.DrawImageOptions
+	fog                   []float32
+	terrainFog            []float32
+
+	// uniformData backs the uniform slices passed to Materials' custom fragment shaders. Ebiten holds onto uniform slices until the frame is
+	// drawn, so each draw call needs its own; they're carved out of uniformData, which is reset when the Camera is cleared each frame.
+	uniformData []float32
+	// shaderDepthTextures holds copies of the Camera's depth texture, sized to match the images of Materials' custom fragment shaders.
+	shaderDepthTextures map[[2]int]*ebiten.Image
+}
+
+func newRenderBuffers() *renderBuffers {
+
+	buffers := &renderBuffers{
+		depths:                map[*Model]float64{},
+		renderPairSorter:      &renderPairSorter{},
+		materialSorter:        &materialSorter{materialOrder: map[*Material]int{}},
+		modelDepthSorter:      &modelDepthSorter{},
+		rectShaderOptions:     &ebiten.DrawRectShaderOptions{},
+		trianglesOptions:      &ebiten.DrawTrianglesOptions{},
+		depthShaderOptions:    &ebiten.DrawTrianglesShaderOptions{},
+		clipShaderOptions:     &ebiten.DrawRectShaderOptions{},
+		materialShaderOptions: &ebiten.DrawTrianglesShaderOptions{},
+		pickingOptions:        &ebiten.DrawImageOptions{},
+		shaderDepthOptions:    &ebiten.DrawImageOptions{},
+		fog:                   make([]float32, 4),
+		terrainFog:            make([]float32, 4),
+		shaderDepthTextures:   map[[2]int]*ebiten.Image{},
+	}
+
+	buffers.rectShaderOptions.Uniforms = map[string]interface{}{}
+	buffers.materialShaderOptions.Uniforms = map[string]interface{}{}
+
+	return buffers
+
+}
+
+// uniformSlice returns a slice of the given size to pass as a uniform value to a shader, reusing the buffers' uniform data from previous frames.
+func (buffers *renderBuffers) uniformSlice(size int) []float32 {
+
+	if len(buffers.uniformData)+size > cap(buffers.uniformData) {
+		// Slices carved out of the previous array are still in use, so we start a new one rather than copying it over.
+		buffers.uniformData = make([]float32, 0, (cap(buffers.uniformData)+size)*2)
+	}
+
+	start := len(buffers.uniformData)
+	buffers.uniformData = buffers.uniformData[:start+size]
+
+	return buffers.uniformData[start : start+size : start+size]
+
+}
+
+// matrixUniform returns the Matrix4 provided as a mat4 uniform value for a Kage shader. As Kage matrices are column-major while Tetra3D's
+// transform row vectors, the matrix's rows become columns, so that it transforms column vectors in the shader the same way.
+func (buffers *renderBuffers) matrixUniform(matrix Matrix4) []float32 {
+
+	uniform := buffers.uniformSlice(16)
+
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 4; col++ {
+			uniform[row*4+col] = float32(matrix[row][col])
+		}
+	}
+
+	return uniform
+
+}
+
+func (buffers *renderBuffers) disposeShaderDepthTextures() {
+	for size, texture := range buffers.shaderDepthTextures {
+		texture.Dispose()
+		delete(buffers.shaderDepthTextures, size)
+	}
+}
+
+// shaderStartTime is the time that the Time uniform passed to Materials' custom fragment shaders counts from.
+var shaderStartTime = time.Now()
+
+// materialShaderOptions returns the options to draw the Model provided with its Material's custom fragment shader, passing the standard
+// uniforms and depth texture (see Material.SetShader()) along with the Material's own. img is the texture the Model would otherwise be drawn with.
+func (camera *Camera) materialShaderOptions(model *Model, mat *Material, img *ebiten.Image, view, projection Matrix4) *ebiten.DrawTrianglesShaderOptions {
+
+	buffers := camera.renderBuffers
+	options := buffers.materialShaderOptions
+	uniforms := options.Uniforms
+
+	for name := range uniforms {
+		delete(uniforms, name)
+	}
+
+	if mat.FragmentShaderOptions != nil {
+		*options = *mat.FragmentShaderOptions
+	} else {
+		*options = ebiten.DrawTrianglesShaderOptions{}
+	}
+
+	options.Uniforms = uniforms
+
+	if options.Images[0] == nil {
+		options.Images[0] = img
+	}
+
+	if camera.RenderDepth && options.Images[3] == nil {
+
+		// All of a shader's images have to be the same size, so the depth texture is stretched to fit.
+		w, h := options.Images[0].Size()
+		size := [2]int{w, h}
+
+		depth, exists := buffers.shaderDepthTextures[size]
+		if !exists {
+			depth = ebiten.NewImage(w, h)
+			buffers.shaderDepthTextures[size] = depth
+		}
+
+		dw, dh := camera.resultDepthTexture.Size()
+		opt := buffers.shaderDepthOptions
+		*opt = ebiten.DrawImageOptions{CompositeMode: ebiten.CompositeModeCopy}
+		opt.GeoM.Scale(float64(w)/float64(dw), float64(h)/float64(dh))
+		depth.DrawImage(camera.resultDepthTexture, opt)
+
+		options.Images[3] = depth
+
+	}
+
+	cameraRange := buffers.uniformSlice(2)
+	cameraRange[0] = float32(camera.Near)
+	cameraRange[1] = float32(camera.Far)
+
+	uniforms["Time"] = float32(time.Since(shaderStartTime).Seconds())
+	uniforms["Model"] = buffers.matrixUniform(model.Transform())
+	uniforms["View"] = buffers.matrixUniform(view)
+	uniforms["Projection"] = buffers.matrixUniform(projection)
+	uniforms["Fog"] = buffers.rectShaderOptions.Uniforms["Fog"]
+	uniforms["FogRange"] = buffers.rectShaderOptions.Uniforms["FogRange"]
+	uniforms["CameraRange"] = cameraRange
+
+	if mat.FragmentShaderOptions != nil {
+		for name, value := range mat.FragmentShaderOptions.Uniforms {
+			uniforms[name] = value
+		}
+	}
+
+	for name, value := range mat.ShaderUniforms {
+		uniforms[name] = value
+	}
+
+	return options
+
+}
+
+// renderPairSorter sorts renderPairs in order of their Models' depths, from furthest to closest. It's used rather than sort.SliceStable()
+// because that allocates.
+type renderPairSorter struct {
+	pairs  []renderPair
+	depths map[*Model]float64
+}
+
+func (s *renderPairSorter) Len() int      { return len(s.pairs) }
+func (s *renderPairSorter) Swap(i, j int) { s.pairs[i], s.pairs[j] = s.pairs[j], s.pairs[i] }
+func (s *renderPairSorter) Less(i, j int) bool {
+	return s.depths[s.pairs[i].Model] > s.depths[s.pairs[j].Model]
+}
+
+// materialSorter sorts renderPairs so that MeshParts sharing Materials are grouped together, in order of each Material's first appearance.
+type materialSorter struct {
+	pairs         []renderPair
+	materialOrder map[*Material]int
+}
+
+func (s *materialSorter) Len() int      { return len(s.pairs) }
+func (s *materialSorter) Swap(i, j int) { s.pairs[i], s.pairs[j] = s.pairs[j], s.pairs[i] }
+func (s *materialSorter) Less(i, j int) bool {
+	return s.materialOrder[s.pairs[i].Model.Material(s.pairs[i].MeshPart)] < s.materialOrder[s.pairs[j].Model.Material(s.pairs[j].MeshPart)]
+}
+
+func (s *materialSorter) sort(pairs []renderPair) {
+
+	for mat := range s.materialOrder {
+		delete(s.materialOrder, mat)
+	}
+
+	for _, pair := range pairs {
+		mat := pair.Model.Material(pair.MeshPart)
+		if _, exists := s.materialOrder[mat]; !exists {
+			s.materialOrder[mat] = len(s.materialOrder)
+		}
+	}
+
+	s.pairs = pairs
+	sort.Stable(s)
+	s.pairs = nil
+
+}
+
+// canBatchRenderPairs returns if the next renderPair can be rendered in the same draw call as the current one (i.e. they share
+// a Material, MaterialOverride, and color blending results).
+func canBatchRenderPairs(current, next renderPair) bool {
+
+	mat := current.Model.Material(current.MeshPart)
+
+	if mat != next.Model.Material(next.MeshPart) || len(next.Model.DynamicBatchModels) > 0 {
+		return false
+	}
+
+	// Fragment shaders are passed the first Model's transform as the "Model" uniform, so different Models can't share a draw call using them.
+	if current.Model != next.Model && mat != nil && mat.fragmentShader != nil && mat.FragmentShaderOn {
+		return false
+	}
+
+	if current.Model.materialOverride(current.MeshPart) != next.Model.materialOverride(next.MeshPart) {
+		return false
+	}
+
+	currentColorM := current.Model.ColorBlendingFunc(current.Model, current.MeshPart)
+	nextColorM := next.Model.ColorBlendingFunc(next.Model, next.MeshPart)
+
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			if currentColorM.Element(i, j) != nextColorM.Element(i, j) {
+				return false
+			}
+		}
+	}
+
+	return true
+
+}
+
+// modelDepthSorter sorts Models in order of their depths, from furthest to closest.
+type modelDepthSorter struct {
+	models []*Model
+	depths map[*Model]float64
+}
+
+func (s *modelDepthSorter) Len() int           { return len(s.models) }
+func (s *modelDepthSorter) Swap(i, j int)      { s.models[i], s.models[j] = s.models[j], s.models[i] }
+func (s *modelDepthSorter) Less(i, j int) bool { return s.depths[s.models[i]] > s.depths[s.models[j]] }
+
+// PreallocateRenderBuffers preallocates the Camera's vertex and index buffers to hold the specified number of triangles, which should be the
+// most triangles the Camera renders in a single draw call (i.e. the triangle count of the largest MeshPart or dynamic batch it renders, capped at
+// MaxTrianglesPerMeshPart). The Camera reuses these buffers from frame to frame, growing them as necessary, so this is only useful to avoid
+// allocations (and so, garbage collection) in the first few frames of rendering, or when a larger Mesh suddenly comes into view.
+func (camera *Camera) PreallocateRenderBuffers(maxTriangles int) {
+	camera.renderBuffers.reserveVertices(maxTriangles * 3)
+}
+
+// reserveVertices grows the renderBuffers' vertex and index buffers to hold at least the number of vertices provided (up to Ebiten's maximum
+// for a draw call), keeping the vertices they already hold.
+func (buffers *renderBuffers) reserveVertices(count int) {
+
+	if count > ebiten.MaxIndicesNum {
+		count = ebiten.MaxIndicesNum
+	}
+
+	if len(buffers.indices) >= count {
+		return
+	}
+
+	// The buffers at least double in size when they grow, so growing them a vertex at a time doesn't reallocate each time.
+	size := len(buffers.indices) * 2
+	if size < count {
+		size = count
+	}
+	if size > ebiten.MaxIndicesNum {
+		size = ebiten.MaxIndicesNum
+	}
+
+	colorVertices := make([]ebiten.Vertex, size)
+	copy(colorVertices, buffers.colorVertices)
+	buffers.colorVertices = colorVertices
+
+	depthVertices := make([]ebiten.Vertex, size)
+	copy(depthVertices, buffers.depthVertices)
+	buffers.depthVertices = depthVertices
+
+	indices := make([]uint16, size)
+	copy(indices, buffers.indices)
+	buffers.indices = indices
+
+}
+
+// Render renders all of the models passed using the provided Scene's properties (fog, for example). Note that if Camera.RenderDepth
+// is false, scenes rendered one after another in multiple Render() calls will be rendered on top of each other in the Camera's texture buffers.
+// Note that for Models, each MeshPart of a Model has a maximum renderable triangle count of 21845.
+func (camera *Camera) Render(scene *Scene, models ...*Model) {
+
+	buffers := camera.renderBuffers
+
+	// Scenes without a World are rendered with the default settings.
+	world := scene.world()
+
+	colorVertexList := buffers.colorVertices
+	depthVertexList := buffers.depthVertices
+	indexList := buffers.indices
+
+	buffers.probes = buffers.probes[:0]
+	buffers.darknessVolumes = buffers.darknessVolumes[:0]
+	buffers.appendVolumesRecursive(scene.Root)
+
+	// ReflectionProbes waiting to be captured are captured before anything else, as capturing renders the Scene with their own Cameras.
+	for _, probe := range buffers.probes {
+		if probe.captureNeeded {
+			probe.Capture(scene)
+		}
+	}
+
+	for _, volume := range buffers.darknessVolumes {
+		if volume.On {
+			volume.beginRender()
+		}
+	}
+
+	stageBegin(ProfileStageRender)
+
+	frametimeStart := time.Now()
+
+	camera.AmbientOcclusion.dirty = true
+	camera.ColorGrading.dirty = true
+
+	lights := buffers.activeLights[:0]
+
+	if world.LightingOn {
+
+		buffers.lights = appendLightsRecursive(buffers.lights[:0], scene.Root)
+
+		if world.AmbientLight != nil {
+			buffers.lights = append(buffers.lights, world.AmbientLight)
+		}
+
+		for _, light := range buffers.lights {
+			camera.DebugInfo.LightCount++
+			if light.isOn() {
+				lights = append(lights, light)
+				light.beginRender()
+				camera.DebugInfo.ActiveLightCount++
+			}
+		}
+
+		buffers.activeLights = lights
+
+	}
+
+	camera.RenderStats.LightsProcessed += len(lights)
+
+	// By multiplying the camera's position against the view matrix (which contains the negated camera position), we're left with just the rotation
+	// matrix, which we feed into model.TransformedVertices() to draw vertices in order of distance.
+	viewMatrix := camera.ViewMatrix()
+	projection := camera.Projection()
+	vpMatrix := viewMatrix.Mult(projection)
+
+	rectShaderOptions := buffers.rectShaderOptions
+	rectShaderOptions.Images[0] = camera.colorIntermediate
+	rectShaderOptions.Images[1] = camera.depthIntermediate
+
+	buffers.fog = world.fogAsFloatSlice(buffers.fog)
+	rectShaderOptions.Uniforms["Fog"] = buffers.fog
+	rectShaderOptions.Uniforms["FogRange"] = world.FogRange
+
+	solids := buffers.solids[:0]
+	transparents := buffers.transparents[:0]
+
+	depths := buffers.depths
+	for model := range depths {
+		delete(depths, model)
+	}
+
+	sorting := len(solids) > 0 && !camera.RenderDepth
+
+	for _, model := range models {
+
+		if !camera.FrameBudget.shouldRender(camera, model) {
+			camera.RenderStats.ModelsSkipped++
+			continue
+		}
+
+		if len(model.DynamicBatchModels) > 0 {
+
+			transparent := false
+
+			for _, child := range model.DynamicBatchModels {
+
+				depths[child] = camera.WorldToScreen(child.WorldPosition())[2]
+
+				for _, mp := range child.Mesh.MeshParts {
+					if child.isTransparent(mp) {
+						transparent = true
+					}
+				}
+			}
+
+			buffers.modelDepthSorter.models = model.DynamicBatchModels
+			buffers.modelDepthSorter.depths = depths
+			sort.Stable(buffers.modelDepthSorter)
+			buffers.modelDepthSorter.models = nil
+
+			if transparent {
+				transparents = append(transparents, renderPair{model, model.Mesh.MeshParts[0]})
+			} else {
+				solids = append(solids, renderPair{model, model.Mesh.MeshParts[0]})
+			}
+
+		} else if model.DynamicBatchOwner == nil && model.Mesh != nil {
+
+			for _, mp := range model.Mesh.MeshParts {
+				if model.isTransparent(mp) {
+					transparents = append(transparents, renderPair{model, mp})
+				} else {
+					solids = append(solids, renderPair{model, mp})
+				}
+			}
+
+			if sorting {
+				depths[model] = camera.WorldToScreen(model.WorldPosition())[2]
+			}
+
+		}
+
+	}
+
+	buffers.solids = solids
+	buffers.transparents = transparents
+
+	buffers.renderPairSorter.depths = depths
+
+	// If the camera isn't rendering depth, then we should sort models by distance to ensure things draw in something like the correct order
+	if sorting {
+		buffers.renderPairSorter.pairs = solids
+		sort.Stable(buffers.renderPairSorter)
+	}
+
+	camWidth, camHeight := camera.resultColorTexture.Size()
 
 	far := camera.Far
 	near := camera.Near
@@ -718,17 +1757,27 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 		near = 0
 	}
 
+	// depthOnly is set when rendering the depth pre-pass.
+	depthOnly := false
+
+	// usesPrePass returns if the renderPair has its depth rendered in the depth pre-pass. Alpha clip, dynamically batched, and non-depth-tested
+	// MeshParts are rendered normally.
+	usesPrePass := func(rp renderPair) bool {
+		mat := rp.Model.Material(rp.MeshPart)
+		return camera.DepthPrePass && camera.RenderDepth && !rp.Model.isTransparent(rp.MeshPart) && len(rp.Model.DynamicBatchModels) == 0 && rp.Model.materialTransparencyMode(rp.MeshPart) != TransparencyModeAlphaClip && (mat == nil || mat.DepthTest)
+	}
+
 	render := func(rp renderPair) {
 
 		startingVertexListIndex := vertexListIndex
 
 		model := rp.Model
 		meshPart := rp.MeshPart
-		mat := meshPart.Material
+		mat := model.Material(meshPart)
 
-		lighting := scene.LightingOn
+		lighting := world.LightingOn && model.ReceivesLight && !depthOnly
 		if mat != nil {
-			lighting = scene.LightingOn && !mat.Shadeless
+			lighting = lighting && !mat.Shadeless
 		}
 
 		// Models without Meshes are essentially just "nodes" that just have a position. They aren't counted for rendering.
@@ -740,22 +1789,70 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 			return
 		}
 
-		camera.DebugInfo.TotalParts++
-		camera.DebugInfo.TotalTris += meshPart.TriangleCount()
+		if !depthOnly {
+			camera.DebugInfo.TotalParts++
+			camera.DebugInfo.TotalTris += meshPart.TriangleCount()
+		}
 
 		model.Transform()
 
-		if model.FrustumCulling {
+		// The first MeshPart of a Model stands in for the Model as a whole in the render statistics.
+		firstPart := len(model.Mesh.MeshParts) > 0 && model.Mesh.MeshParts[0] == meshPart
+
+		if model.frustumCulling {
 
 			if !camera.SphereInFrustum(model.BoundingSphere) {
+				if !depthOnly {
+					camera.RenderStats.MeshPartsCulled++
+					if firstPart {
+						camera.RenderStats.ModelsCulled++
+					}
+				}
 				return
 			}
 
 		}
 
-		camera.DebugInfo.DrawnParts++
+		if !depthOnly {
+			camera.DebugInfo.DrawnParts++
+			camera.RenderStats.MeshPartsRendered++
+			camera.RenderStats.TrianglesSubmitted += meshPart.TriangleCount()
+			if firstPart {
+				camera.RenderStats.ModelsRendered++
+			}
+		}
+
+		var probe *ReflectionProbe
+		var reflectivity float32
+		var darknessVolumes []*DarknessVolume
+		var modelTransform, modelRotation Matrix4
+		var cameraPosition Vector3
+
+		if !depthOnly && camera.flatColorFunc == nil {
+
+			if mat != nil && mat.Reflectivity > 0 && mat.BillboardMode == BillboardModeNone {
+				if probe = buffers.nearestProbe(model); probe != nil {
+					reflectivity = mat.Reflectivity
+				}
+			}
+
+			darknessVolumes = buffers.modelDarknessVolumes(model)
+
+			if probe != nil || len(darknessVolumes) > 0 {
+				modelTransform = model.Transform()
+				_, _, modelRotation = modelTransform.Decompose()
+				cameraPosition = NewVector3FromVector(camera.WorldPosition())
+			}
+
+		}
+
+		sortTime := camera.RenderStats.SortTime
+		transformStart := time.Now()
+
+		model.processVertices(vpMatrix, camera, meshPart, scene, probe != nil || len(darknessVolumes) > 0)
 
-		model.ProcessVertices(vpMatrix, camera, meshPart, scene)
+		// Sorting happens in ProcessVertices(), but is tracked separately.
+		camera.RenderStats.TransformTime += time.Since(transformStart) - (camera.RenderStats.SortTime - sortTime)
 
 		backfaceCulling := true
 		if mat != nil {
@@ -765,30 +1862,37 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 		srcW := 0.0
 		srcH := 0.0
 
-		if mat != nil && mat.Texture != nil {
-			srcW = float64(mat.Texture.Bounds().Dx())
-			srcH = float64(mat.Texture.Bounds().Dy())
-		}
-
-		var img *ebiten.Image
+		img := model.materialTexture(meshPart)
 
-		if mat != nil {
-			img = mat.Texture
+		if img != nil {
+			srcW = float64(img.Bounds().Dx())
+			srcH = float64(img.Bounds().Dy())
 		}
 
 		if img == nil {
 			img = defaultImg
 		}
 
+		terrainVertexColors := mat != nil && mat.TerrainMode == TerrainModeVertexColors && mat.fragmentShader != nil && mat.FragmentShaderOn
+
+		modelLights := lights
+
 		if lighting {
 
 			t := time.Now()
 
-			for _, light := range lights {
+			modelLights = buffers.nearestLights(model, lights)
+
+			if model.CacheLighting {
+				model.updateLightCache(modelLights)
+			}
+
+			for _, light := range modelLights {
 				light.beginModel(model, camera)
 			}
 
 			camera.DebugInfo.lightTime += time.Since(t)
+			camera.RenderStats.LightingTime += time.Since(t)
 
 		}
 
@@ -796,6 +1900,9 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 		// Here we do all vertex transforms first because of data locality (it's faster to access all vertex transformations, then go back and do all UV values, etc)
 
+		buffers.reserveVertices(vertexListIndex + len(meshPart.sortingTriangles)*3)
+		colorVertexList, depthVertexList, indexList = buffers.colorVertices, buffers.depthVertices, buffers.indices
+
 		for t := range meshPart.sortingTriangles {
 
 			meshPart.sortingTriangles[t].rendered = false
@@ -806,23 +1913,32 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 			v2 := mesh.vertexTransforms[vertIndex+2]
 
 			// Near-ish clipping (basically clip triangles that are wholly behind the camera)
-			if v0[3] < 0 && v1[3] < 0 && v2[3] < 0 {
+			if v0.W < 0 && v1.W < 0 && v2.W < 0 {
+				if !depthOnly {
+					camera.RenderStats.TrianglesClipped++
+				}
 				continue
 			}
 
-			if v0[2] > far && v1[2] > far && v2[2] > far {
+			if v0.Z > far && v1.Z > far && v2.Z > far {
+				if !depthOnly {
+					camera.RenderStats.TrianglesClipped++
+				}
 				continue
 			}
 
-			p0 = camera.clipToScreen(v0, p0, vertIndex, mat, float64(camWidth), float64(camHeight))
-			p1 = camera.clipToScreen(v1, p1, vertIndex+1, mat, float64(camWidth), float64(camHeight))
-			p2 = camera.clipToScreen(v2, p2, vertIndex+2, mat, float64(camWidth), float64(camHeight))
+			p0 := camera.clipToScreen(v0, vertIndex, mat, float64(camWidth), float64(camHeight))
+			p1 := camera.clipToScreen(v1, vertIndex+1, mat, float64(camWidth), float64(camHeight))
+			p2 := camera.clipToScreen(v2, vertIndex+2, mat, float64(camWidth), float64(camHeight))
 
 			// We can skip triangles that lie entirely outside of the view horizontally and vertically.
-			if (p0[0] < 0 && p1[0] < 0 && p2[0] < 0) ||
-				(p0[1] < 0 && p1[1] < 0 && p2[1] < 0) ||
-				(p0[0] > float64(camWidth) && p1[0] > float64(camWidth) && p2[0] > float64(camWidth)) ||
-				(p0[1] > float64(camHeight) && p1[1] > float64(camHeight) && p2[1] > float64(camHeight)) {
+			if (p0.X < 0 && p1.X < 0 && p2.X < 0) ||
+				(p0.Y < 0 && p1.Y < 0 && p2.Y < 0) ||
+				(p0.X > float64(camWidth) && p1.X > float64(camWidth) && p2.X > float64(camWidth)) ||
+				(p0.Y > float64(camHeight) && p1.Y > float64(camHeight) && p2.Y > float64(camHeight)) {
+				if !depthOnly {
+					camera.RenderStats.TrianglesCulled++
+				}
 				continue
 			}
 
@@ -834,12 +1950,14 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 			if backfaceCulling {
 
-				camera.backfacePool.Reset()
-				n0 := camera.backfacePool.Sub(p0, p1)[:3]
-				n1 := camera.backfacePool.Sub(p1, p2)[:3]
-				nor := camera.backfacePool.Cross(n0, n1)
+				n0 := p0.Vector3().Sub(p1.Vector3())
+				n1 := p1.Vector3().Sub(p2.Vector3())
+				nor := n0.Cross(n1)
 
-				if nor[2] > 0 {
+				if nor.Z > 0 {
+					if !depthOnly {
+						camera.RenderStats.TrianglesCulled++
+					}
 					continue
 				}
 
@@ -854,19 +1972,19 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 				}
 			}
 
-			colorVertexList[vertexListIndex].DstX = float32(p0[0])
-			colorVertexList[vertexListIndex].DstY = float32(p0[1])
-			colorVertexList[vertexListIndex+1].DstX = float32(p1[0])
-			colorVertexList[vertexListIndex+1].DstY = float32(p1[1])
-			colorVertexList[vertexListIndex+2].DstX = float32(p2[0])
-			colorVertexList[vertexListIndex+2].DstY = float32(p2[1])
+			colorVertexList[vertexListIndex].DstX = float32(p0.X)
+			colorVertexList[vertexListIndex].DstY = float32(p0.Y)
+			colorVertexList[vertexListIndex+1].DstX = float32(p1.X)
+			colorVertexList[vertexListIndex+1].DstY = float32(p1.Y)
+			colorVertexList[vertexListIndex+2].DstX = float32(p2.X)
+			colorVertexList[vertexListIndex+2].DstY = float32(p2.Y)
 
-			depthVertexList[vertexListIndex].DstX = float32(p0[0])
-			depthVertexList[vertexListIndex].DstY = float32(p0[1])
-			depthVertexList[vertexListIndex+1].DstX = float32(p1[0])
-			depthVertexList[vertexListIndex+1].DstY = float32(p1[1])
-			depthVertexList[vertexListIndex+2].DstX = float32(p2[0])
-			depthVertexList[vertexListIndex+2].DstY = float32(p2[1])
+			depthVertexList[vertexListIndex].DstX = float32(p0.X)
+			depthVertexList[vertexListIndex].DstY = float32(p0.Y)
+			depthVertexList[vertexListIndex+1].DstX = float32(p1.X)
+			depthVertexList[vertexListIndex+1].DstY = float32(p1.Y)
+			depthVertexList[vertexListIndex+2].DstX = float32(p2.X)
+			depthVertexList[vertexListIndex+2].DstY = float32(p2.Y)
 
 			meshPart.sortingTriangles[t].rendered = true
 
@@ -880,6 +1998,10 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 		vertexListIndex = startingVertexListIndex
 
+		if lighting {
+			stageBegin(ProfileStageLighting)
+		}
+
 		for _, tri := range meshPart.sortingTriangles {
 
 			if !tri.rendered {
@@ -901,7 +2023,9 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 				// Vertex colors
 
-				if activeChannel := mesh.VertexActiveColorChannel[vertIndex]; activeChannel >= 0 {
+				if depthOnly {
+					// Vertex colors aren't used when rendering only depth.
+				} else if activeChannel := mesh.VertexActiveColorChannel[vertIndex]; activeChannel >= 0 && camera.flatColorFunc == nil {
 					colorVertexList[vertexListIndex+i].ColorR = mesh.VertexColors[vertIndex][activeChannel].R
 					colorVertexList[vertexListIndex+i].ColorG = mesh.VertexColors[vertIndex][activeChannel].G
 					colorVertexList[vertexListIndex+i].ColorB = mesh.VertexColors[vertIndex][activeChannel].B
@@ -913,11 +2037,25 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 					colorVertexList[vertexListIndex+i].ColorA = 1
 				}
 
+				if terrainVertexColors && !depthOnly {
+					// Terrain layer weights are normalized so that the third can be derived from the first two, freeing up the blue channel
+					// for the fog amount; fog is applied by the terrain shader after blending the layers and lighting them.
+					vert := &colorVertexList[vertexListIndex+i]
+					if total := vert.ColorR + vert.ColorG + vert.ColorB; total > 0 {
+						vert.ColorR /= total
+						vert.ColorG /= total
+					} else {
+						vert.ColorR = 1.0 / 3
+						vert.ColorG = 1.0 / 3
+					}
+					vert.ColorB = 0
+				}
+
 				if camera.RenderDepth {
 
 					// We're adding 0.03 for a margin because for whatever reason, at close range / wide FOV,
 					// depth can be negative but still be in front of the camera and not behind it.
-					depth := (mesh.vertexTransforms[vertIndex][2]+near)/far + 0.03
+					depth := (mesh.vertexTransforms[vertIndex].Z+near)/far + 0.03
 					if depth < 0 {
 						depth = 0
 					} else if depth > 1 {
@@ -936,11 +2074,11 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 					// but when drawing textures 0 is the top, and the sourceHeight is the bottom.
 					depthVertexList[vertexListIndex+i].SrcY = v
 
-				} else if scene.FogMode != FogOff {
+				} else if world.FogMode != FogOff && !depthOnly {
 
 					// We're adding 0.03 for a margin because for whatever reason, at close range / wide FOV,
 					// depth can be negative but still be in front of the camera and not behind it.
-					depth := float32((mesh.vertexTransforms[vertIndex][2]+near)/far + 0.03)
+					depth := float32((mesh.vertexTransforms[vertIndex].Z+near)/far + 0.03)
 					if depth < 0 {
 						depth = 0
 					} else if depth > 1 {
@@ -949,16 +2087,18 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 					// depth = 1 - depth
 
-					depth = scene.FogRange[0] + ((scene.FogRange[1]-scene.FogRange[0])*1 - depth)
-
-					if scene.FogMode == FogAdd {
-						colorVertexList[vertexListIndex+i].ColorR += scene.FogColor.R * depth
-						colorVertexList[vertexListIndex+i].ColorG += scene.FogColor.G * depth
-						colorVertexList[vertexListIndex+i].ColorB += scene.FogColor.B * depth
-					} else if scene.FogMode == FogMultiply {
-						colorVertexList[vertexListIndex+i].ColorR *= scene.FogColor.R * depth
-						colorVertexList[vertexListIndex+i].ColorG *= scene.FogColor.G * depth
-						colorVertexList[vertexListIndex+i].ColorB *= scene.FogColor.B * depth
+					depth = world.FogRange[0] + ((world.FogRange[1]-world.FogRange[0])*1 - depth)
+
+					if terrainVertexColors {
+						colorVertexList[vertexListIndex+i].ColorB = depth
+					} else if world.FogMode == FogAdd {
+						colorVertexList[vertexListIndex+i].ColorR += world.FogColor.R * depth
+						colorVertexList[vertexListIndex+i].ColorG += world.FogColor.G * depth
+						colorVertexList[vertexListIndex+i].ColorB += world.FogColor.B * depth
+					} else if world.FogMode == FogMultiply {
+						colorVertexList[vertexListIndex+i].ColorR *= world.FogColor.R * depth
+						colorVertexList[vertexListIndex+i].ColorG *= world.FogColor.G * depth
+						colorVertexList[vertexListIndex+i].ColorB *= world.FogColor.B * depth
 					}
 
 				}
@@ -969,22 +2109,85 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 				t := time.Now()
 
-				addLightResults := [9]float32{}
+				addLightResults, cached := [9]float32{}, false
 
-				for _, light := range lights {
-					lightResults := light.Light(tri.ID, model)
-					for i := 0; i < 9; i++ {
-						addLightResults[i] += lightResults[i]
-					}
+				if model.CacheLighting {
+					addLightResults, cached = model.cachedLight(tri.ID)
 				}
 
-				for i := 0; i < 3; i++ {
-					colorVertexList[vertexListIndex+i].ColorR *= addLightResults[i*3]
-					colorVertexList[vertexListIndex+i].ColorG *= addLightResults[i*3+1]
-					colorVertexList[vertexListIndex+i].ColorB *= addLightResults[i*3+2]
+				if !cached {
+
+					for _, light := range modelLights {
+						lightResults := light.Light(tri.ID, model)
+						for i := 0; i < 9; i++ {
+							addLightResults[i] += lightResults[i]
+						}
+					}
+
+					if model.CacheLighting {
+						model.setCachedLight(tri.ID, addLightResults)
+					}
+
+				}
+
+				if terrainVertexColors {
+					// The vertex colors hold the terrain's layer weights, so the light's brightness goes in the alpha channel instead.
+					for i := 0; i < 3; i++ {
+						colorVertexList[vertexListIndex+i].ColorA *= (addLightResults[i*3] + addLightResults[i*3+1] + addLightResults[i*3+2]) / 3
+					}
+				} else {
+					for i := 0; i < 3; i++ {
+						colorVertexList[vertexListIndex+i].ColorR *= addLightResults[i*3]
+						colorVertexList[vertexListIndex+i].ColorG *= addLightResults[i*3+1]
+						colorVertexList[vertexListIndex+i].ColorB *= addLightResults[i*3+2]
+					}
 				}
 
 				camera.DebugInfo.lightTime += time.Since(t)
+				camera.RenderStats.LightingTime += time.Since(t)
+
+			}
+
+			if probe != nil || len(darknessVolumes) > 0 {
+
+				positions, normals := model.worldSpaceVertices()
+
+				for i := 0; i < 3; i++ {
+
+					vertIndex := tri.ID*3 + i
+
+					var vertPos, vertNormal Vector3
+
+					if positions != nil {
+						vertPos = positions[vertIndex]
+						vertNormal = normals[vertIndex]
+					} else {
+						vertPos = modelTransform.MultVec3(NewVector3FromVector(model.vertexPositions()[vertIndex]))
+						vertNormal = modelRotation.MultVec3(NewVector3FromVector(model.vertexNormals()[vertIndex])).Unit()
+					}
+
+					vert := &colorVertexList[vertexListIndex+i]
+
+					if probe != nil && !terrainVertexColors {
+						view := vertPos.Sub(cameraPosition).Unit()
+						r, g, b := probe.sample(view.Sub(vertNormal.Scale(2 * view.Dot(vertNormal))))
+						vert.ColorR += (r - vert.ColorR) * reflectivity
+						vert.ColorG += (g - vert.ColorG) * reflectivity
+						vert.ColorB += (b - vert.ColorB) * reflectivity
+					}
+
+					for _, volume := range darknessVolumes {
+						brightness := 1 - volume.darknessAt(volume.workingInverse.MultVec3(vertPos))
+						if terrainVertexColors {
+							vert.ColorA *= brightness
+						} else {
+							vert.ColorR *= brightness
+							vert.ColorG *= brightness
+							vert.ColorB *= brightness
+						}
+					}
+
+				}
 
 			}
 
@@ -992,6 +2195,10 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 		}
 
+		if lighting {
+			stageEnd(ProfileStageLighting)
+		}
+
 		for i := 0; i < vertexListIndex; i++ {
 			indexList[i] = uint16(i)
 		}
@@ -1004,15 +2211,15 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 			return
 		}
 
+		stageBegin(ProfileStageRasterize)
+
+		rasterizeStart := time.Now()
+
 		model := rp.Model
 		meshPart := rp.MeshPart
-		mat := meshPart.Material
+		mat := model.Material(meshPart)
 
-		var img *ebiten.Image
-
-		if mat != nil {
-			img = mat.Texture
-		}
+		img := model.materialTexture(meshPart)
 
 		if img == nil {
 			img = defaultImg
@@ -1039,46 +2246,141 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 			// image while also reading the DepthTexture, but unfortunately, images can't currently be different sizes in Ebiten.
 			// See: https://github.com/hajimehoshi/ebiten/issues/1870
 
-			transparencyMode := TransparencyModeOpaque
-
-			if mat != nil {
-				transparencyMode = mat.TransparencyMode
-			}
+			transparencyMode := model.materialTransparencyMode(meshPart)
 
 			camera.depthIntermediate.Clear()
 
+			// Materials that ignore depth testing compare against clipBehind, which is never drawn to, so all of their fragments pass.
+			depthTexture := camera.resultDepthTexture
+			if mat != nil && !mat.DepthTest {
+				depthTexture = camera.clipBehind
+			}
+
 			if transparencyMode == TransparencyModeAlphaClip {
 
 				camera.clipAlphaIntermediate.Clear()
 
-				camera.clipAlphaIntermediate.DrawTrianglesShader(depthVertexList[:vertexListIndex], indexList[:vertexListIndex], camera.clipAlphaRenderShader, &ebiten.DrawTrianglesShaderOptions{Images: [4]*ebiten.Image{img}})
+				shaderOpt := buffers.depthShaderOptions
+				shaderOpt.Images = [4]*ebiten.Image{img}
+
+				camera.clipAlphaIntermediate.DrawTrianglesShader(depthVertexList[:vertexListIndex], indexList[:vertexListIndex], camera.clipAlphaRenderShader, shaderOpt)
+				camera.RenderStats.DrawCalls++
 
 				w, h := camera.depthIntermediate.Size()
 
-				camera.depthIntermediate.DrawRectShader(w, h, camera.clipAlphaCompositeShader, &ebiten.DrawRectShaderOptions{Images: [4]*ebiten.Image{camera.resultDepthTexture, camera.clipAlphaIntermediate}})
+				clipOpt := buffers.clipShaderOptions
+				clipOpt.Images = [4]*ebiten.Image{depthTexture, camera.clipAlphaIntermediate}
+
+				camera.depthIntermediate.DrawRectShader(w, h, camera.clipAlphaCompositeShader, clipOpt)
+				camera.RenderStats.DrawCalls++
+
+			} else if !depthOnly && usesPrePass(rp) {
+
+				// The depth for this MeshPart has already been rendered in the pre-pass, so we only need to draw the
+				// fragments that weren't occluded, and don't have to write to the depth texture again.
+				shaderOpt := buffers.depthShaderOptions
+				shaderOpt.Images = [4]*ebiten.Image{camera.resultDepthTexture}
+
+				camera.depthIntermediate.DrawTrianglesShader(depthVertexList[:vertexListIndex], indexList[:vertexListIndex], camera.depthEqualShader, shaderOpt)
+				camera.RenderStats.DrawCalls++
 
 			} else {
-				shaderOpt := &ebiten.DrawTrianglesShaderOptions{
-					Images: [4]*ebiten.Image{camera.resultDepthTexture},
-				}
+				shaderOpt := buffers.depthShaderOptions
+				shaderOpt.Images = [4]*ebiten.Image{depthTexture}
 
 				camera.depthIntermediate.DrawTrianglesShader(depthVertexList[:vertexListIndex], indexList[:vertexListIndex], camera.depthShader, shaderOpt)
+				camera.RenderStats.DrawCalls++
 			}
 
-			if !model.isTransparent(meshPart) {
+			if !model.isTransparent(meshPart) && (depthOnly || !usesPrePass(rp)) {
 				camera.resultDepthTexture.DrawImage(camera.depthIntermediate, nil)
+				camera.RenderStats.DrawCalls++
+			}
+
+		}
+
+		// When rendering the depth pre-pass, we're done at this point.
+		if depthOnly {
+			vertexListIndex = 0
+			camera.RenderStats.RasterizeTime += time.Since(rasterizeStart)
+			stageEnd(ProfileStageRasterize)
+			return
+		}
+
+		if camera.RenderPicking && camera.pickingTexture != nil {
+
+			id, exists := camera.pickingIDs[model]
+			if !exists {
+				camera.pickingNodes = append(camera.pickingNodes, model)
+				id = len(camera.pickingNodes)
+				camera.pickingIDs[model] = id
 			}
 
+			// The ID is encoded in the red, green, and blue channels (0 being no Model).
+			r := float64((id>>16)&0xff) / 255
+			g := float64((id>>8)&0xff) / 255
+			b := float64(id&0xff) / 255
+
+			if camera.RenderDepth {
+
+				// The depth intermediate texture holds just the fragments of this MeshPart that passed the depth test, so we can use
+				// it as a mask, filling it with the ID color.
+				opt := buffers.pickingOptions
+				opt.ColorM.Reset()
+				opt.ColorM.Scale(0, 0, 0, 255)
+				opt.ColorM.Translate(r, g, b, 0)
+				camera.pickingTexture.DrawImage(camera.depthIntermediate, opt)
+
+			} else {
+
+				t := buffers.trianglesOptions
+				*t = ebiten.DrawTrianglesOptions{}
+				t.ColorM.Scale(0, 0, 0, 0)
+				t.ColorM.Translate(r, g, b, 1)
+				camera.pickingTexture.DrawTriangles(colorVertexList[:vertexListIndex], indexList[:vertexListIndex], img, t)
+
+			}
+
+			camera.RenderStats.DrawCalls++
+
 		}
 
-		t := &ebiten.DrawTrianglesOptions{}
+		t := buffers.trianglesOptions
+		*t = ebiten.DrawTrianglesOptions{}
 		t.ColorM = model.ColorBlendingFunc(model, meshPart) // Modify the model's appearance using its color blending function
 		if mat != nil {
 			t.Filter = mat.TextureFilterMode
 			t.Address = mat.TextureWrapMode
 		}
 
-		hasFragShader := mat != nil && mat.fragmentShader != nil && mat.FragmentShaderOn
+		if camera.flatColorFunc != nil {
+			// Keep the texture's alpha (so alpha clipping still cuts out shapes), but replace its color.
+			flat := camera.flatColorFunc(model)
+			t.ColorM.Reset()
+			t.ColorM.Scale(0, 0, 0, float64(flat.A))
+			t.ColorM.Translate(float64(flat.R), float64(flat.G), float64(flat.B), 0)
+		}
+
+		hasFragShader := mat != nil && mat.fragmentShader != nil && mat.FragmentShaderOn && camera.flatColorFunc == nil
+
+		var shaderOptions *ebiten.DrawTrianglesShaderOptions
+
+		if hasFragShader {
+			if mat.TerrainMode != TerrainModeNone {
+				// When rendering depth, fog is applied to the finished render afterwards, so the terrain shader shouldn't apply it.
+				for i := range buffers.terrainFog {
+					buffers.terrainFog[i] = 0
+				}
+				if !camera.RenderDepth && (world.FogMode == FogAdd || world.FogMode == FogMultiply) {
+					buffers.terrainFog[0] = world.FogColor.R
+					buffers.terrainFog[1] = world.FogColor.G
+					buffers.terrainFog[2] = world.FogColor.B
+					buffers.terrainFog[3] = float32(world.FogMode)
+				}
+				mat.updateTerrainUniforms(buffers.terrainFog)
+			}
+			shaderOptions = camera.materialShaderOptions(model, mat, img, viewMatrix, projection)
+		}
 		w, h := camera.resultColorTexture.Size()
 
 		// If rendering depth, and rendering through a custom fragment shader, we'll need to render the tris to the ColorIntermediate buffer using the custom shader.
@@ -1093,90 +2395,120 @@ func (camera *Camera) Render(scene *Scene, models ...*Model) {
 
 			camera.colorIntermediate.Clear()
 
-			if mat != nil {
-				rectShaderOptions.CompositeMode = mat.CompositeMode
-			}
+			rectShaderOptions.CompositeMode = model.materialCompositeMode(meshPart)
 
 			if hasFragShader {
-				camera.colorIntermediate.DrawTrianglesShader(colorVertexList[:vertexListIndex], indexList[:vertexListIndex], mat.fragmentShader, mat.FragmentShaderOptions)
+				camera.colorIntermediate.DrawTrianglesShader(colorVertexList[:vertexListIndex], indexList[:vertexListIndex], mat.fragmentShader, shaderOptions)
 			} else {
 				camera.colorIntermediate.DrawTriangles(colorVertexList[:vertexListIndex], indexList[:vertexListIndex], img, t)
 			}
 
 			camera.resultColorTexture.DrawRectShader(w, h, camera.colorShader, rectShaderOptions)
+			camera.RenderStats.DrawCalls += 2
 
 		} else {
 
-			if mat != nil {
-				t.CompositeMode = mat.CompositeMode
-			}
+			t.CompositeMode = model.materialCompositeMode(meshPart)
 
 			if hasFragShader {
-				camera.resultColorTexture.DrawTrianglesShader(colorVertexList[:vertexListIndex], indexList[:vertexListIndex], mat.fragmentShader, mat.FragmentShaderOptions)
+				camera.resultColorTexture.DrawTrianglesShader(colorVertexList[:vertexListIndex], indexList[:vertexListIndex], mat.fragmentShader, shaderOptions)
 			} else {
 				camera.resultColorTexture.DrawTriangles(colorVertexList[:vertexListIndex], indexList[:vertexListIndex], img, t)
 			}
 
+			camera.RenderStats.DrawCalls++
+
 		}
 
 		camera.DebugInfo.DrawnTris += vertexListIndex / 3
+		camera.RenderStats.TrianglesDrawn += vertexListIndex / 3
 
 		vertexListIndex = 0
 
+		camera.RenderStats.RasterizeTime += time.Since(rasterizeStart)
+
+		stageEnd(ProfileStageRasterize)
+
 	}
 
-	for _, pair := range solids {
+	renderPairs := func(pairs []renderPair) {
 
-		// Internally, the idea behind dynamic batching is that we simply hold off on flushing until the
-		// end - this saves a lot of time if we're rendering singular low-poly objects, at the cost of each
-		// object sharing the same material / object-level properties (color / material blending mode, for
-		// example).
-		if dyn := pair.Model.DynamicBatchModels; len(dyn) > 0 {
+		for i, pair := range pairs {
 
-			for _, merged := range dyn {
-				for _, part := range merged.Mesh.MeshParts {
-					render(renderPair{Model: merged, MeshPart: part})
+			// Internally, the idea behind dynamic batching is that we simply hold off on flushing until the
+			// end - this saves a lot of time if we're rendering singular low-poly objects, at the cost of each
+			// object sharing the same material / object-level properties (color / material blending mode, for
+			// example).
+			if dyn := pair.Model.DynamicBatchModels; len(dyn) > 0 {
+
+				for _, merged := range dyn {
+					for _, part := range merged.Mesh.MeshParts {
+						render(renderPair{Model: merged, MeshPart: part})
+					}
 				}
+
+				flush(pair)
+				continue
+
 			}
 
-			flush(pair)
-		} else {
 			render(pair)
+
+			// When batching by material, we hold off on flushing if the next MeshPart can be rendered in the same draw call
+			// and its triangles would fit. The picking pass draws a whole batch with a single Model's ID, so while picking,
+			// only MeshParts from the same Model are batched together.
+			if camera.BatchByMaterial && i < len(pairs)-1 {
+				next := pairs[i+1]
+				samePickingID := !camera.RenderPicking || next.Model == pair.Model
+				if samePickingID && vertexListIndex+(next.MeshPart.TriangleCount()*3) < ebiten.MaxIndicesNum && canBatchRenderPairs(pair, next) {
+					continue
+				}
+			}
+
 			flush(pair)
+
 		}
 
 	}
 
-	if len(transparents) > 0 {
-
-		sort.SliceStable(transparents, func(i, j int) bool {
-			return depths[transparents[i].Model] > depths[transparents[j].Model]
-		})
-
-		for _, pair := range transparents {
+	if camera.BatchByMaterial && camera.RenderDepth {
+		buffers.materialSorter.sort(solids)
+	}
 
-			if dyn := pair.Model.DynamicBatchModels; len(dyn) > 0 {
+	if camera.DepthPrePass && camera.RenderDepth {
 
-				for _, merged := range dyn {
-					for _, part := range merged.Mesh.MeshParts {
-						render(renderPair{Model: merged, MeshPart: part})
-					}
-				}
+		depthOnly = true
 
-				flush(pair)
-			} else {
+		for _, pair := range solids {
+			if usesPrePass(pair) {
 				render(pair)
 				flush(pair)
 			}
-
 		}
 
+		depthOnly = false
+
+	}
+
+	renderPairs(solids)
+
+	if len(transparents) > 0 {
+
+		buffers.renderPairSorter.pairs = transparents
+		sort.Stable(buffers.renderPairSorter)
+
+		renderPairs(transparents)
+
 	}
 
 	camera.DebugInfo.frameTime += time.Since(frametimeStart)
 
+	camera.FrameBudget.renderTime += time.Since(frametimeStart)
+
 	camera.DebugInfo.frameCount++
 
+	stageEnd(ProfileStageRender)
+
 }
 
 func (camera *Camera) drawCircle(screen *ebiten.Image, position vector.Vector, radius float64, drawColor color.Color) {
@@ -1211,8 +2543,10 @@ func (camera *Camera) DrawDebugRenderInfo(screen *ebiten.Image, textScale float6
 	m = camera.DebugInfo.AvgLightTime.Round(time.Microsecond).Microseconds()
 	lt := fmt.Sprintf("%.2fms", float32(m)/1000)
 
+	stats := camera.RenderStats
+
 	debugText := fmt.Sprintf(
-		"TPS: %f\nFPS: %f\nTotal render frame-time: %s\nSkinned mesh animation time: %s\nLighting frame-time: %s\nDraw calls: %d/%d\nRendered triangles: %d/%d\nActive Lights: %d/%d",
+		"TPS: %f\nFPS: %f\nTotal render frame-time: %s\nSkinned mesh animation time: %s\nLighting frame-time: %s\nDraw calls: %d/%d\nRendered triangles: %d/%d\nActive Lights: %d/%d\nCulled / clipped triangles: %d / %d\nRendered / culled models: %d / %d\nGPU draw calls issued: %d",
 		ebiten.CurrentTPS(),
 		ebiten.CurrentFPS(),
 		ft,
@@ -1223,7 +2557,12 @@ func (camera *Camera) DrawDebugRenderInfo(screen *ebiten.Image, textScale float6
 		camera.DebugInfo.DrawnTris,
 		camera.DebugInfo.TotalTris,
 		camera.DebugInfo.ActiveLightCount,
-		camera.DebugInfo.LightCount)
+		camera.DebugInfo.LightCount,
+		stats.TrianglesCulled,
+		stats.TrianglesClipped,
+		stats.ModelsRendered,
+		stats.ModelsCulled,
+		stats.DrawCalls)
 
 	camera.DebugDrawText(screen, debugText, 0, 0, textScale, color)
 
@@ -1243,7 +2582,7 @@ func (camera *Camera) DrawDebugWireframe(screen *ebiten.Image, rootNode INode, c
 
 		if model, isModel := m.(*Model); isModel {
 
-			if model.FrustumCulling {
+			if model.frustumCulling {
 
 				model.Transform()
 				if !camera.SphereInFrustum(model.BoundingSphere) {
@@ -1258,21 +2597,21 @@ func (camera *Camera) DrawDebugWireframe(screen *ebiten.Image, rootNode INode, c
 
 				for i := 0; i < len(model.Mesh.vertexTransforms); i += 3 {
 
-					v0 := camera.ClipToScreen(model.Mesh.vertexTransforms[i])
-					v1 := camera.ClipToScreen(model.Mesh.vertexTransforms[i+1])
-					v2 := camera.ClipToScreen(model.Mesh.vertexTransforms[i+2])
+					v0 := camera.clipToScreen(model.Mesh.vertexTransforms[i], i, nil, float64(camWidth), float64(camHeight))
+					v1 := camera.clipToScreen(model.Mesh.vertexTransforms[i+1], i+1, nil, float64(camWidth), float64(camHeight))
+					v2 := camera.clipToScreen(model.Mesh.vertexTransforms[i+2], i+2, nil, float64(camWidth), float64(camHeight))
 
-					if (v0[0] < 0 && v1[0] < 0 && v2[0] < 0) ||
-						(v0[1] < 0 && v1[1] < 0 && v2[1] < 0) ||
-						(v0[0] > float64(camWidth) && v1[0] > float64(camWidth) && v2[0] > float64(camWidth)) ||
-						(v0[1] > float64(camHeight) && v1[1] > float64(camHeight) && v2[1] > float64(camHeight)) {
+					if (v0.X < 0 && v1.X < 0 && v2.X < 0) ||
+						(v0.Y < 0 && v1.Y < 0 && v2.Y < 0) ||
+						(v0.X > float64(camWidth) && v1.X > float64(camWidth) && v2.X > float64(camWidth)) ||
+						(v0.Y > float64(camHeight) && v1.Y > float64(camHeight) && v2.Y > float64(camHeight)) {
 						continue
 					}
 
 					c := color.ToRGBA64()
-					ebitenutil.DrawLine(screen, float64(v0[0]), float64(v0[1]), float64(v1[0]), float64(v1[1]), c)
-					ebitenutil.DrawLine(screen, float64(v1[0]), float64(v1[1]), float64(v2[0]), float64(v2[1]), c)
-					ebitenutil.DrawLine(screen, float64(v2[0]), float64(v2[1]), float64(v0[0]), float64(v0[1]), c)
+					ebitenutil.DrawLine(screen, v0.X, v0.Y, v1.X, v1.Y, c)
+					ebitenutil.DrawLine(screen, v1.X, v1.Y, v2.X, v2.Y, c)
+					ebitenutil.DrawLine(screen, v2.X, v2.Y, v0.X, v0.Y, c)
 
 				}
 
@@ -1308,7 +2647,7 @@ func (camera *Camera) DrawDebugDrawOrder(screen *ebiten.Image, rootNode INode, t
 
 		if model, isModel := m.(*Model); isModel {
 
-			if model.FrustumCulling {
+			if model.frustumCulling {
 
 				model.Transform()
 				if !camera.SphereInFrustum(model.BoundingSphere) {
@@ -1349,7 +2688,7 @@ func (camera *Camera) DrawDebugDrawCallCount(screen *ebiten.Image, rootNode INod
 
 		if model, isModel := m.(*Model); isModel {
 
-			if model.FrustumCulling {
+			if model.frustumCulling {
 
 				model.Transform()
 				if !camera.SphereInFrustum(model.BoundingSphere) {
@@ -1378,7 +2717,7 @@ func (camera *Camera) DrawDebugNormals(screen *ebiten.Image, rootNode INode, nor
 
 		if model, isModel := m.(*Model); isModel {
 
-			if model.FrustumCulling {
+			if model.frustumCulling {
 
 				model.Transform()
 				if !camera.SphereInFrustum(model.BoundingSphere) {
@@ -1458,9 +2797,182 @@ func (camera *Camera) DebugDrawText(screen *ebiten.Image, txtStr string, posX, p
 
 }
 
-// ColorTexture returns the camera's final result color texture from any previous Render() or RenderNodes() calls.
+// NodeAtScreenPosition returns the Node (i.e. the Model) rendered at the given screen position in the Camera's last render, or nil if there's
+// nothing there. The position is in the Camera's full-size screen coordinates (i.e. it accounts for the render scale). This only works if
+// Camera.RenderPicking is true. Note that this reads pixels back from the GPU, so it's best to call it sparingly (i.e. when the mouse is clicked).
+func (camera *Camera) NodeAtScreenPosition(x, y int) INode {
+
+	if !camera.RenderPicking || camera.pickingTexture == nil {
+		return nil
+	}
+
+	px := int(float64(x) * camera.renderScale)
+	py := int(float64(y) * camera.renderScale)
+
+	r, g, b, a := camera.pickingTexture.At(px, py).RGBA()
+
+	if a == 0 {
+		return nil
+	}
+
+	id := int(r>>8)<<16 | int(g>>8)<<8 | int(b>>8)
+
+	if id <= 0 || id > len(camera.pickingNodes) {
+		return nil
+	}
+
+	return camera.pickingNodes[id-1]
+
+}
+
+// DepthAt returns the distance from the Camera (along its forward axis) to the surface rendered at the given screen position in the Camera's
+// last render, decoded from the Camera's depth texture. This is useful for soft particles, finding world positions under the cursor, or
+// custom depth-based effects. The position is in the Camera's full-size screen coordinates (i.e. it accounts for the render scale). If
+// nothing was rendered at the position (or Camera.RenderDepth is false), DepthAt returns -1. Note that depth is stored with limited precision,
+// and surfaces close to the Camera's near or far planes may be clamped to them. Like NodeAtScreenPosition(), this reads pixels back from the GPU.
+func (camera *Camera) DepthAt(x, y int) float64 {
+
+	if !camera.RenderDepth {
+		return -1
+	}
+
+	px := int(float64(x) * camera.renderScale)
+	py := int(float64(y) * camera.renderScale)
+
+	return camera.depthToDistance(camera.resultDepthTexture.At(px, py))
+
+}
+
+// WorldPositionAtScreen returns the world position of the surface rendered at the given screen position in the Camera's last render, reconstructed
+// from the Camera's depth texture. As this uses the rendered surface itself rather than Models' bounds, it's far more accurate for placing objects
+// (i.e. in editors or building games) than testing rays against bounding objects. To find which Node is under the position, use
+// Camera.NodeAtScreenPosition(). The position is in the Camera's full-size screen coordinates (i.e. it accounts for the render scale). If nothing
+// was rendered at the position (or Camera.RenderDepth is false), WorldPositionAtScreen returns nil. As it uses Camera.DepthAt(), it has the same
+// precision limitations, and reads pixels back from the GPU.
+func (camera *Camera) WorldPositionAtScreen(x, y int) vector.Vector {
+
+	distance := camera.DepthAt(x, y)
+
+	if distance < 0 {
+		return nil
+	}
+
+	w, h := camera.resultColorTexture.Size()
+	projection := camera.Projection()
+
+	// Undo the mapping from clip space to screen space that clipToScreen() performs, using the center of the pixel.
+	screenX := (float64(int(float64(x)*camera.renderScale)) + 0.5 - float64(w)/2) / float64(w)
+	screenY := (float64(h)/2 - float64(int(float64(y)*camera.renderScale)) - 0.5) / float64(h)
+
+	clipW := 1.0
+	if camera.Perspective {
+		clipW = -distance * projection[2][3]
+	}
+
+	// The view-space position is in front of the Camera, which looks down -Z.
+	viewPos := vector.Vector{screenX * clipW / projection[0][0], screenY * clipW / projection[1][1], -distance}
+
+	return camera.WorldRotation().MultVec(viewPos).Add(camera.WorldPosition())
+
+}
+
+// MouseRay returns a world-space Ray starting at the Camera's near plane and passing through the given screen position (for example, the mouse
+// cursor's position), which can be used with Ray.Test() or RayTest() to find out what's under the cursor. For perspective Cameras, the Ray
+// starts at the Camera's position and fans out with the field of view, while for orthographic Cameras, the Ray points straight ahead from the
+// screen position. The position is in the Camera's full-size screen coordinates (i.e. it accounts for the render scale).
+func (camera *Camera) MouseRay(x, y int) Ray {
+
+	w, h := camera.resultColorTexture.Size()
+	projection := camera.Projection()
+
+	// Undo the mapping from clip space to screen space that clipToScreen() performs, using the center of the pixel.
+	screenX := ((float64(x)+0.5)*camera.renderScale - float64(w)/2) / float64(w)
+	screenY := (float64(h)/2 - (float64(y)+0.5)*camera.renderScale) / float64(h)
+
+	rotation := camera.WorldRotation()
+
+	if camera.Perspective {
+		clipW := -projection[2][3]
+		direction := rotation.MultVec(vector.Vector{screenX * clipW / projection[0][0], screenY * clipW / projection[1][1], -1})
+		return NewRay(camera.WorldPosition().Add(direction.Scale(camera.Near)), direction)
+	}
+
+	offset := rotation.MultVec(vector.Vector{screenX / projection[0][0], screenY / projection[1][1], -camera.Near})
+
+	return NewRay(camera.WorldPosition().Add(offset), rotation.Forward().Invert())
+
+}
+
+// ReadDepth reads back the Camera's entire depth texture, storing the distance from the Camera (along its forward axis) to the surface rendered
+// at each pixel in the slice provided, row by row, and returning the result. Pixels where nothing was rendered are set to -1. The slice is
+// reallocated if it isn't large enough to hold all of the pixels; pass the returned slice back in each time to avoid allocating. Note that
+// the depth texture's size is the Camera's texture size (i.e. it's affected by the render scale). If Camera.RenderDepth is false, ReadDepth
+// returns the slice provided, emptied.
+func (camera *Camera) ReadDepth(distances []float64) []float64 {
+
+	distances = distances[:0]
+
+	if !camera.RenderDepth {
+		return distances
+	}
+
+	w, h := camera.resultDepthTexture.Size()
+
+	if cap(distances) < w*h {
+		distances = make([]float64, 0, w*h)
+	}
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			distances = append(distances, camera.depthToDistance(camera.resultDepthTexture.At(x, y)))
+		}
+	}
+
+	return distances
+
+}
+
+// depthToDistance decodes the color of a pixel of the depth texture back to the distance from the Camera along its forward axis, or -1 if
+// nothing was rendered there.
+func (camera *Camera) depthToDistance(depthColor color.Color) float64 {
+
+	r, g, b, a := depthColor.RGBA()
+
+	if a == 0 {
+		return -1
+	}
+
+	// This mirrors decodeDepth() in the depth shader.
+	depth := float64(r>>8)/255 + float64(g>>8)/255/255 + float64(b>>8)/255/65025
+
+	// Depth is stored as (Z + Near) / Far + 0.03, where Z is the clip-space Z, so we undo that and then the projection.
+	clipZ := (depth-0.03)*camera.Far - camera.Near
+
+	if camera.Perspective {
+		return (clipZ + 1) * (camera.Far - camera.Near) / (camera.Far + camera.Near)
+	}
+
+	return clipZ * (camera.Far - camera.Near) / 2
+
+}
+
+// ColorTexture returns the camera's final result color texture from any previous Render() or RenderNodes() calls. If the Camera's
+// AmbientOcclusion is on (and the Camera renders depth), the texture returned has ambient occlusion applied, and if the Camera's
+// ColorGrading is on, the texture returned is color graded.
 func (camera *Camera) ColorTexture() *ebiten.Image {
-	return camera.resultColorTexture
+
+	texture := camera.resultColorTexture
+
+	if camera.AmbientOcclusion.On && camera.RenderDepth {
+		texture = camera.ambientOcclusionTexture()
+	}
+
+	if camera.ColorGrading.On {
+		texture = camera.colorGradingTexture(texture)
+	}
+
+	return texture
+
 }
 
 // DepthTexture returns the camera's final result depth texture from any previous Render() or RenderNodes() calls. If Camera.RenderDepth is set to false,
@@ -1487,181 +2999,234 @@ func (camera *Camera) DrawDebugBoundsColored(screen *ebiten.Image, rootNode INod
 
 	allModels := append([]INode{rootNode}, rootNode.ChildrenRecursive()...)
 
-	camWidth, camHeight := camera.resultColorTexture.Size()
-
 	for _, n := range allModels {
 
 		if b, isBounds := n.(BoundingObject); isBounds {
+			camera.drawDebugBoundingObject(screen, b, aabbColor, sphereColor, capsuleColor, trianglesColor)
+		}
 
-			switch bounds := b.(type) {
+	}
 
-			case *BoundingSphere:
+}
 
-				pos := bounds.WorldPosition()
-				radius := bounds.WorldRadius()
+// drawDebugBoundingObject draws a shape approximating the shape and position of the BoundingObject provided to the screen image provided,
+// using the color provided for its kind of bounding object.
+func (camera *Camera) drawDebugBoundingObject(screen *ebiten.Image, b BoundingObject, aabbColor, sphereColor, capsuleColor, trianglesColor *Color) {
 
-				u := camera.WorldToScreen(pos.Add(vector.Y.Scale(radius)))
-				d := camera.WorldToScreen(pos.Add(vector.Y.Scale(-radius)))
-				r := camera.WorldToScreen(pos.Add(vector.X.Scale(radius)))
-				l := camera.WorldToScreen(pos.Add(vector.X.Scale(-radius)))
-				f := camera.WorldToScreen(pos.Add(vector.Z.Scale(radius)))
-				b := camera.WorldToScreen(pos.Add(vector.Z.Scale(-radius)))
+	camWidth, camHeight := camera.resultColorTexture.Size()
 
-				lines := []vector.Vector{
-					u, r, d, l,
-					u, f, d, b, u,
-					b, r, f, l, b,
-				}
+	switch bounds := b.(type) {
 
-				for i := range lines {
+	case *BoundingSphere:
 
-					if i >= len(lines)-1 {
-						break
-					}
+		pos := bounds.WorldPosition()
+		radius := bounds.WorldRadius()
 
-					start := lines[i]
-					end := lines[i+1]
-					ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], sphereColor.ToRGBA64())
+		u := camera.WorldToScreen(pos.Add(vector.Y.Scale(radius)))
+		d := camera.WorldToScreen(pos.Add(vector.Y.Scale(-radius)))
+		r := camera.WorldToScreen(pos.Add(vector.X.Scale(radius)))
+		l := camera.WorldToScreen(pos.Add(vector.X.Scale(-radius)))
+		f := camera.WorldToScreen(pos.Add(vector.Z.Scale(radius)))
+		b := camera.WorldToScreen(pos.Add(vector.Z.Scale(-radius)))
 
-				}
+		lines := []vector.Vector{
+			u, r, d, l,
+			u, f, d, b, u,
+			b, r, f, l, b,
+		}
 
-			case *BoundingCapsule:
+		for i := range lines {
 
-				pos := bounds.WorldPosition()
-				radius := bounds.WorldRadius()
-				height := bounds.Height / 2
+			if i >= len(lines)-1 {
+				break
+			}
 
-				uv := bounds.WorldRotation().Up()
-				rv := bounds.WorldRotation().Right()
-				fv := bounds.WorldRotation().Forward()
+			start := lines[i]
+			end := lines[i+1]
+			ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], sphereColor.ToRGBA64())
 
-				u := camera.WorldToScreen(pos.Add(uv.Scale(height)))
+		}
 
-				ur := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(rv.Scale(radius)))
-				ul := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(rv.Scale(-radius)))
-				uf := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(fv.Scale(radius)))
-				ub := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(fv.Scale(-radius)))
+	case *BoundingCapsule:
 
-				d := camera.WorldToScreen(pos.Add(uv.Scale(-height)))
+		pos := bounds.WorldPosition()
+		radius := bounds.WorldRadius()
+		height := bounds.Height / 2
 
-				dr := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(rv.Scale(radius)))
-				dl := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(rv.Scale(-radius)))
-				df := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(fv.Scale(radius)))
-				db := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(fv.Scale(-radius)))
+		uv := bounds.WorldRotation().Up()
+		rv := bounds.WorldRotation().Right()
+		fv := bounds.WorldRotation().Forward()
 
-				lines := []vector.Vector{
-					u, ur, dr, d, dl, ul,
-					u, uf, df, d, db, ub, u,
-					ul, uf, ur, ub, ul,
-					dl, db, dr, df, dl,
-				}
+		u := camera.WorldToScreen(pos.Add(uv.Scale(height)))
 
-				for i := range lines {
+		ur := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(rv.Scale(radius)))
+		ul := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(rv.Scale(-radius)))
+		uf := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(fv.Scale(radius)))
+		ub := camera.WorldToScreen(pos.Add(uv.Scale(height - radius)).Add(fv.Scale(-radius)))
 
-					if i >= len(lines)-1 {
-						break
-					}
+		d := camera.WorldToScreen(pos.Add(uv.Scale(-height)))
 
-					start := lines[i]
-					end := lines[i+1]
-					ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], capsuleColor.ToRGBA64())
+		dr := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(rv.Scale(radius)))
+		dl := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(rv.Scale(-radius)))
+		df := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(fv.Scale(radius)))
+		db := camera.WorldToScreen(pos.Add(uv.Scale(-(height - radius))).Add(fv.Scale(-radius)))
 
-				}
+		lines := []vector.Vector{
+			u, ur, dr, d, dl, ul,
+			u, uf, df, d, db, ub, u,
+			ul, uf, ur, ub, ul,
+			dl, db, dr, df, dl,
+		}
+
+		for i := range lines {
 
-			case *BoundingAABB:
+			if i >= len(lines)-1 {
+				break
+			}
 
-				pos := bounds.WorldPosition()
-				size := bounds.Size.Scale(1.0 / 2.0)
+			start := lines[i]
+			end := lines[i+1]
+			ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], capsuleColor.ToRGBA64())
 
-				ufr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], size[1], size[2]}))
-				ufl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], size[1], size[2]}))
-				ubr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], size[1], -size[2]}))
-				ubl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], size[1], -size[2]}))
+		}
 
-				dfr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], -size[1], size[2]}))
-				dfl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], -size[1], size[2]}))
-				dbr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], -size[1], -size[2]}))
-				dbl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], -size[1], -size[2]}))
+	case *BoundingAABB:
 
-				lines := []vector.Vector{
-					ufr, ufl, ubl, ubr, ufr,
-					dfr, dfl, dbl, dbr, dfr,
-					ufr, ufl, dfl, dbl, ubl, ubr, dbr,
-				}
+		pos := bounds.WorldPosition()
+		size := bounds.Size.Scale(1.0 / 2.0)
 
-				for i := range lines {
+		ufr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], size[1], size[2]}))
+		ufl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], size[1], size[2]}))
+		ubr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], size[1], -size[2]}))
+		ubl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], size[1], -size[2]}))
 
-					if i >= len(lines)-1 {
-						break
-					}
+		dfr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], -size[1], size[2]}))
+		dfl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], -size[1], size[2]}))
+		dbr := camera.WorldToScreen(pos.Add(vector.Vector{size[0], -size[1], -size[2]}))
+		dbl := camera.WorldToScreen(pos.Add(vector.Vector{-size[0], -size[1], -size[2]}))
 
-					start := lines[i]
-					end := lines[i+1]
-					ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], aabbColor.ToRGBA64())
+		lines := []vector.Vector{
+			ufr, ufl, ubl, ubr, ufr,
+			dfr, dfl, dbl, dbr, dfr,
+			ufr, ufl, dfl, dbl, ubl, ubr, dbr,
+		}
 
-				}
+		for i := range lines {
 
-			case *BoundingTriangles:
+			if i >= len(lines)-1 {
+				break
+			}
 
-				lines := []vector.Vector{}
+			start := lines[i]
+			end := lines[i+1]
+			ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], aabbColor.ToRGBA64())
 
-				mesh := bounds.Mesh
+		}
 
-				for _, tri := range mesh.Triangles {
+	case *BoundingTriangles:
 
-					mvpMatrix := bounds.Transform().Mult(camera.ViewMatrix().Mult(camera.Projection()))
+		lines := []vector.Vector{}
 
-					v0 := camera.ClipToScreen(mvpMatrix.MultVecW(mesh.VertexPositions[tri.ID*3]))
-					v1 := camera.ClipToScreen(mvpMatrix.MultVecW(mesh.VertexPositions[tri.ID*3+1]))
-					v2 := camera.ClipToScreen(mvpMatrix.MultVecW(mesh.VertexPositions[tri.ID*3+2]))
+		mesh := bounds.Mesh
 
-					if (v0[0] < 0 && v1[0] < 0 && v2[0] < 0) ||
-						(v0[1] < 0 && v1[1] < 0 && v2[1] < 0) ||
-						(v0[0] > float64(camWidth) && v1[0] > float64(camWidth) && v2[0] > float64(camWidth)) ||
-						(v0[1] > float64(camHeight) && v1[1] > float64(camHeight) && v2[1] > float64(camHeight)) {
-						continue
-					}
+		for _, tri := range mesh.Triangles {
 
-					lines = append(lines, v0, v1, v2)
+			mvpMatrix := bounds.Transform().Mult(camera.ViewMatrix().Mult(camera.Projection()))
 
-				}
+			v0 := camera.ClipToScreen(mvpMatrix.MultVecW(mesh.VertexPositions[tri.ID*3]))
+			v1 := camera.ClipToScreen(mvpMatrix.MultVecW(mesh.VertexPositions[tri.ID*3+1]))
+			v2 := camera.ClipToScreen(mvpMatrix.MultVecW(mesh.VertexPositions[tri.ID*3+2]))
 
-				triColor := trianglesColor.ToRGBA64()
+			if (v0[0] < 0 && v1[0] < 0 && v2[0] < 0) ||
+				(v0[1] < 0 && v1[1] < 0 && v2[1] < 0) ||
+				(v0[0] > float64(camWidth) && v1[0] > float64(camWidth) && v2[0] > float64(camWidth)) ||
+				(v0[1] > float64(camHeight) && v1[1] > float64(camHeight) && v2[1] > float64(camHeight)) {
+				continue
+			}
 
-				for i := 0; i < len(lines); i += 3 {
+			lines = append(lines, v0, v1, v2)
 
-					if i >= len(lines)-1 {
-						break
-					}
+		}
 
-					start := lines[i]
-					end := lines[i+1]
-					ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], triColor)
+		triColor := trianglesColor.ToRGBA64()
 
-					start = lines[i+1]
-					end = lines[i+2]
-					ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], triColor)
+		for i := 0; i < len(lines); i += 3 {
 
-					start = lines[i+2]
-					end = lines[i]
-					ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], triColor)
+			if i >= len(lines)-1 {
+				break
+			}
 
-				}
+			start := lines[i]
+			end := lines[i+1]
+			ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], triColor)
 
-				camera.DrawDebugBoundsColored(screen, bounds.BoundingAABB, aabbColor, sphereColor, capsuleColor, trianglesColor)
+			start = lines[i+1]
+			end = lines[i+2]
+			ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], triColor)
 
-			}
+			start = lines[i+2]
+			end = lines[i]
+			ebitenutil.DrawLine(screen, start[0], start[1], end[0], end[1], triColor)
 
 		}
 
+		camera.drawDebugBoundingObject(screen, bounds.BoundingAABB, aabbColor, sphereColor, capsuleColor, trianglesColor)
+
 	}
 
 }
 
-// DrawDebugBounds will draw shapes approximating the shapes and positions of BoundingObjects underneath the rootNode. The shapes will
-// be drawn in the color provided to the screen image provided.
-func (camera *Camera) DrawDebugBounds(screen *ebiten.Image, rootNode INode, color *Color) {
-	camera.DrawDebugBoundsColored(screen, rootNode, color, color, color, color)
+// The colors Camera.DrawDebugBounds() draws each kind of BoundingObject with, as well as the color it draws intersecting BoundingObjects with.
+var (
+	DebugBoundsAABBColor         = NewColor(0, 1, 0, 1)
+	DebugBoundsSphereColor       = NewColor(0, 0.75, 1, 1)
+	DebugBoundsCapsuleColor      = NewColor(1, 0.85, 0, 1)
+	DebugBoundsTrianglesColor    = NewColor(0.6, 0.6, 0.6, 1)
+	DebugBoundsIntersectingColor = NewColor(1, 0.15, 0.15, 1)
+)
+
+// DrawDebugBounds will draw shapes approximating the shapes and positions of all BoundingObjects (BoundingAABBs, BoundingSpheres,
+// BoundingCapsules, and BoundingTriangles) underneath the rootNode to the screen image provided. Each kind of BoundingObject is drawn in a
+// distinct color (DebugBoundsAABBColor, DebugBoundsSphereColor, and so on), while BoundingObjects that are intersecting any other
+// BoundingObject under the rootNode are highlighted by being drawn in DebugBoundsIntersectingColor instead. Note that as every
+// BoundingObject is tested against every other one, this can be slow with many BoundingObjects (particularly BoundingTriangles).
+// To draw BoundingObjects in specific colors without intersection testing, use Camera.DrawDebugBoundsColored().
+func (camera *Camera) DrawDebugBounds(screen *ebiten.Image, rootNode INode) {
+
+	allBounds := []BoundingObject{}
+
+	for _, n := range append([]INode{rootNode}, rootNode.ChildrenRecursive()...) {
+		if b, isBounds := n.(BoundingObject); isBounds {
+			allBounds = append(allBounds, b)
+		}
+	}
+
+	intersecting := make([]bool, len(allBounds))
+
+	for i := 0; i < len(allBounds); i++ {
+
+		for j := i + 1; j < len(allBounds); j++ {
+
+			if (!intersecting[i] || !intersecting[j]) && allBounds[i].Colliding(allBounds[j]) {
+				intersecting[i] = true
+				intersecting[j] = true
+			}
+
+		}
+
+	}
+
+	for i, b := range allBounds {
+
+		if intersecting[i] {
+			c := DebugBoundsIntersectingColor
+			camera.drawDebugBoundingObject(screen, b, c, c, c, c)
+		} else {
+			camera.drawDebugBoundingObject(screen, b, DebugBoundsAABBColor, DebugBoundsSphereColor, DebugBoundsCapsuleColor, DebugBoundsTrianglesColor)
+		}
+
+	}
+
 }
 
 // DrawDebugFrustums will draw shapes approximating the frustum spheres for objects underneath the rootNode.
@@ -1710,6 +3275,290 @@ func (camera *Camera) DrawDebugFrustums(screen *ebiten.Image, rootNode INode, co
 
 }
 
+// drawDebugLine draws a line between the two world positions provided to the screen image in the color given. Lines that pass behind a
+// perspective Camera are clipped against its near plane, as points behind the Camera can't be projected onto the screen properly.
+func (camera *Camera) drawDebugLine(screen *ebiten.Image, start, end vector.Vector, drawColor color.Color) {
+
+	if camera.Perspective {
+
+		startW := camera.WorldToClip(start)[3]
+		endW := camera.WorldToClip(end)[3]
+
+		if startW < camera.Near && endW < camera.Near {
+			return
+		}
+
+		// The clip W component is the depth in front of the Camera, which changes linearly along the line, so we can just
+		// move the point that's behind the near plane along the line until it lies on it.
+		if startW < camera.Near {
+			start = start.Add(end.Sub(start).Scale((camera.Near - startW) / (endW - startW)))
+		} else if endW < camera.Near {
+			end = end.Add(start.Sub(end).Scale((camera.Near - endW) / (startW - endW)))
+		}
+
+	}
+
+	s := camera.WorldToScreen(start)
+	e := camera.WorldToScreen(end)
+	ebitenutil.DrawLine(screen, s[0], s[1], e[0], e[1], drawColor)
+
+}
+
+// skeletonBones returns the bones of the armature skinning the Model provided, or nil if the Model isn't skinned.
+func skeletonBones(model *Model) []INode {
+
+	if !model.Skinned || model.SkinRoot == nil {
+		return nil
+	}
+
+	bones := []INode{}
+
+	for _, node := range append([]INode{model.SkinRoot}, model.SkinRoot.ChildrenRecursive()...) {
+		if node.IsBone() {
+			bones = append(bones, node)
+		}
+	}
+
+	return bones
+
+}
+
+// DrawDebugSkeleton draws the bones of the armature skinning the Model provided to the screen image in the color given. As bones don't
+// have lengths of their own, each bone is drawn as an octahedron stretching from its position to the position of each of its child bones
+// (like bones in Blender), while bones without child bones are drawn as circles. This is useful for debugging skinning or animation issues.
+// To label the bones, use Camera.DrawDebugBoneNames(). If the Model isn't skinned, nothing is drawn.
+func (camera *Camera) DrawDebugSkeleton(screen *ebiten.Image, model *Model, color *Color) {
+
+	c := color.ToRGBA64()
+
+	for _, bone := range skeletonBones(model) {
+
+		head := bone.WorldPosition()
+		hasTail := false
+
+		for _, child := range bone.Children() {
+
+			if !child.IsBone() {
+				continue
+			}
+
+			hasTail = true
+
+			tail := child.WorldPosition()
+			dir := tail.Sub(head)
+			length := dir.Magnitude()
+
+			if length == 0 {
+				continue
+			}
+
+			dir = dir.Unit()
+
+			// We need two axes perpendicular to the bone to build the octahedron's "waist".
+			perp := vector.Y
+			if math.Abs(dir.Dot(perp)) > 0.99 {
+				perp = vector.X
+			}
+
+			a, _ := dir.Cross(perp)
+			a = a.Unit()
+			b, _ := dir.Cross(a)
+
+			width := length * 0.1
+			waistCenter := head.Add(dir.Scale(length * 0.1))
+
+			waist := []vector.Vector{
+				waistCenter.Add(a.Scale(width)),
+				waistCenter.Add(b.Scale(width)),
+				waistCenter.Add(a.Scale(-width)),
+				waistCenter.Add(b.Scale(-width)),
+			}
+
+			for i, w := range waist {
+				camera.drawDebugLine(screen, head, w, c)
+				camera.drawDebugLine(screen, w, tail, c)
+				camera.drawDebugLine(screen, w, waist[(i+1)%len(waist)], c)
+			}
+
+		}
+
+		if !hasTail {
+			camera.drawCircle(screen, head, 4, c)
+		}
+
+	}
+
+}
+
+// DrawDebugBoneNames draws the names of the bones of the armature skinning the Model provided at their positions to the screen image,
+// using the text scale and color given. If the Model isn't skinned, nothing is drawn.
+func (camera *Camera) DrawDebugBoneNames(screen *ebiten.Image, model *Model, textScale float64, color *Color) {
+
+	for _, bone := range skeletonBones(model) {
+
+		if camera.Perspective && camera.WorldToClip(bone.WorldPosition())[3] <= 0 {
+			continue
+		}
+
+		screenPos := camera.WorldToScreen(bone.WorldPosition())
+		camera.DebugDrawText(screen, bone.Name(), screenPos[0], screenPos[1], textScale, color)
+
+	}
+
+}
+
+// drawDebugWorldCircle draws a circle in world space with the center and radius provided, lying on the plane formed by the two axes given.
+func (camera *Camera) drawDebugWorldCircle(screen *ebiten.Image, center, axisA, axisB vector.Vector, radius float64, drawColor color.Color) {
+
+	stepCount := 32
+
+	for i := 0; i < stepCount; i++ {
+
+		a1 := math.Pi * 2 * float64(i) / float64(stepCount)
+		a2 := math.Pi * 2 * float64(i+1) / float64(stepCount)
+
+		start := center.Add(axisA.Scale(math.Cos(a1) * radius)).Add(axisB.Scale(math.Sin(a1) * radius))
+		end := center.Add(axisA.Scale(math.Cos(a2) * radius)).Add(axisB.Scale(math.Sin(a2) * radius))
+
+		camera.drawDebugLine(screen, start, end, drawColor)
+
+	}
+
+}
+
+// DrawDebugGizmos draws gizmos for the Lights, Paths, and Cameras underneath the rootNode to the screen image provided in the color given,
+// similar to how they're displayed in Blender. The gizmos argument indicates which kinds of gizmos to draw, and should be a combination of the
+// DebugGizmo constants (i.e. DebugGizmoLights | DebugGizmoPaths, or DebugGizmoAll). Point lights are drawn as circles, along with their range
+// (if their Distance is set); directional lights are drawn as circles with a line pointing in the direction they shine; Paths are drawn as lines
+// connecting their points, with the points' indices drawn using the text scale provided; and Cameras are drawn as pyramids showing the
+// direction they're looking and the shape of their frustum, with a triangle indicating their up direction. The Camera drawing the gizmos is skipped.
+func (camera *Camera) DrawDebugGizmos(screen *ebiten.Image, rootNode INode, gizmos int, textScale float64, color *Color) {
+
+	allNodes := append([]INode{rootNode}, rootNode.ChildrenRecursive()...)
+
+	c := color.ToRGBA64()
+
+	for _, node := range allNodes {
+
+		switch n := node.(type) {
+
+		case *PointLight:
+
+			if gizmos&DebugGizmoLights == 0 {
+				continue
+			}
+
+			camera.drawCircle(screen, n.WorldPosition(), 6, c)
+
+			if n.Distance > 0 {
+				pos := n.WorldPosition()
+				camera.drawDebugWorldCircle(screen, pos, vector.X, vector.Y, n.Distance, c)
+				camera.drawDebugWorldCircle(screen, pos, vector.X, vector.Z, n.Distance, c)
+				camera.drawDebugWorldCircle(screen, pos, vector.Y, vector.Z, n.Distance, c)
+			}
+
+		case *DirectionalLight:
+
+			if gizmos&DebugGizmoLights == 0 {
+				continue
+			}
+
+			pos := n.WorldPosition()
+			camera.drawCircle(screen, pos, 6, c)
+			camera.drawCircle(screen, pos, 10, c)
+
+			// A DirectionalLight's forward vector points back towards the light, so it shines in the opposite direction.
+			camera.drawDebugLine(screen, pos, pos.Add(n.WorldRotation().Forward().Scale(-2)), c)
+
+		case *Path:
+
+			if gizmos&DebugGizmoPaths == 0 {
+				continue
+			}
+
+			points := n.Children()
+
+			for i, point := range points {
+
+				pos := point.WorldPosition()
+
+				if i < len(points)-1 {
+					camera.drawDebugLine(screen, pos, points[i+1].WorldPosition(), c)
+				} else if n.Closed && len(points) > 2 {
+					camera.drawDebugLine(screen, pos, points[0].WorldPosition(), c)
+				}
+
+				if camera.Perspective && camera.WorldToClip(pos)[3] <= 0 {
+					continue
+				}
+
+				camera.drawCircle(screen, pos, 4, c)
+
+				screenPos := camera.WorldToScreen(pos)
+				camera.DebugDrawText(screen, fmt.Sprintf("%d", i), screenPos[0], screenPos[1], textScale, color)
+
+			}
+
+		case *Camera:
+
+			if gizmos&DebugGizmoCameras == 0 || n == camera {
+				continue
+			}
+
+			camera.drawDebugCameraGizmo(screen, n, c)
+
+		}
+
+	}
+
+}
+
+// drawDebugCameraGizmo draws the provided Camera as a pyramid (or a box, for orthographic Cameras), pointing in the direction the Camera looks,
+// with a triangle over the top to indicate its up direction.
+func (camera *Camera) drawDebugCameraGizmo(screen *ebiten.Image, other *Camera, drawColor color.Color) {
+	// The gizmo only extends a unit in front of the Camera, as a Camera's far plane is usually too far away to draw usefully.
+	camera.drawDebugFrustumCorners(screen, other.FrustumCorners(0, 1), drawColor)
+}
+
+// drawDebugFrustumCorners draws the edges of the view volume described by the corners provided (as returned by Camera.FrustumCorners()),
+// with a triangle over the top edge of the far end to indicate its up direction.
+func (camera *Camera) drawDebugFrustumCorners(screen *ebiten.Image, corners [8]vector.Vector, drawColor color.Color) {
+
+	for i := 0; i < 4; i++ {
+		next := (i + 1) % 4
+		camera.drawDebugLine(screen, corners[i], corners[next], drawColor)
+		camera.drawDebugLine(screen, corners[4+i], corners[4+next], drawColor)
+		camera.drawDebugLine(screen, corners[i], corners[4+i], drawColor)
+	}
+
+	// The up triangle sits over the top edge (top-left to top-right) of the far end, a quarter as tall as the far end.
+	topLeft, topRight, bottomLeft := corners[7], corners[6], corners[4]
+	top := topLeft.Add(topRight).Scale(0.5).Add(topLeft.Sub(bottomLeft).Scale(0.25))
+	camera.drawDebugLine(screen, topLeft, top, drawColor)
+	camera.drawDebugLine(screen, top, topRight, drawColor)
+
+}
+
+// DrawDebugFrustum draws the view frustum of the other Camera provided (i.e. the volume of space it can see, between its near and far
+// planes) to the screen image in the color given. Lines are also drawn from the other Camera's position to the corners of its near plane,
+// and a triangle is drawn over its far plane to indicate its up direction. This is useful for debugging culling issues or fitting a shadow
+// camera to a scene by viewing another Camera's view volume from a free-flying debug Camera.
+func (camera *Camera) DrawDebugFrustum(screen *ebiten.Image, otherCamera *Camera, color *Color) {
+
+	c := color.ToRGBA64()
+
+	corners := otherCamera.FrustumCorners(otherCamera.Near, otherCamera.Far)
+
+	camera.drawDebugFrustumCorners(screen, corners, c)
+
+	if otherCamera.Perspective {
+		for _, corner := range corners[:4] {
+			camera.drawDebugLine(screen, otherCamera.WorldPosition(), corner, c)
+		}
+	}
+
+}
+
 /////
 
 // AddChildren parents the provided children Nodes to the passed parent Node, inheriting its transformations and being under it in the scenegraph
@@ -1,8 +1,12 @@
 package tetra3d
 
 import (
+	"errors"
+	"fmt"
 	"image/color"
 	"math"
+	"strconv"
+	"strings"
 )
 
 // Color represents a color, containing R, G, B, and A components, each expected to range from 0 to 1.
@@ -83,28 +87,79 @@ func (color *Color) capRGBA64(value float32) uint16 {
 	return uint16(value * math.MaxUint16)
 }
 
-// ConvertTosRGB() converts the color's R, G, and B components to the sRGB color space. This is used to convert
+// ConvertTosRGB() converts the color's R, G, and B components from linear space to the sRGB color space. This is used to convert
 // colors from their values in GLTF to how they should appear on the screen. See: https://en.wikipedia.org/wiki/SRGB
 func (color *Color) ConvertTosRGB() {
+	color.R = linearTosRGB(color.R)
+	color.G = linearTosRGB(color.G)
+	color.B = linearTosRGB(color.B)
+}
+
+// ConvertToLinear() converts the color's R, G, and B components from the sRGB color space to linear space; this is the inverse of ConvertTosRGB().
+// Linear space is where blending and lighting colors together is mathematically correct.
+func (color *Color) ConvertToLinear() {
+	color.R = sRGBToLinear(color.R)
+	color.G = sRGBToLinear(color.G)
+	color.B = sRGBToLinear(color.B)
+}
+
+func linearTosRGB(value float32) float32 {
+	if value <= 0.0031308 {
+		return value * 12.92
+	}
+	return float32(1.055*math.Pow(float64(value), 1/2.4) - 0.055)
+}
+
+func sRGBToLinear(value float32) float32 {
+	if value <= 0.04045 {
+		return value / 12.92
+	}
+	return float32(math.Pow((float64(value)+0.055)/1.055, 2.4))
+}
 
-	if color.R <= 0.0031308 {
-		color.R *= 12.92
-	} else {
-		color.R = float32(1.055*math.Pow(float64(color.R), 1/2.4) - 0.055)
+// Lerp returns a new Color, linearly interpolated between the Color and the other Color provided by the percentage given (ranging from 0 to 1).
+func (color *Color) Lerp(other *Color, percentage float64) *Color {
+	p := float32(percentage)
+	return NewColor(
+		color.R+((other.R-color.R)*p),
+		color.G+((other.G-color.G)*p),
+		color.B+((other.B-color.B)*p),
+		color.A+((other.A-color.A)*p),
+	)
+}
+
+// NewColorFromHexString returns a new Color parsed from a hexadecimal string in the format of "#RRGGBB" or "#RRGGBBAA" (the leading "#" is optional).
+// If the alpha component is omitted, it defaults to 1. An error is returned if the string can't be parsed.
+func NewColorFromHexString(hex string) (*Color, error) {
+
+	hex = strings.TrimPrefix(hex, "#")
+
+	if len(hex) != 6 && len(hex) != 8 {
+		return nil, errors.New("hex color string must be in the format of #RRGGBB or #RRGGBBAA")
 	}
 
-	if color.G <= 0.0031308 {
-		color.G *= 12.92
-	} else {
-		color.G = float32(1.055*math.Pow(float64(color.G), 1/2.4) - 0.055)
+	if len(hex) == 6 {
+		hex += "ff"
 	}
 
-	if color.B <= 0.0031308 {
-		color.B *= 12.92
-	} else {
-		color.B = float32(1.055*math.Pow(float64(color.B), 1/2.4) - 0.055)
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, err
 	}
 
+	return NewColor(
+		float32((value>>24)&0xff)/255,
+		float32((value>>16)&0xff)/255,
+		float32((value>>8)&0xff)/255,
+		float32(value&0xff)/255,
+	), nil
+
+}
+
+// ToHexString returns the Color as a hexadecimal string in the format of "#RRGGBBAA". Components are capped to the range of 0 to 1.
+func (color *Color) ToHexString() string {
+	rgba := color.ToRGBA64()
+	return fmt.Sprintf("#%02x%02x%02x%02x", rgba.R>>8, rgba.G>>8, rgba.B>>8, rgba.A>>8)
 }
 
 // NewColorFromHSV returns a new color, using hue, saturation, and value numbers, each ranging from 0 to 1. A hue of
@@ -164,6 +219,15 @@ func NewColorFromHSV(h, s, v float64) *Color {
 	return &Color{float32(m + r), float32(m + g), float32(m + b), 1}
 }
 
+// SetHSV sets the Color's R, G, and B components using hue, saturation, and value numbers, each ranging from 0 to 1. The Color's alpha
+// component is left as-is. See NewColorFromHSV().
+func (color *Color) SetHSV(h, s, v float64) {
+	hsv := NewColorFromHSV(h, s, v)
+	color.R = hsv.R
+	color.G = hsv.G
+	color.B = hsv.B
+}
+
 // HSV returns a color as a hue, saturation, and value (each ranging from 0 to 1).
 // Also cribbed from: https://github.com/lucasb-eyer/go-colorful/blob/master/colors.go
 func (color *Color) HSV() (float64, float64, float64) {
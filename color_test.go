@@ -0,0 +1,100 @@
+package tetra3d
+
+import (
+	"math"
+	"testing"
+)
+
+func colorsEqual(a, b *Color) bool {
+	return math.Abs(float64(a.R-b.R)) < 0.002 &&
+		math.Abs(float64(a.G-b.G)) < 0.002 &&
+		math.Abs(float64(a.B-b.B)) < 0.002 &&
+		math.Abs(float64(a.A-b.A)) < 0.002
+}
+
+func TestColorHexString(t *testing.T) {
+
+	tests := []struct {
+		hex   string
+		color *Color
+		valid bool
+		out   string
+	}{
+		{"#ff0000", NewColor(1, 0, 0, 1), true, "#ff0000ff"},
+		{"00ff0080", NewColor(0, 1, 0, 128.0/255), true, "#00ff0080"},
+		{"#336699", NewColor(0.2, 0.4, 0.6, 1), true, "#336699ff"},
+		{"#FFFFFF00", NewColor(1, 1, 1, 0), true, "#ffffff00"},
+		{"#fff", nil, false, ""},
+		{"#ff00000", nil, false, ""},
+		{"#gg0000", nil, false, ""},
+		{"", nil, false, ""},
+	}
+
+	for _, test := range tests {
+
+		color, err := NewColorFromHexString(test.hex)
+
+		if !test.valid {
+			if err == nil {
+				t.Errorf("NewColorFromHexString(%q) didn't return an error", test.hex)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("NewColorFromHexString(%q) returned an error: %s", test.hex, err)
+			continue
+		}
+
+		if !colorsEqual(color, test.color) {
+			t.Errorf("NewColorFromHexString(%q) = %v, want %v", test.hex, color, test.color)
+		}
+
+		if out := color.ToHexString(); out != test.out {
+			t.Errorf("ToHexString() of %q = %q, want %q", test.hex, out, test.out)
+		}
+
+	}
+
+}
+
+func TestGradient(t *testing.T) {
+
+	red := NewColor(1, 0, 0, 1)
+	green := NewColor(0, 1, 0, 1)
+	blue := NewColor(0, 0, 1, 1)
+
+	smooth := NewGradient(red, green, blue)
+
+	stepped := smooth.Clone()
+	stepped.Stepped = true
+
+	added := NewGradient(red, blue)
+	added.Add(0.25, green)
+
+	tests := []struct {
+		name     string
+		gradient *Gradient
+		t        float64
+		color    *Color
+	}{
+		{"before start", smooth, -1, red},
+		{"start", smooth, 0, red},
+		{"between stops", smooth, 0.25, NewColor(0.5, 0.5, 0, 1)},
+		{"middle stop", smooth, 0.5, green},
+		{"end", smooth, 1, blue},
+		{"after end", smooth, 2, blue},
+		{"stepped", stepped, 0.4, red},
+		{"stepped past stop", stepped, 0.6, green},
+		{"added stop", added, 0.25, green},
+		{"after added stop", added, 0.625, NewColor(0, 0.5, 0.5, 1)},
+		{"empty", NewGradient(), 0.5, NewColor(1, 1, 1, 1)},
+	}
+
+	for _, test := range tests {
+		if color := test.gradient.Evaluate(test.t); !colorsEqual(color, test.color) {
+			t.Errorf("%s: Evaluate(%f) = %v, want %v", test.name, test.t, color, test.color)
+		}
+	}
+
+}
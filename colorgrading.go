@@ -0,0 +1,178 @@
+package tetra3d
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// colorGradingShaderSrc applies exposure, contrast, saturation, and gamma to the color texture (image 0), and then optionally maps the
+// result through a color lookup table, which is drawn into the top-left corner of image 1.
+var colorGradingShaderSrc = []byte(
+	`package main
+
+	var Exposure float
+	var Gamma float
+	var Contrast float
+	var Saturation float
+	var LUTSize float
+
+	// lutTexel returns the texel of the color lookup table (image 1) in the given tile at the given position within it, in texels.
+	func lutTexel(tile float, pos vec2) vec3 {
+		origin, _ := imageSrcRegionOnTexture()
+		pos = clamp(pos, vec2(0), vec2(LUTSize-1))
+		return imageSrc1At(origin + (vec2(tile*LUTSize, 0)+floor(pos)+0.5)/imageSrcTextureSize()).rgb
+	}
+
+	// lutBilinear returns the color lookup table's color in the given tile at the given position, interpolating between the nearest texels.
+	func lutBilinear(tile float, pos vec2) vec3 {
+		f := fract(pos)
+		top := mix(lutTexel(tile, pos), lutTexel(tile, pos+vec2(1, 0)), f.x)
+		bottom := mix(lutTexel(tile, pos+vec2(0, 1)), lutTexel(tile, pos+vec2(1, 1)), f.x)
+		return mix(top, bottom, f.y)
+	}
+
+	func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+
+		colorTex := imageSrc0At(texCoord)
+
+		if colorTex.a == 0 {
+			return colorTex
+		}
+
+		rgb := colorTex.rgb / colorTex.a
+
+		rgb *= Exposure
+		rgb = (rgb-0.5)*Contrast + 0.5
+		rgb = mix(vec3(dot(rgb, vec3(0.299, 0.587, 0.114))), rgb, Saturation)
+		rgb = pow(clamp(rgb, 0, 1), vec3(1/Gamma))
+
+		if LUTSize > 0 {
+			scaled := rgb * (LUTSize - 1)
+			tile := floor(scaled.b)
+			rgb = mix(lutBilinear(tile, scaled.rg), lutBilinear(min(tile+1, LUTSize-1), scaled.rg), scaled.b-tile)
+		}
+
+		return vec4(rgb*colorTex.a, colorTex.a)
+
+	}
+	`,
+)
+
+// ColorGrading holds settings for a Camera's color grading, which adjusts the tone of everything the Camera renders as a final pass, so scenes
+// can be graded (for example, made warmer, darker, or more washed out) without custom shaders. Like AmbientOcclusion, color grading is applied
+// when the Camera's ColorTexture() is retrieved after rendering, so it's only applied once no matter how many times Render() is called in a frame,
+// and it costs a full-screen shader pass. The adjustments are applied in the order of exposure, contrast, saturation, gamma, and then the LUT.
+type ColorGrading struct {
+	On         bool
+	Exposure   float64 // A multiplier for the brightness of the rendered colors. Defaults to 1.
+	Contrast   float64 // How far colors are pushed away from (or, below 1, pulled towards) middle gray. Defaults to 1.
+	Saturation float64 // How saturated colors are; 0 is grayscale, while values above 1 are more saturated. Defaults to 1.
+	Gamma      float64 // The gamma to correct rendered colors with; values above 1 brighten midtones, while values below 1 darken them. Defaults to 1.
+
+	// LUT is an optional color lookup table texture that colors are mapped through after the other adjustments. LUTs are laid out as a horizontal
+	// strip of square tiles, N tiles of N x N pixels each (i.e. 256x16 or 1024x32), with red increasing from left to right within each tile,
+	// green increasing from top to bottom, and blue increasing from tile to tile. A LUT texture can be made by grading an image of a neutral LUT
+	// in an image editor. Due to a limitation in Ebiten, the LUT can't be larger than the Camera's texture. If nil (the default), no LUT is applied.
+	LUT *ebiten.Image
+
+	shader     *ebiten.Shader
+	texture    *ebiten.Image
+	lutTexture *ebiten.Image
+	lutSource  *ebiten.Image
+	options    *ebiten.DrawRectShaderOptions
+	dirty      bool
+}
+
+func newColorGrading() ColorGrading {
+	return ColorGrading{
+		Exposure:   1,
+		Contrast:   1,
+		Saturation: 1,
+		Gamma:      1,
+	}
+}
+
+// colorGradingTexture returns the source texture provided with the Camera's color grading applied, applying it if the Camera has rendered
+// since it was last applied.
+func (camera *Camera) colorGradingTexture(source *ebiten.Image) *ebiten.Image {
+
+	grading := &camera.ColorGrading
+
+	w, h := source.Size()
+
+	if grading.texture != nil {
+		if tw, th := grading.texture.Size(); tw != w || th != h {
+			grading.texture.Dispose()
+			grading.texture = nil
+			grading.lutTexture.Dispose()
+			grading.lutTexture = nil
+		}
+	}
+
+	if grading.texture == nil {
+		grading.texture = ebiten.NewImage(w, h)
+		grading.lutTexture = ebiten.NewImage(w, h)
+		grading.lutSource = nil
+		grading.dirty = true
+	}
+
+	if !grading.dirty {
+		return grading.texture
+	}
+
+	grading.dirty = false
+
+	if grading.shader == nil {
+
+		shader, err := ebiten.NewShader(colorGradingShaderSrc)
+
+		if err != nil {
+			panic(err)
+		}
+
+		grading.shader = shader
+		grading.options = &ebiten.DrawRectShaderOptions{
+			CompositeMode: ebiten.CompositeModeCopy,
+			Uniforms:      map[string]interface{}{},
+		}
+
+	}
+
+	lutSize := 0
+
+	if grading.LUT != nil {
+
+		lutW, lutH := grading.LUT.Size()
+
+		if lutW != lutH*lutH {
+			panic("error: a ColorGrading LUT must be N * N pixels wide and N pixels tall")
+		}
+
+		if lutW > w || lutH > h {
+			panic("error: a ColorGrading LUT can't be larger than the Camera's texture")
+		}
+
+		// Shader images must all be the same size, so the LUT is copied into a texture the size of the Camera's.
+		if grading.LUT != grading.lutSource {
+			grading.lutTexture.Clear()
+			grading.lutTexture.DrawImage(grading.LUT, nil)
+			grading.lutSource = grading.LUT
+		}
+
+		lutSize = lutH
+
+	}
+
+	grading.options.Uniforms["Exposure"] = float32(grading.Exposure)
+	grading.options.Uniforms["Contrast"] = float32(grading.Contrast)
+	grading.options.Uniforms["Saturation"] = float32(grading.Saturation)
+	grading.options.Uniforms["Gamma"] = float32(grading.Gamma)
+	grading.options.Uniforms["LUTSize"] = float32(lutSize)
+
+	grading.options.Images[0] = source
+	grading.options.Images[1] = grading.lutTexture
+
+	grading.texture.DrawRectShader(w, h, grading.shader, grading.options)
+
+	return grading.texture
+
+}
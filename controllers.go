@@ -0,0 +1,410 @@
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kvartborg/vector"
+)
+
+// mouseLook tracks the mouse cursor's movement between ticks for the camera controllers.
+type mouseLook struct {
+	prevX, prevY int
+	hasPrev      bool
+}
+
+// delta returns how far the mouse cursor has moved since the last call to delta(). The first call returns 0, 0.
+func (look *mouseLook) delta() (float64, float64) {
+
+	mx, my := ebiten.CursorPosition()
+
+	dx, dy := 0.0, 0.0
+
+	if look.hasPrev {
+		dx = float64(mx - look.prevX)
+		dy = float64(my - look.prevY)
+	}
+
+	look.prevX = mx
+	look.prevY = my
+	look.hasPrev = true
+
+	return dx, dy
+
+}
+
+// reset makes the next call to delta() return 0, 0, so that the camera doesn't jump when mouse-look resumes.
+func (look *mouseLook) reset() {
+	look.hasPrev = false
+}
+
+// yawPitchRotation returns a rotation Matrix4 that rotates by the pitch around the X axis (tilting up and down), and then by the yaw around
+// the Y axis (turning left and right).
+func yawPitchRotation(yaw, pitch float64) Matrix4 {
+	return NewMatrix4Rotate(1, 0, 0, pitch).Mult(NewMatrix4Rotate(0, 1, 0, yaw))
+}
+
+// dampingFactor returns how far a damped value should move towards its target value this tick. A damping value of 0 snaps to the target
+// immediately, while values approaching 1 move more and more slowly; the result is independent of the frame rate.
+func dampingFactor(damping, dt float64) float64 {
+	if damping <= 0 {
+		return 1
+	}
+	return 1 - math.Pow(math.Min(damping, 0.999), dt*60)
+}
+
+// OrbitController is a camera controller that orbits a Camera around a center point (or a target Node), rotating when the mouse is dragged
+// and zooming in and out with the mouse wheel. This is useful for model viewers, editors, or strategy games. To use it, create it with
+// NewOrbitController() and call OrbitController.Update() once per tick from your game's Update() function.
+type OrbitController struct {
+	Camera *Camera       // The Camera to control
+	Target INode         // The Node to orbit around; if nil, the Camera orbits around Center instead
+	Center vector.Vector // The point to orbit around if Target is nil; if Target is set, this is an offset from the Target's world position. Defaults to (0, 0, 0).
+
+	Yaw         float64 // The horizontal angle of the Camera around the center in radians
+	Pitch       float64 // The vertical angle of the Camera around the center in radians; negative values look down on the center
+	Distance    float64 // How far the Camera is from the center. Defaults to 10.
+	MinDistance float64 // How close the Camera can zoom in to the center. Defaults to 1.
+	MaxDistance float64 // How far the Camera can zoom out from the center. Defaults to 100.
+	MinPitch    float64 // The minimum pitch in radians. Defaults to just past -90 degrees (looking straight down).
+	MaxPitch    float64 // The maximum pitch in radians. Defaults to just short of 90 degrees (looking straight up).
+
+	RotateButton ebiten.MouseButton // The mouse button that must be held to orbit. Defaults to ebiten.MouseButtonLeft.
+	RotateSpeed  float64            // How far the Camera orbits in radians for each pixel the mouse moves. Defaults to 0.01.
+	ZoomSpeed    float64            // How much the distance changes for each notch of the mouse wheel, as a percentage of the current distance. Defaults to 0.1.
+	Damping      float64            // How smoothly the Camera follows changes to its yaw, pitch, and distance, ranging from 0 (not at all) to below 1 (very smoothly). Defaults to 0.8.
+	DisableMouse bool               // If input from the mouse is ignored (so that you can set Yaw, Pitch, and Distance yourself). Defaults to false.
+
+	currentYaw       float64
+	currentPitch     float64
+	currentDistance  float64
+	initialized      bool
+	mouse            mouseLook
+	prevButtonDown   bool
+	prevDisableMouse bool
+}
+
+// NewOrbitController returns a new OrbitController controlling the Camera provided.
+func NewOrbitController(camera *Camera) *OrbitController {
+	return &OrbitController{
+		Camera:       camera,
+		Center:       vector.Vector{0, 0, 0},
+		Pitch:        -math.Pi / 8,
+		Distance:     10,
+		MinDistance:  1,
+		MaxDistance:  100,
+		MinPitch:     -math.Pi/2 + 0.01,
+		MaxPitch:     math.Pi/2 - 0.01,
+		RotateButton: ebiten.MouseButtonLeft,
+		RotateSpeed:  0.01,
+		ZoomSpeed:    0.1,
+		Damping:      0.8,
+	}
+}
+
+// Update handles the OrbitController's input and positions the Camera. dt is the time (in seconds) since the last time Update() was called
+// (so 1.0 / 60 for a game running at 60 FPS); it's used to keep damping consistent regardless of frame rate.
+func (orbit *OrbitController) Update(dt float64) {
+
+	if orbit.Camera == nil {
+		return
+	}
+
+	if !orbit.DisableMouse {
+
+		buttonDown := ebiten.IsMouseButtonPressed(orbit.RotateButton)
+
+		if !buttonDown || !orbit.prevButtonDown || orbit.prevDisableMouse {
+			orbit.mouse.reset()
+		}
+
+		if buttonDown {
+			dx, dy := orbit.mouse.delta()
+			orbit.Yaw -= dx * orbit.RotateSpeed
+			orbit.Pitch -= dy * orbit.RotateSpeed
+		}
+
+		orbit.prevButtonDown = buttonDown
+
+		if _, wheel := ebiten.Wheel(); wheel != 0 {
+			orbit.Distance *= math.Pow(1-orbit.ZoomSpeed, wheel)
+		}
+
+	}
+
+	orbit.prevDisableMouse = orbit.DisableMouse
+
+	orbit.Pitch = math.Max(math.Min(orbit.Pitch, orbit.MaxPitch), orbit.MinPitch)
+	orbit.Distance = math.Max(math.Min(orbit.Distance, orbit.MaxDistance), orbit.MinDistance)
+
+	if !orbit.initialized {
+		orbit.currentYaw = orbit.Yaw
+		orbit.currentPitch = orbit.Pitch
+		orbit.currentDistance = orbit.Distance
+		orbit.initialized = true
+	} else {
+		t := dampingFactor(orbit.Damping, dt)
+		orbit.currentYaw += (orbit.Yaw - orbit.currentYaw) * t
+		orbit.currentPitch += (orbit.Pitch - orbit.currentPitch) * t
+		orbit.currentDistance += (orbit.Distance - orbit.currentDistance) * t
+	}
+
+	rotation := yawPitchRotation(orbit.currentYaw, orbit.currentPitch)
+
+	// The Camera looks down -Z, so we place it along its own +Z (forward) axis from the center to look at it.
+	orbit.Camera.SetWorldRotation(rotation)
+	orbit.Camera.SetWorldPosition(orbit.CenterPosition().Add(rotation.Forward().Scale(orbit.currentDistance)))
+
+}
+
+// CenterPosition returns the world position the OrbitController is orbiting around.
+func (orbit *OrbitController) CenterPosition() vector.Vector {
+	if orbit.Target != nil {
+		return orbit.Target.WorldPosition().Add(orbit.Center)
+	}
+	return orbit.Center.Clone()
+}
+
+// FirstPersonController is a camera controller that rotates a Camera with the mouse and moves it with the keyboard (W, A, S, and D by
+// default), as in a first-person game. To use it, create it with NewFirstPersonController() and call FirstPersonController.Update()
+// once per tick from your game's Update() function. You'll probably also want to capture the mouse cursor using
+// ebiten.SetCursorMode(ebiten.CursorModeCaptured) so that it can't leave the window.
+type FirstPersonController struct {
+	Camera *Camera // The Camera to control
+
+	Yaw              float64 // The horizontal angle of the Camera in radians
+	Pitch            float64 // The vertical angle of the Camera in radians
+	MinPitch         float64 // The minimum pitch in radians. Defaults to just past -90 degrees (looking straight down).
+	MaxPitch         float64 // The maximum pitch in radians. Defaults to just short of 90 degrees (looking straight up).
+	MouseSensitivity float64 // How far the Camera turns in radians for each pixel the mouse moves. Defaults to 0.005.
+
+	MoveSpeed        float64 // How fast the Camera moves in world units per second. Defaults to 5.
+	SprintMultiplier float64 // How much faster the Camera moves while the SprintKey is held. Defaults to 2.
+	// If the Camera flies in the direction it's looking (true), or moves only horizontally, like someone walking (false).
+	// When flying, the UpKey and DownKey move the Camera vertically. Defaults to false.
+	Fly bool
+
+	ForwardKey   ebiten.Key // The key that moves the Camera forward. Defaults to W.
+	BackwardKey  ebiten.Key // The key that moves the Camera backward. Defaults to S.
+	LeftKey      ebiten.Key // The key that moves the Camera left. Defaults to A.
+	RightKey     ebiten.Key // The key that moves the Camera right. Defaults to D.
+	UpKey        ebiten.Key // The key that moves the Camera up while flying. Defaults to Space.
+	DownKey      ebiten.Key // The key that moves the Camera down while flying. Defaults to Control.
+	SprintKey    ebiten.Key // The key that makes the Camera move faster. Defaults to Shift.
+	DisableMouse bool       // If mouse-look is disabled (for example, while a menu is open). Defaults to false.
+
+	mouse mouseLook
+}
+
+// NewFirstPersonController returns a new FirstPersonController controlling the Camera provided.
+func NewFirstPersonController(camera *Camera) *FirstPersonController {
+	return &FirstPersonController{
+		Camera:           camera,
+		MinPitch:         -math.Pi/2 + 0.01,
+		MaxPitch:         math.Pi/2 - 0.01,
+		MouseSensitivity: 0.005,
+		MoveSpeed:        5,
+		SprintMultiplier: 2,
+		ForwardKey:       ebiten.KeyW,
+		BackwardKey:      ebiten.KeyS,
+		LeftKey:          ebiten.KeyA,
+		RightKey:         ebiten.KeyD,
+		UpKey:            ebiten.KeySpace,
+		DownKey:          ebiten.KeyControl,
+		SprintKey:        ebiten.KeyShift,
+	}
+}
+
+// Update handles the FirstPersonController's input, rotating and moving the Camera. dt is the time (in seconds) since the last time Update()
+// was called (so 1.0 / 60 for a game running at 60 FPS).
+func (fp *FirstPersonController) Update(dt float64) {
+
+	if fp.Camera == nil {
+		return
+	}
+
+	if fp.DisableMouse {
+		fp.mouse.reset()
+	} else {
+		dx, dy := fp.mouse.delta()
+		fp.Yaw -= dx * fp.MouseSensitivity
+		fp.Pitch -= dy * fp.MouseSensitivity
+	}
+
+	fp.Pitch = math.Max(math.Min(fp.Pitch, fp.MaxPitch), fp.MinPitch)
+
+	rotation := yawPitchRotation(fp.Yaw, fp.Pitch)
+	fp.Camera.SetLocalRotation(rotation)
+
+	// The Camera looks down -Z, so its forward vector is inverted.
+	forward := NewVector3FromVector(rotation.Forward()).Invert()
+	right := NewVector3FromVector(rotation.Right())
+
+	if !fp.Fly {
+		forward = NewVector3(forward.X, 0, forward.Z).Unit()
+		right = NewVector3(right.X, 0, right.Z).Unit()
+	}
+
+	move := NewVector3(0, 0, 0)
+
+	if ebiten.IsKeyPressed(fp.ForwardKey) {
+		move = move.Add(forward)
+	}
+	if ebiten.IsKeyPressed(fp.BackwardKey) {
+		move = move.Sub(forward)
+	}
+	if ebiten.IsKeyPressed(fp.RightKey) {
+		move = move.Add(right)
+	}
+	if ebiten.IsKeyPressed(fp.LeftKey) {
+		move = move.Sub(right)
+	}
+
+	if fp.Fly {
+		if ebiten.IsKeyPressed(fp.UpKey) {
+			move.Y++
+		}
+		if ebiten.IsKeyPressed(fp.DownKey) {
+			move.Y--
+		}
+	}
+
+	// Normalizing the movement keeps the Camera from moving faster diagonally.
+	if move.MagnitudeSquared() > 0 {
+
+		speed := fp.MoveSpeed * dt
+		if ebiten.IsKeyPressed(fp.SprintKey) {
+			speed *= fp.SprintMultiplier
+		}
+
+		move = move.Unit().Scale(speed)
+		fp.Camera.Move(move.X, move.Y, move.Z)
+
+	}
+
+}
+
+// ThirdPersonController is a camera controller that follows a target Node from behind on a "boom", rotating around it with the mouse.
+// If any of the BoundingObjects in Colliders block the boom, it shortens so that the Camera stays in front of them (rather than clipping through
+// walls), extending again smoothly once the way is clear. To use it, create it with NewThirdPersonController() and call
+// ThirdPersonController.Update() once per tick from your game's Update() function (after moving the target).
+type ThirdPersonController struct {
+	Camera    *Camera          // The Camera to control
+	Target    INode            // The Node to follow
+	Offset    vector.Vector    // The offset from the Target's world position that the boom pivots around. Defaults to (0, 1.5, 0).
+	Colliders []BoundingObject // The BoundingObjects that block the boom. These shouldn't include the Target's own bounds.
+
+	Yaw              float64 // The horizontal angle of the boom in radians
+	Pitch            float64 // The vertical angle of the boom in radians; negative values look down on the Target
+	MinPitch         float64 // The minimum pitch in radians. Defaults to -80 degrees.
+	MaxPitch         float64 // The maximum pitch in radians. Defaults to 60 degrees.
+	MouseSensitivity float64 // How far the boom turns in radians for each pixel the mouse moves. Defaults to 0.005.
+	DisableMouse     bool    // If mouse-look is disabled (for example, while a menu is open). Defaults to false.
+
+	Distance      float64 // The length of the boom when it isn't blocked. Defaults to 5.
+	MinDistance   float64 // The shortest the boom can get when it's blocked. Defaults to 0.5.
+	ProbeRadius   float64 // The radius of the sphere swept along the boom to check if it's blocked; this keeps the Camera's near plane out of walls. Defaults to 0.25.
+	ReturnDamping float64 // How smoothly the boom extends again after being blocked, ranging from 0 (not at all) to below 1 (very smoothly). Defaults to 0.85.
+
+	currentDistance float64
+	initialized     bool
+	probe           *BoundingSphere
+	mouse           mouseLook
+}
+
+// NewThirdPersonController returns a new ThirdPersonController controlling the Camera provided, and following the target Node given.
+func NewThirdPersonController(camera *Camera, target INode) *ThirdPersonController {
+	return &ThirdPersonController{
+		Camera:           camera,
+		Target:           target,
+		Offset:           vector.Vector{0, 1.5, 0},
+		Pitch:            -math.Pi / 10,
+		MinPitch:         -80 * math.Pi / 180,
+		MaxPitch:         60 * math.Pi / 180,
+		MouseSensitivity: 0.005,
+		Distance:         5,
+		MinDistance:      0.5,
+		ProbeRadius:      0.25,
+		ReturnDamping:    0.85,
+		probe:            NewBoundingSphere("boom probe", 0.25),
+	}
+}
+
+// Update handles the ThirdPersonController's input, positions the Camera, and shortens the boom if it's blocked. dt is the time (in seconds)
+// since the last time Update() was called (so 1.0 / 60 for a game running at 60 FPS).
+func (tp *ThirdPersonController) Update(dt float64) {
+
+	if tp.Camera == nil || tp.Target == nil {
+		return
+	}
+
+	if tp.DisableMouse {
+		tp.mouse.reset()
+	} else {
+		dx, dy := tp.mouse.delta()
+		tp.Yaw -= dx * tp.MouseSensitivity
+		tp.Pitch -= dy * tp.MouseSensitivity
+	}
+
+	tp.Pitch = math.Max(math.Min(tp.Pitch, tp.MaxPitch), tp.MinPitch)
+
+	rotation := yawPitchRotation(tp.Yaw, tp.Pitch)
+	pivot := tp.PivotPosition()
+	direction := rotation.Forward()
+
+	allowed := tp.boomLength(pivot, direction)
+
+	if !tp.initialized || allowed < tp.currentDistance {
+		// Shorten the boom immediately so the Camera never ends up inside of a wall, even for a frame.
+		tp.currentDistance = allowed
+		tp.initialized = true
+	} else {
+		tp.currentDistance += (allowed - tp.currentDistance) * dampingFactor(tp.ReturnDamping, dt)
+	}
+
+	tp.Camera.SetWorldRotation(rotation)
+	tp.Camera.SetWorldPosition(pivot.Add(direction.Scale(tp.currentDistance)))
+
+}
+
+// boomLength sweeps the probe sphere outward along the boom, returning the furthest distance the Camera can be from the pivot without being blocked.
+func (tp *ThirdPersonController) boomLength(pivot, direction vector.Vector) float64 {
+
+	distance := math.Max(tp.Distance, tp.MinDistance)
+
+	if len(tp.Colliders) == 0 || tp.ProbeRadius <= 0 {
+		return distance
+	}
+
+	tp.probe.Radius = tp.ProbeRadius
+
+	// Step by half of the probe's radius so that thin walls can't slip between steps.
+	steps := int(math.Ceil(distance / (tp.ProbeRadius / 2)))
+
+	for i := 1; i <= steps; i++ {
+
+		d := distance * float64(i) / float64(steps)
+
+		tp.probe.SetLocalPosition(pivot.Add(direction.Scale(d)))
+
+		for _, collider := range tp.Colliders {
+			if tp.probe.Colliding(collider) {
+				return math.Max(distance*float64(i-1)/float64(steps), tp.MinDistance)
+			}
+		}
+
+	}
+
+	return distance
+
+}
+
+// PivotPosition returns the world position that the ThirdPersonController's boom pivots around (the Target's world position plus the Offset).
+func (tp *ThirdPersonController) PivotPosition() vector.Vector {
+	return tp.Target.WorldPosition().Add(tp.Offset)
+}
+
+// CurrentDistance returns the current length of the ThirdPersonController's boom, which is shorter than Distance if the boom is blocked.
+func (tp *ThirdPersonController) CurrentDistance() float64 {
+	return tp.currentDistance
+}
@@ -0,0 +1,286 @@
+package tetra3d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kvartborg/vector"
+)
+
+// Curve represents a parametric curve through 3D space. Curves are independent of Nodes and Paths, so they can be used for anything that needs
+// smooth spline math, like camera tracks, projectile arcs, or procedural geometry.
+type Curve interface {
+	// Evaluate returns the position on the Curve at the given percentage (t, ranging from 0 to 1). Note that t isn't proportional to distance
+	// travelled along the Curve; use EvaluateDistance() for that.
+	Evaluate(t float64) vector.Vector
+	// Tangent returns the normalized direction of the Curve at the given percentage (t, ranging from 0 to 1).
+	Tangent(t float64) vector.Vector
+	// Length returns the (approximate) length of the Curve.
+	Length() float64
+	// EvaluateDistance returns the position on the Curve at the given distance along it, so that evenly spaced distances result in evenly
+	// spaced positions (i.e. arc-length reparameterization).
+	EvaluateDistance(distance float64) vector.Vector
+	// PercentageAtDistance returns the percentage (t) that corresponds to the given distance along the Curve.
+	PercentageAtDistance(distance float64) float64
+}
+
+// curveSamplesPerSegment is how many samples are taken of each segment of a Curve to approximate its arc length.
+const curveSamplesPerSegment = 16
+
+// arcLengthTable holds the cumulative lengths of a curve at evenly spaced percentages, used to reparameterize it by arc length.
+type arcLengthTable struct {
+	lengths []float64
+}
+
+func (table *arcLengthTable) build(evaluate func(t float64) Vector3, sampleCount int) {
+
+	if sampleCount < 1 {
+		sampleCount = 1
+	}
+
+	table.lengths = table.lengths[:0]
+	table.lengths = append(table.lengths, 0)
+
+	prev := evaluate(0)
+	total := 0.0
+
+	for i := 1; i <= sampleCount; i++ {
+		next := evaluate(float64(i) / float64(sampleCount))
+		total += next.Distance(prev)
+		table.lengths = append(table.lengths, total)
+		prev = next
+	}
+
+}
+
+func (table *arcLengthTable) length() float64 {
+	if len(table.lengths) == 0 {
+		return 0
+	}
+	return table.lengths[len(table.lengths)-1]
+}
+
+func (table *arcLengthTable) percentageAt(distance float64) float64 {
+
+	total := table.length()
+
+	if total == 0 || distance <= 0 {
+		return 0
+	} else if distance >= total {
+		return 1
+	}
+
+	index := sort.SearchFloat64s(table.lengths, distance)
+
+	start := table.lengths[index-1]
+	end := table.lengths[index]
+
+	segmentPerc := 0.0
+	if end > start {
+		segmentPerc = (distance - start) / (end - start)
+	}
+
+	return (float64(index-1) + segmentPerc) / float64(len(table.lengths)-1)
+
+}
+
+// curveSegment returns the segment index and the percentage through that segment for the given percentage through a curve of segmentCount segments.
+func curveSegment(t float64, segmentCount int) (int, float64) {
+
+	t = math.Max(math.Min(t, 1), 0)
+
+	s := t * float64(segmentCount)
+	index := int(s)
+	if index >= segmentCount {
+		index = segmentCount - 1
+	}
+
+	return index, s - float64(index)
+
+}
+
+// CatmullRomCurve is a Curve that passes smoothly through all of its points (a uniform Catmull-Rom spline).
+type CatmullRomCurve struct {
+	Points []vector.Vector // The points the Curve passes through. If you alter the Points, call UpdateArcLength() afterwards.
+	Closed bool            // If the Curve loops back around from its last point to its first. If you alter this, call UpdateArcLength() afterwards.
+	table  arcLengthTable
+}
+
+// NewCatmullRomCurve returns a new CatmullRomCurve passing through the provided points. At least two points are required.
+func NewCatmullRomCurve(closed bool, points ...vector.Vector) *CatmullRomCurve {
+
+	if len(points) < 2 {
+		panic("Error: NewCatmullRomCurve() requires at least two points.")
+	}
+
+	curve := &CatmullRomCurve{
+		Points: points,
+		Closed: closed,
+	}
+	curve.UpdateArcLength()
+	return curve
+
+}
+
+// UpdateArcLength recalculates the Curve's arc length table; this should be called after altering the Curve's Points.
+func (curve *CatmullRomCurve) UpdateArcLength() {
+	curve.table.build(curve.evaluate, curve.segmentCount()*curveSamplesPerSegment)
+}
+
+func (curve *CatmullRomCurve) segmentCount() int {
+	if curve.Closed {
+		return len(curve.Points)
+	}
+	return len(curve.Points) - 1
+}
+
+func (curve *CatmullRomCurve) point(index int) Vector3 {
+
+	count := len(curve.Points)
+
+	if curve.Closed {
+		index = ((index % count) + count) % count
+	} else if index < 0 {
+		index = 0
+	} else if index >= count {
+		index = count - 1
+	}
+
+	return NewVector3FromVector(curve.Points[index])
+
+}
+
+func (curve *CatmullRomCurve) segmentPoints(t float64) (p0, p1, p2, p3 Vector3, u float64) {
+	index, u := curveSegment(t, curve.segmentCount())
+	return curve.point(index - 1), curve.point(index), curve.point(index + 1), curve.point(index + 2), u
+}
+
+func (curve *CatmullRomCurve) evaluate(t float64) Vector3 {
+
+	p0, p1, p2, p3, u := curve.segmentPoints(t)
+
+	a := p1.Scale(2)
+	b := p2.Sub(p0)
+	c := p0.Scale(2).Sub(p1.Scale(5)).Add(p2.Scale(4)).Sub(p3)
+	d := p1.Scale(3).Sub(p0).Sub(p2.Scale(3)).Add(p3)
+
+	return a.Add(b.Scale(u)).Add(c.Scale(u * u)).Add(d.Scale(u * u * u)).Scale(0.5)
+
+}
+
+// Evaluate returns the position on the Curve at the given percentage (t, ranging from 0 to 1).
+func (curve *CatmullRomCurve) Evaluate(t float64) vector.Vector {
+	return curve.evaluate(t).ToVector()
+}
+
+// Tangent returns the normalized direction of the Curve at the given percentage (t, ranging from 0 to 1).
+func (curve *CatmullRomCurve) Tangent(t float64) vector.Vector {
+
+	p0, p1, p2, p3, u := curve.segmentPoints(t)
+
+	b := p2.Sub(p0)
+	c := p0.Scale(2).Sub(p1.Scale(5)).Add(p2.Scale(4)).Sub(p3)
+	d := p1.Scale(3).Sub(p0).Sub(p2.Scale(3)).Add(p3)
+
+	return b.Add(c.Scale(2 * u)).Add(d.Scale(3 * u * u)).Unit().ToVector()
+
+}
+
+// Length returns the approximate length of the Curve.
+func (curve *CatmullRomCurve) Length() float64 {
+	return curve.table.length()
+}
+
+// PercentageAtDistance returns the percentage (t) that corresponds to the given distance along the Curve.
+func (curve *CatmullRomCurve) PercentageAtDistance(distance float64) float64 {
+	return curve.table.percentageAt(distance)
+}
+
+// EvaluateDistance returns the position on the Curve at the given distance along it.
+func (curve *CatmullRomCurve) EvaluateDistance(distance float64) vector.Vector {
+	return curve.Evaluate(curve.PercentageAtDistance(distance))
+}
+
+// BezierCurve is a Curve made up of one or more connected cubic Bezier segments. The first segment is defined by the first four points (start,
+// first control point, second control point, end), and each following segment is defined by the previous segment's end and three more points.
+type BezierCurve struct {
+	Points []vector.Vector // The points of the Curve. If you alter the Points, call UpdateArcLength() afterwards.
+	table  arcLengthTable
+}
+
+// NewBezierCurve returns a new BezierCurve using the provided points. The number of points must be 3 * the number of segments + 1 (so 4, 7, 10, etc).
+func NewBezierCurve(points ...vector.Vector) *BezierCurve {
+
+	if len(points) < 4 || (len(points)-1)%3 != 0 {
+		panic("Error: NewBezierCurve() requires 3 * segment count + 1 points (i.e. 4, 7, 10, etc).")
+	}
+
+	curve := &BezierCurve{
+		Points: points,
+	}
+	curve.UpdateArcLength()
+	return curve
+
+}
+
+// UpdateArcLength recalculates the Curve's arc length table; this should be called after altering the Curve's Points.
+func (curve *BezierCurve) UpdateArcLength() {
+	curve.table.build(curve.evaluate, curve.segmentCount()*curveSamplesPerSegment)
+}
+
+func (curve *BezierCurve) segmentCount() int {
+	return (len(curve.Points) - 1) / 3
+}
+
+func (curve *BezierCurve) segmentPoints(t float64) (p0, p1, p2, p3 Vector3, u float64) {
+	index, u := curveSegment(t, curve.segmentCount())
+	i := index * 3
+	return NewVector3FromVector(curve.Points[i]), NewVector3FromVector(curve.Points[i+1]), NewVector3FromVector(curve.Points[i+2]), NewVector3FromVector(curve.Points[i+3]), u
+}
+
+func (curve *BezierCurve) evaluate(t float64) Vector3 {
+
+	p0, p1, p2, p3, u := curve.segmentPoints(t)
+
+	inv := 1 - u
+
+	return p0.Scale(inv * inv * inv).
+		Add(p1.Scale(3 * inv * inv * u)).
+		Add(p2.Scale(3 * inv * u * u)).
+		Add(p3.Scale(u * u * u))
+
+}
+
+// Evaluate returns the position on the Curve at the given percentage (t, ranging from 0 to 1).
+func (curve *BezierCurve) Evaluate(t float64) vector.Vector {
+	return curve.evaluate(t).ToVector()
+}
+
+// Tangent returns the normalized direction of the Curve at the given percentage (t, ranging from 0 to 1).
+func (curve *BezierCurve) Tangent(t float64) vector.Vector {
+
+	p0, p1, p2, p3, u := curve.segmentPoints(t)
+
+	inv := 1 - u
+
+	return p1.Sub(p0).Scale(3 * inv * inv).
+		Add(p2.Sub(p1).Scale(6 * inv * u)).
+		Add(p3.Sub(p2).Scale(3 * u * u)).
+		Unit().ToVector()
+
+}
+
+// Length returns the approximate length of the Curve.
+func (curve *BezierCurve) Length() float64 {
+	return curve.table.length()
+}
+
+// PercentageAtDistance returns the percentage (t) that corresponds to the given distance along the Curve.
+func (curve *BezierCurve) PercentageAtDistance(distance float64) float64 {
+	return curve.table.percentageAt(distance)
+}
+
+// EvaluateDistance returns the position on the Curve at the given distance along it.
+func (curve *BezierCurve) EvaluateDistance(distance float64) vector.Vector {
+	return curve.Evaluate(curve.PercentageAtDistance(distance))
+}
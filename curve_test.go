@@ -0,0 +1,77 @@
+package tetra3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kvartborg/vector"
+)
+
+func TestCurves(t *testing.T) {
+
+	line := NewBezierCurve(vector.Vector{0, 0, 0}, vector.Vector{1, 0, 0}, vector.Vector{2, 0, 0}, vector.Vector{3, 0, 0})
+	arc := NewBezierCurve(vector.Vector{0, 0, 0}, vector.Vector{0, 1, 0}, vector.Vector{1, 1, 0}, vector.Vector{1, 0, 0})
+	catmull := NewCatmullRomCurve(false, vector.Vector{0, 0, 0}, vector.Vector{1, 1, 0}, vector.Vector{2, 0, 0})
+	loop := NewCatmullRomCurve(true, vector.Vector{0, 0, 0}, vector.Vector{1, 0, 0}, vector.Vector{1, 1, 0}, vector.Vector{0, 1, 0})
+
+	tests := []struct {
+		name    string
+		curve   Curve
+		t       float64
+		point   vector.Vector
+		tangent vector.Vector
+	}{
+		{"bezier line start", line, 0, vector.Vector{0, 0, 0}, vector.Vector{1, 0, 0}},
+		{"bezier line middle", line, 0.5, vector.Vector{1.5, 0, 0}, vector.Vector{1, 0, 0}},
+		{"bezier line end", line, 1, vector.Vector{3, 0, 0}, vector.Vector{1, 0, 0}},
+		{"bezier arc start", arc, 0, vector.Vector{0, 0, 0}, vector.Vector{0, 1, 0}},
+		{"bezier arc middle", arc, 0.5, vector.Vector{0.5, 0.75, 0}, vector.Vector{1, 0, 0}},
+		{"bezier arc end", arc, 1, vector.Vector{1, 0, 0}, vector.Vector{0, -1, 0}},
+		{"catmull-rom passes through points", catmull, 0.5, vector.Vector{1, 1, 0}, vector.Vector{1, 0, 0}},
+		{"catmull-rom end", catmull, 1, vector.Vector{2, 0, 0}, nil},
+		{"closed catmull-rom loops", loop, 1, vector.Vector{0, 0, 0}, nil},
+		{"closed catmull-rom point", loop, 0.25, vector.Vector{1, 0, 0}, nil},
+	}
+
+	for _, test := range tests {
+		if point := test.curve.Evaluate(test.t); !vectorsEqual(point, test.point) {
+			t.Errorf("%s: Evaluate(%f) = %v, want %v", test.name, test.t, point, test.point)
+		}
+		if test.tangent != nil {
+			if tangent := test.curve.Tangent(test.t); !vectorsEqual(tangent, test.tangent) {
+				t.Errorf("%s: Tangent(%f) = %v, want %v", test.name, test.t, tangent, test.tangent)
+			}
+		}
+	}
+
+}
+
+func TestCurveArcLength(t *testing.T) {
+
+	// The control points of this line are unevenly spaced, so t doesn't map linearly to distance along it.
+	uneven := NewBezierCurve(vector.Vector{0, 0, 0}, vector.Vector{0.1, 0, 0}, vector.Vector{0.2, 0, 0}, vector.Vector{3, 0, 0})
+
+	tests := []struct {
+		name     string
+		curve    Curve
+		length   float64
+		distance float64
+		point    vector.Vector
+	}{
+		{"even line", NewBezierCurve(vector.Vector{0, 0, 0}, vector.Vector{1, 0, 0}, vector.Vector{2, 0, 0}, vector.Vector{3, 0, 0}), 3, 1.5, vector.Vector{1.5, 0, 0}},
+		{"uneven line", uneven, 3, 1, vector.Vector{1, 0, 0}},
+		{"uneven line start", uneven, 3, 0, vector.Vector{0, 0, 0}},
+		{"uneven line end", uneven, 3, 3, vector.Vector{3, 0, 0}},
+		{"catmull-rom line", NewCatmullRomCurve(false, vector.Vector{0, 0, 0}, vector.Vector{0, 2, 0}, vector.Vector{0, 4, 0}), 4, 3, vector.Vector{0, 3, 0}},
+	}
+
+	for _, test := range tests {
+		if length := test.curve.Length(); math.Abs(length-test.length) > 0.01 {
+			t.Errorf("%s: Length() = %f, want %f", test.name, length, test.length)
+		}
+		if point := test.curve.EvaluateDistance(test.distance); point.Sub(test.point).Magnitude() > 0.01 {
+			t.Errorf("%s: EvaluateDistance(%f) = %v, want %v", test.name, test.distance, point, test.point)
+		}
+	}
+
+}
@@ -296,7 +296,8 @@ func LoadDAEData(data []byte, options *DaeLoadOptions) (*Library, error) {
 
 		mesh := NewMesh(geo.Name)
 		mat := daeURLsToMaterials[geo.Triangles.MaterialName]
-		mesh.AddMeshPart(mat).AddTriangles(verts...)
+		mesh.AddMeshPart(mat).addTriangles(verts...)
+		mesh.SplitMeshParts()
 		mesh.library = scenes
 
 		// if len(normals) > 0 {
@@ -0,0 +1,139 @@
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/kvartborg/vector"
+)
+
+const (
+	DarknessVolumeShapeBox    = iota // The DarknessVolume is a box, sized by its Size
+	DarknessVolumeShapeSphere        // The DarknessVolume is a sphere, sized by its Radius
+)
+
+// DarknessVolume is a Node that darkens anything rendered inside of it, regardless of the Scene's Lights (or whether the Scene is lit at all).
+// This is useful for fog-of-war over unexplored areas of a map, dark caves, or hiding places, without having to change any individual Lights.
+// The darkness fades in from the edges of the volume over its Softness, and DarknessVolumes are sized and shaped in their local space, so
+// they can be scaled and rotated like any other Node. Like lighting, darkness is applied per-vertex. Overlapping DarknessVolumes multiply together.
+// As DarknessVolumes are Nodes, DarknessVolume.DarknessAt() can also be used for gameplay, like checking if a player is hidden in the dark.
+type DarknessVolume struct {
+	*Node
+	Shape    int           // The shape of the DarknessVolume; one of the DarknessVolumeShape constants. Defaults to DarknessVolumeShapeBox.
+	Size     vector.Vector // The width, height, and depth of a box-shaped DarknessVolume. Defaults to {2, 2, 2}.
+	Radius   float64       // The radius of a sphere-shaped DarknessVolume. Defaults to 1.
+	Darkness float32       // How much the DarknessVolume darkens anything fully inside of it, from 0 (not at all) to 1 (completely black). Defaults to 1.
+	Softness float64       // How far in from the DarknessVolume's edges its darkness takes to fully fade in; if 0, the edges are hard. Defaults to 0.5.
+	On       bool          // If the DarknessVolume is on and darkening the Scene.
+
+	workingInverse Matrix4 // The inverse of the DarknessVolume's world transform, used to bring vertices into its local space when rendering.
+	workingCenter  Vector3
+	workingRadius  float64
+}
+
+// NewDarknessVolume returns a new box-shaped DarknessVolume with the name provided.
+func NewDarknessVolume(name string) *DarknessVolume {
+	return &DarknessVolume{
+		Node:     NewNode(name),
+		Shape:    DarknessVolumeShapeBox,
+		Size:     vector.Vector{2, 2, 2},
+		Radius:   1,
+		Darkness: 1,
+		Softness: 0.5,
+		On:       true,
+	}
+}
+
+// Clone returns a clone of the DarknessVolume.
+func (volume *DarknessVolume) Clone() INode {
+
+	clone := NewDarknessVolume(volume.name)
+	clone.Shape = volume.Shape
+	clone.Size = volume.Size.Clone()
+	clone.Radius = volume.Radius
+	clone.Darkness = volume.Darkness
+	clone.Softness = volume.Softness
+	clone.On = volume.On
+
+	clone.Node = volume.Node.Clone().(*Node)
+	for _, child := range clone.children {
+		child.setParent(clone)
+	}
+
+	return clone
+
+}
+
+// DarknessAt returns how much the DarknessVolume darkens the world position provided, ranging from 0 (not at all, as the point is outside
+// of the DarknessVolume or it's off) to the DarknessVolume's Darkness value.
+func (volume *DarknessVolume) DarknessAt(point vector.Vector) float32 {
+
+	if !volume.On {
+		return 0
+	}
+
+	return volume.darknessAt(volume.Transform().Inverted().MultVec3(NewVector3FromVector(point)))
+
+}
+
+// darknessAt returns how much the DarknessVolume darkens the point provided, which is in the DarknessVolume's local space.
+func (volume *DarknessVolume) darknessAt(local Vector3) float32 {
+
+	// depth is how far inside of the DarknessVolume's edges the point is.
+	var depth float64
+
+	if volume.Shape == DarknessVolumeShapeSphere {
+		depth = volume.Radius - local.Magnitude()
+	} else {
+		depth = math.Min(volume.Size[0]/2-math.Abs(local.X), math.Min(volume.Size[1]/2-math.Abs(local.Y), volume.Size[2]/2-math.Abs(local.Z)))
+	}
+
+	if depth <= 0 {
+		return 0
+	}
+
+	if volume.Softness <= 0 || depth >= volume.Softness {
+		return volume.Darkness
+	}
+
+	return volume.Darkness * float32(depth/volume.Softness)
+
+}
+
+// beginRender prepares the DarknessVolume's working values for rendering.
+func (volume *DarknessVolume) beginRender() {
+
+	volume.workingInverse = volume.Transform().Inverted()
+	volume.workingCenter = NewVector3FromVector(volume.WorldPosition())
+
+	radius := volume.Radius
+	if volume.Shape == DarknessVolumeShapeBox {
+		radius = volume.Size.Magnitude() / 2
+	}
+
+	scale := volume.WorldScale()
+	volume.workingRadius = radius * math.Max(math.Abs(scale[0]), math.Max(math.Abs(scale[1]), math.Abs(scale[2])))
+
+}
+
+// overlapsSphere returns if the DarknessVolume could overlap a sphere of the world position and radius provided, as of the last beginRender() call.
+func (volume *DarknessVolume) overlapsSphere(center Vector3, radius float64) bool {
+	return volume.workingCenter.DistanceSquared(center) <= math.Pow(volume.workingRadius+radius, 2)
+}
+
+// AddChildren parents the provided children Nodes to the passed parent Node, inheriting its transformations and being under it in the scenegraph
+// hierarchy. If the children are already parented to other Nodes, they are unparented before doing so.
+func (volume *DarknessVolume) AddChildren(children ...INode) {
+	volume.addChildren(volume, children...)
+}
+
+// Unparent unparents the DarknessVolume from its parent, removing it from the scenegraph.
+func (volume *DarknessVolume) Unparent() {
+	if volume.parent != nil {
+		volume.parent.RemoveChildren(volume)
+	}
+}
+
+// Type returns the NodeType for this object.
+func (volume *DarknessVolume) Type() NodeType {
+	return NodeTypeDarknessVolume
+}
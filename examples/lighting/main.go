@@ -76,7 +76,7 @@ func (g *Game) Init() {
 	light.On = false
 	g.Camera.AddChildren(light)
 
-	// g.Scene.FogMode = tetra3d.FogMultiply
+	// g.Scene.World.FogMode = tetra3d.FogMultiply
 
 	ebiten.SetCursorMode(ebiten.CursorModeCaptured)
 
@@ -203,7 +203,7 @@ func (g *Game) Update() error {
 	}
 
 	if inpututil.IsKeyJustPressed(ebiten.Key1) {
-		g.Scene.LightingOn = !g.Scene.LightingOn
+		g.Scene.World.LightingOn = !g.Scene.World.LightingOn
 	}
 
 	if inpututil.IsKeyJustPressed(ebiten.Key2) {
@@ -217,7 +217,7 @@ func (g *Game) Update() error {
 func (g *Game) Draw(screen *ebiten.Image) {
 
 	// Clear, but with a color - we can use the world lighting color for this.
-	light := g.Scene.Root.Get("World Ambient").(*tetra3d.AmbientLight)
+	light := g.Scene.World.AmbientLight
 	energy := light.Energy
 	bgColor := light.Color.Clone()
 	bgColor.MultiplyRGBA(energy, energy, energy, 1)
@@ -0,0 +1,346 @@
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/kvartborg/vector"
+)
+
+// Plane represents an infinite plane in 3D space, defined by a normal and a distance from the origin. A point P lies on the Plane if
+// Normal.Dot(P) + Distance == 0.
+type Plane struct {
+	Normal   vector.Vector // The normalized direction the Plane faces
+	Distance float64       // The (negated) distance of the Plane from the origin along its normal
+}
+
+// NewPlaneFromNormal returns a new Plane facing the direction of the normal provided and passing through the point provided.
+func NewPlaneFromNormal(normal, point vector.Vector) Plane {
+	n := normal.Unit()
+	return Plane{
+		Normal:   n,
+		Distance: -n.Dot(point),
+	}
+}
+
+// NewPlaneFromPoints returns a new Plane passing through the three points provided. The Plane faces the direction in which the points
+// are ordered counter-clockwise.
+func NewPlaneFromPoints(a, b, c vector.Vector) Plane {
+	normal, _ := b.Sub(a).Cross(c.Sub(a))
+	return NewPlaneFromNormal(normal, a)
+}
+
+// SignedDistance returns the signed distance from the Plane to the point provided; the distance is positive if the point is on the side of the
+// Plane it's facing, and negative if it's behind it.
+func (plane Plane) SignedDistance(point vector.Vector) float64 {
+	return plane.Normal.Dot(point) + plane.Distance
+}
+
+// ClosestPoint returns the closest point on the Plane to the point provided.
+func (plane Plane) ClosestPoint(point vector.Vector) vector.Vector {
+	return point.Sub(plane.Normal.Scale(plane.SignedDistance(point)))
+}
+
+// Ray represents a ray in 3D space - a line starting at an origin point and extending infinitely in a direction.
+type Ray struct {
+	Origin    vector.Vector // The starting point of the Ray
+	Direction vector.Vector // The normalized direction of the Ray
+}
+
+// NewRay returns a new Ray starting at the origin and going in the direction provided.
+func NewRay(origin, direction vector.Vector) Ray {
+	return Ray{
+		Origin:    origin.Clone(),
+		Direction: direction.Unit(),
+	}
+}
+
+// PointAt returns the point along the Ray at the given distance from its origin.
+func (ray Ray) PointAt(distance float64) vector.Vector {
+	return ray.Origin.Add(ray.Direction.Scale(distance))
+}
+
+// IntersectPlane returns the distance along the Ray at which it intersects the Plane, and whether it does at all (i.e. a Ray that is parallel
+// to the Plane or that points away from it doesn't intersect it).
+func (ray Ray) IntersectPlane(plane Plane) (float64, bool) {
+
+	denom := plane.Normal.Dot(ray.Direction)
+
+	if math.Abs(denom) < 1e-9 {
+		return 0, false
+	}
+
+	t := -plane.SignedDistance(ray.Origin) / denom
+
+	return t, t >= 0
+
+}
+
+// IntersectDimensions returns the distance along the Ray at which it intersects the axis-aligned box described by the Dimensions provided, and whether
+// it does at all. If the Ray starts inside the box, the returned distance is 0.
+func (ray Ray) IntersectDimensions(dim Dimensions) (float64, bool) {
+
+	tMin := 0.0
+	tMax := math.MaxFloat64
+
+	for axis := 0; axis < 3; axis++ {
+
+		if math.Abs(ray.Direction[axis]) < 1e-9 {
+
+			// Parallel to the slab; we either are within it or not at all.
+			if ray.Origin[axis] < dim[0][axis] || ray.Origin[axis] > dim[1][axis] {
+				return 0, false
+			}
+
+		} else {
+
+			inv := 1 / ray.Direction[axis]
+			t1 := (dim[0][axis] - ray.Origin[axis]) * inv
+			t2 := (dim[1][axis] - ray.Origin[axis]) * inv
+
+			if t1 > t2 {
+				t1, t2 = t2, t1
+			}
+
+			tMin = math.Max(tMin, t1)
+			tMax = math.Min(tMax, t2)
+
+			if tMin > tMax {
+				return 0, false
+			}
+
+		}
+
+	}
+
+	return tMin, true
+
+}
+
+// IntersectAABB returns the distance along the Ray at which it intersects the BoundingAABB provided, and whether it does at all.
+func (ray Ray) IntersectAABB(box *BoundingAABB) (float64, bool) {
+	box.Transform()
+	pos := box.WorldPosition()
+	half := box.Size.Scale(0.5)
+	return ray.IntersectDimensions(Dimensions{pos.Sub(half), pos.Add(half)})
+}
+
+// IntersectSphere returns the distance along the Ray at which it intersects the sphere with the center and radius provided, and whether it does at all.
+// If the Ray starts inside the sphere, the returned distance is 0.
+func (ray Ray) IntersectSphere(center vector.Vector, radius float64) (float64, bool) {
+
+	diff := ray.Origin.Sub(center)
+	b := diff.Dot(ray.Direction)
+	c := diff.Dot(diff) - radius*radius
+
+	if c <= 0 {
+		return 0, true
+	}
+
+	if b > 0 {
+		return 0, false
+	}
+
+	discriminant := b*b - c
+
+	if discriminant < 0 {
+		return 0, false
+	}
+
+	return -b - math.Sqrt(discriminant), true
+
+}
+
+// IntersectCapsule returns the distance along the Ray at which it intersects the capsule made up of the line segment between the start and end points
+// provided and the radius around it, and whether it does at all. If the Ray starts inside the capsule, the returned distance is 0.
+func (ray Ray) IntersectCapsule(start, end vector.Vector, radius float64) (float64, bool) {
+
+	origin := NewVector3FromVector(ray.Origin)
+	dir := NewVector3FromVector(ray.Direction)
+	segStart := NewVector3FromVector(start)
+	segment := NewVector3FromVector(end).Sub(segStart)
+	diff := origin.Sub(segStart)
+
+	segLengthSquared := segment.Dot(segment)
+
+	// If the Ray starts within radius of the closest point on the segment, it's inside.
+	closest := segStart
+	if segLengthSquared > 0 {
+		closest = segStart.Add(segment.Scale(math.Max(math.Min(diff.Dot(segment)/segLengthSquared, 1), 0)))
+	}
+
+	if origin.DistanceSquared(closest) <= radius*radius {
+		return 0, true
+	}
+
+	// A capsule is a cylinder with a sphere at each end, so the Ray first enters it through either the cylinder's side (if it's within the
+	// segment's length) or one of the spheres.
+	segDir := segment.Dot(dir)
+	segDiff := segment.Dot(diff)
+
+	a := segLengthSquared - segDir*segDir
+
+	if a > 1e-9 {
+
+		b := segLengthSquared*dir.Dot(diff) - segDiff*segDir
+		c := segLengthSquared*diff.Dot(diff) - segDiff*segDiff - radius*radius*segLengthSquared
+		h := b*b - a*c
+
+		if h >= 0 {
+			t := (-b - math.Sqrt(h)) / a
+			along := segDiff + t*segDir
+			if t >= 0 && along > 0 && along < segLengthSquared {
+				return t, true
+			}
+		}
+
+	}
+
+	startDist, startHit := ray.IntersectSphere(start, radius)
+	endDist, endHit := ray.IntersectSphere(end, radius)
+
+	if startHit && (!endHit || startDist < endDist) {
+		return startDist, true
+	}
+
+	return endDist, endHit
+
+}
+
+// IntersectTriangle returns the distance along the Ray at which it intersects the triangle made up of the three points provided, and whether it does
+// at all. Triangles are intersected from both sides.
+func (ray Ray) IntersectTriangle(a, b, c vector.Vector) (float64, bool) {
+	return intersectRayTriangle(NewVector3FromVector(ray.Origin), NewVector3FromVector(ray.Direction), NewVector3FromVector(a), NewVector3FromVector(b), NewVector3FromVector(c))
+}
+
+// intersectRayTriangle returns the distance along the ray with the origin and direction provided at which it intersects the triangle made up
+// of the three points provided, and whether it does at all. The distance is in multiples of the direction's length.
+func intersectRayTriangle(origin, dir, a, b, c Vector3) (float64, bool) {
+
+	// Möller–Trumbore intersection
+	edge1 := b.Sub(a)
+	edge2 := c.Sub(a)
+
+	p := dir.Cross(edge2)
+	det := edge1.Dot(p)
+
+	if math.Abs(det) < 1e-9 {
+		return 0, false
+	}
+
+	invDet := 1 / det
+
+	s := origin.Sub(a)
+	u := s.Dot(p) * invDet
+
+	if u < 0 || u > 1 {
+		return 0, false
+	}
+
+	q := s.Cross(edge1)
+	v := dir.Dot(q) * invDet
+
+	if v < 0 || u+v > 1 {
+		return 0, false
+	}
+
+	t := edge2.Dot(q) * invDet
+
+	return t, t >= 0
+
+}
+
+// Frustum represents a view frustum, made up of six inward-facing Planes (in the order of left, right, bottom, top, near, and far).
+type Frustum struct {
+	Planes [6]Plane
+}
+
+// NewFrustumFromMatrix returns a new Frustum extracted from a Camera's view-projection Matrix4 (i.e. camera.ViewMatrix().Mult(camera.Projection())).
+// See Matrix4.FrustumPlanes() for more information.
+func NewFrustumFromMatrix(viewProjection Matrix4) Frustum {
+
+	frustum := Frustum{}
+
+	for i, p := range viewProjection.FrustumPlanes() {
+		frustum.Planes[i] = Plane{
+			Normal:   vector.Vector{p[0], p[1], p[2]},
+			Distance: p[3],
+		}
+	}
+
+	return frustum
+
+}
+
+// ContainsPoint returns if the point provided is within the Frustum.
+func (frustum Frustum) ContainsPoint(point vector.Vector) bool {
+	for _, plane := range frustum.Planes {
+		if plane.SignedDistance(point) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// intersectsPoints returns if the shape made up of the points provided, grown by the margin provided, is at least partially within each of the
+// Frustum's planes. This test is conservative, so shapes near the Frustum's corners may be reported as intersecting even if they're slightly outside of it.
+func (frustum Frustum) intersectsPoints(margin float64, points ...vector.Vector) bool {
+	for _, plane := range frustum.Planes {
+		if project(plane.Normal, points...).Max+plane.Distance < -margin {
+			return false
+		}
+	}
+	return true
+}
+
+// IntersectsSphere returns if the sphere with the center and radius provided is at least partially within the Frustum.
+func (frustum Frustum) IntersectsSphere(center vector.Vector, radius float64) bool {
+	return frustum.intersectsPoints(radius, center)
+}
+
+// IntersectsDimensions returns if the axis-aligned box described by the Dimensions provided is at least partially within the Frustum. This test is
+// conservative, so boxes near the Frustum's corners may be reported as intersecting even if they're slightly outside of it.
+func (frustum Frustum) IntersectsDimensions(dim Dimensions) bool {
+
+	corners := make([]vector.Vector, 8)
+
+	for i := range corners {
+		corners[i] = vector.Vector{dim[i&1][0], dim[(i>>1)&1][1], dim[(i>>2)&1][2]}
+	}
+
+	return frustum.intersectsPoints(0, corners...)
+
+}
+
+// IntersectsBounds returns if the BoundingObject provided is at least partially within the Frustum. BoundingTriangles are tested using their BoundingAABB.
+func (frustum Frustum) IntersectsBounds(bounds BoundingObject) bool {
+
+	switch b := bounds.(type) {
+
+	case *BoundingSphere:
+		b.Transform()
+		return frustum.IntersectsSphere(b.WorldPosition(), b.WorldRadius())
+
+	case *BoundingAABB:
+		b.Transform()
+		pos := b.WorldPosition()
+		half := b.Size.Scale(0.5)
+		return frustum.IntersectsDimensions(Dimensions{pos.Sub(half), pos.Add(half)})
+
+	case *BoundingCapsule:
+		b.Transform()
+		return frustum.intersectsPoints(b.WorldRadius(), b.lineBottom(), b.lineTop())
+
+	case *BoundingTriangles:
+		b.Transform()
+		return frustum.IntersectsBounds(b.BoundingAABB)
+
+	}
+
+	return false
+
+}
+
+// IntersectsAABB returns if the BoundingAABB provided is at least partially within the Frustum.
+func (frustum Frustum) IntersectsAABB(box *BoundingAABB) bool {
+	return frustum.IntersectsBounds(box)
+}
@@ -0,0 +1,192 @@
+package tetra3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kvartborg/vector"
+)
+
+const testEpsilon = 1e-6
+
+func vectorsEqual(a, b vector.Vector) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > testEpsilon {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPlaneSignedDistance(t *testing.T) {
+
+	plane := NewPlaneFromPoints(vector.Vector{0, 0, 0}, vector.Vector{1, 0, 0}, vector.Vector{0, 0, -1})
+
+	tests := []struct {
+		point    vector.Vector
+		distance float64
+		closest  vector.Vector
+	}{
+		{vector.Vector{0, 2, 0}, 2, vector.Vector{0, 0, 0}},
+		{vector.Vector{3, -1, 4}, -1, vector.Vector{3, 0, 4}},
+		{vector.Vector{5, 0, 5}, 0, vector.Vector{5, 0, 5}},
+	}
+
+	for _, test := range tests {
+		if d := plane.SignedDistance(test.point); math.Abs(d-test.distance) > testEpsilon {
+			t.Errorf("SignedDistance(%v) = %f, want %f", test.point, d, test.distance)
+		}
+		if c := plane.ClosestPoint(test.point); !vectorsEqual(c, test.closest) {
+			t.Errorf("ClosestPoint(%v) = %v, want %v", test.point, c, test.closest)
+		}
+	}
+
+}
+
+func TestRayIntersections(t *testing.T) {
+
+	ray := NewRay(vector.Vector{0, 0, 10}, vector.Vector{0, 0, -2})
+
+	tests := []struct {
+		name      string
+		intersect func() (float64, bool)
+		distance  float64
+		hit       bool
+	}{
+		{"plane", func() (float64, bool) {
+			return ray.IntersectPlane(NewPlaneFromNormal(vector.Vector{0, 0, 1}, vector.Vector{0, 0, 2}))
+		}, 8, true},
+		{"plane behind", func() (float64, bool) {
+			return ray.IntersectPlane(NewPlaneFromNormal(vector.Vector{0, 0, 1}, vector.Vector{0, 0, 12}))
+		}, -2, false},
+		{"plane parallel", func() (float64, bool) {
+			return ray.IntersectPlane(NewPlaneFromNormal(vector.Vector{1, 0, 0}, vector.Vector{2, 0, 0}))
+		}, 0, false},
+		{"dimensions", func() (float64, bool) { return ray.IntersectDimensions(Dimensions{{-1, -1, -1}, {1, 1, 1}}) }, 9, true},
+		{"dimensions miss", func() (float64, bool) { return ray.IntersectDimensions(Dimensions{{2, 2, -1}, {3, 3, 1}}) }, 0, false},
+		{"dimensions inside", func() (float64, bool) { return ray.IntersectDimensions(Dimensions{{-1, -1, 5}, {1, 1, 15}}) }, 0, true},
+		{"sphere", func() (float64, bool) { return ray.IntersectSphere(vector.Vector{0, 0, 0}, 2) }, 8, true},
+		{"sphere miss", func() (float64, bool) { return ray.IntersectSphere(vector.Vector{5, 0, 0}, 2) }, 0, false},
+		{"sphere behind", func() (float64, bool) { return ray.IntersectSphere(vector.Vector{0, 0, 20}, 2) }, 0, false},
+		{"sphere inside", func() (float64, bool) { return ray.IntersectSphere(vector.Vector{0, 0, 9}, 2) }, 0, true},
+		{"capsule side", func() (float64, bool) {
+			return ray.IntersectCapsule(vector.Vector{0, -5, 0}, vector.Vector{0, 5, 0}, 1)
+		}, 9, true},
+		{"capsule end", func() (float64, bool) {
+			return ray.IntersectCapsule(vector.Vector{0, 0, -5}, vector.Vector{0, 0, 0}, 1)
+		}, 9, true},
+		{"capsule miss", func() (float64, bool) {
+			return ray.IntersectCapsule(vector.Vector{3, -5, 0}, vector.Vector{3, 5, 0}, 1)
+		}, 0, false},
+		{"triangle", func() (float64, bool) {
+			return ray.IntersectTriangle(vector.Vector{-1, -1, 0}, vector.Vector{1, -1, 0}, vector.Vector{0, 1, 0})
+		}, 10, true},
+		{"triangle back face", func() (float64, bool) {
+			return ray.IntersectTriangle(vector.Vector{-1, -1, 0}, vector.Vector{0, 1, 0}, vector.Vector{1, -1, 0})
+		}, 10, true},
+		{"triangle miss", func() (float64, bool) {
+			return ray.IntersectTriangle(vector.Vector{2, 2, 0}, vector.Vector{3, 2, 0}, vector.Vector{2, 3, 0})
+		}, 0, false},
+	}
+
+	for _, test := range tests {
+		distance, hit := test.intersect()
+		if hit != test.hit {
+			t.Errorf("%s: hit = %t, want %t", test.name, hit, test.hit)
+		} else if hit && math.Abs(distance-test.distance) > testEpsilon {
+			t.Errorf("%s: distance = %f, want %f", test.name, distance, test.distance)
+		}
+	}
+
+}
+
+func TestFrustum(t *testing.T) {
+
+	// A perspective view-projection matrix for a camera at the origin looking down -Z, with a 90 degree vertical field of view.
+	perspective := NewProjectionPerspective(90, 1, 100, 100, 100)
+	perspectiveFrustum := NewFrustumFromMatrix(NewMatrix4().Mult(perspective))
+
+	// An orthographic one for a camera at [0, 0, 10] looking down -Z, spanning 10 units horizontally and vertically.
+	orthographic := NewProjectionOrthographic(1, 100, 10, -10, 10, -10)
+	orthographicFrustum := NewFrustumFromMatrix(NewMatrix4Translate(0, 0, -10).Mult(orthographic))
+
+	tests := []struct {
+		name    string
+		frustum Frustum
+		point   vector.Vector
+		radius  float64
+		inside  bool
+	}{
+		{"perspective center", perspectiveFrustum, vector.Vector{0, 0, -50}, 0, true},
+		{"perspective edge", perspectiveFrustum, vector.Vector{9.9, 9.9, -10}, 0, true},
+		{"perspective outside edge", perspectiveFrustum, vector.Vector{10.5, 0, -10}, 0, false},
+		{"perspective sphere overlapping edge", perspectiveFrustum, vector.Vector{10.5, 0, -10}, 1, true},
+		{"perspective before near", perspectiveFrustum, vector.Vector{0, 0, -0.5}, 0, false},
+		{"perspective beyond far", perspectiveFrustum, vector.Vector{0, 0, -101}, 0, false},
+		{"perspective behind", perspectiveFrustum, vector.Vector{0, 0, 10}, 0, false},
+		{"orthographic center", orthographicFrustum, vector.Vector{0, 0, -50}, 0, true},
+		{"orthographic edge", orthographicFrustum, vector.Vector{4.9, -4.9, 0}, 0, true},
+		{"orthographic outside edge", orthographicFrustum, vector.Vector{5.5, 0, 0}, 0, false},
+		{"orthographic behind", orthographicFrustum, vector.Vector{0, 0, 11}, 0, false},
+		{"orthographic beyond far", orthographicFrustum, vector.Vector{0, 0, -100}, 0, false},
+	}
+
+	for _, test := range tests {
+		if test.radius == 0 {
+			if inside := test.frustum.ContainsPoint(test.point); inside != test.inside {
+				t.Errorf("%s: ContainsPoint(%v) = %t, want %t", test.name, test.point, inside, test.inside)
+			}
+		}
+		if inside := test.frustum.IntersectsSphere(test.point, test.radius); inside != test.inside {
+			t.Errorf("%s: IntersectsSphere(%v, %f) = %t, want %t", test.name, test.point, test.radius, inside, test.inside)
+		}
+	}
+
+}
+
+func TestFrustumBounds(t *testing.T) {
+
+	frustum := NewFrustumFromMatrix(NewMatrix4().Mult(NewProjectionPerspective(90, 1, 100, 100, 100)))
+
+	newSphere := func(x, y, z, radius float64) BoundingObject {
+		sphere := NewBoundingSphere("sphere", radius)
+		sphere.SetLocalPosition(vector.Vector{x, y, z})
+		return sphere
+	}
+
+	newAABB := func(x, y, z, size float64) BoundingObject {
+		box := NewBoundingAABB("aabb", size, size, size)
+		box.SetLocalPosition(vector.Vector{x, y, z})
+		return box
+	}
+
+	newCapsule := func(x, y, z, height, radius float64) BoundingObject {
+		capsule := NewBoundingCapsule("capsule", height, radius)
+		capsule.SetLocalPosition(vector.Vector{x, y, z})
+		return capsule
+	}
+
+	tests := []struct {
+		name   string
+		bounds BoundingObject
+		inside bool
+	}{
+		{"sphere inside", newSphere(0, 0, -10, 1), true},
+		{"sphere outside", newSphere(20, 0, -10, 1), false},
+		{"aabb inside", newAABB(0, 0, -10, 2), true},
+		{"aabb overlapping", newAABB(11, 0, -10, 4), true},
+		{"aabb outside", newAABB(0, 0, 10, 2), false},
+		{"capsule crossing", newCapsule(0, 0, -10, 100, 1), true},
+		{"capsule outside", newCapsule(30, 0, -10, 10, 1), false},
+	}
+
+	for _, test := range tests {
+		if inside := frustum.IntersectsBounds(test.bounds); inside != test.inside {
+			t.Errorf("%s: IntersectsBounds() = %t, want %t", test.name, inside, test.inside)
+		}
+	}
+
+}
@@ -0,0 +1,325 @@
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/kvartborg/vector"
+)
+
+const (
+	GizmoModeTranslate = iota // The Gizmo moves its Target along an axis
+	GizmoModeRotate           // The Gizmo rotates its Target around an axis
+	GizmoModeScale            // The Gizmo scales its Target along an axis
+)
+
+const gizmoRingSegments = 32
+
+// Gizmo is a runtime transform gizmo, allowing the user to translate, rotate, or scale a target Node by dragging handles with the mouse.
+// This is useful for building simple in-game editors or level tweaking tools. To use it, call Gizmo.Update() from your game's Update() function,
+// and Gizmo.Draw() from your game's Draw() function (after drawing the Camera's color texture to the screen, which should be the same size as the
+// screen for the handles to line up with the mouse cursor).
+type Gizmo struct {
+	Camera     *Camera // The Camera used to project the Gizmo's handles to the screen
+	Target     INode   // The Node the Gizmo manipulates; if nil, the Gizmo is neither drawn nor interactive
+	Mode       int     // The Gizmo's mode; should be one of the GizmoMode constants. Defaults to GizmoModeTranslate.
+	LocalSpace bool    // If the Gizmo's axes should align to the Target's rotation (true) or to the world axes (false). Defaults to false.
+	ScreenSize float64 // The length of the Gizmo's handles on screen in pixels. Defaults to 80.
+	PickRadius float64 // How close (in pixels) the mouse cursor has to be to a handle to grab it. Defaults to 6.
+
+	XColor     *Color // The color of the X axis handle. Defaults to red.
+	YColor     *Color // The color of the Y axis handle. Defaults to green.
+	ZColor     *Color // The color of the Z axis handle. Defaults to blue.
+	HoverColor *Color // The color of a hovered or dragged handle. Defaults to yellow.
+
+	hoverAxis int
+	dragAxis  int
+
+	dragStartMouse    Vector3
+	dragStartPosition vector.Vector
+	dragStartRotation Matrix4
+	dragStartScale    vector.Vector
+	dragScreenDir     Vector3
+	dragAxisVector    Vector3
+	dragHandleLength  float64
+}
+
+// NewGizmo returns a new Gizmo, using the Camera provided to project its handles.
+func NewGizmo(camera *Camera) *Gizmo {
+	return &Gizmo{
+		Camera:     camera,
+		Mode:       GizmoModeTranslate,
+		ScreenSize: 80,
+		PickRadius: 6,
+		XColor:     NewColor(1, 0.2, 0.2, 1),
+		YColor:     NewColor(0.2, 1, 0.2, 1),
+		ZColor:     NewColor(0.3, 0.4, 1, 1),
+		HoverColor: NewColor(1, 1, 0, 1),
+		hoverAxis:  -1,
+		dragAxis:   -1,
+	}
+}
+
+// Dragging returns if the user is currently dragging one of the Gizmo's handles.
+func (gizmo *Gizmo) Dragging() bool {
+	return gizmo.dragAxis >= 0
+}
+
+// Update handles mouse interaction with the Gizmo. It returns true if the mouse is hovering over or dragging one of the Gizmo's handles,
+// in which case you probably want to ignore the mouse click for other purposes (like selecting a different Node).
+func (gizmo *Gizmo) Update() bool {
+
+	gizmo.hoverAxis = -1
+
+	if gizmo.Target == nil || gizmo.Camera == nil {
+		gizmo.dragAxis = -1
+		return false
+	}
+
+	mx, my := ebiten.CursorPosition()
+	mouse := NewVector3(float64(mx), float64(my), 0)
+
+	if gizmo.dragAxis >= 0 {
+
+		if !ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
+			gizmo.dragAxis = -1
+			return false
+		}
+
+		gizmo.drag(mouse)
+		return true
+
+	}
+
+	center, axes, length, ok := gizmo.handles()
+	if !ok {
+		return false
+	}
+
+	gizmo.hoverAxis = gizmo.pick(mouse, center, axes, length)
+
+	if gizmo.hoverAxis >= 0 && inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+
+		gizmo.dragAxis = gizmo.hoverAxis
+		gizmo.dragStartMouse = mouse
+		gizmo.dragStartPosition = gizmo.Target.WorldPosition()
+		gizmo.dragStartRotation = gizmo.Target.WorldRotation()
+		gizmo.dragStartScale = gizmo.Target.LocalScale()
+		gizmo.dragAxisVector = axes[gizmo.dragAxis]
+		gizmo.dragHandleLength = length
+
+		cs := gizmo.toScreen(center)
+		gizmo.dragScreenDir = gizmo.toScreen(center.Add(axes[gizmo.dragAxis].Scale(length))).Sub(cs)
+
+	}
+
+	return gizmo.hoverAxis >= 0
+
+}
+
+func (gizmo *Gizmo) drag(mouse Vector3) {
+
+	target := gizmo.Target
+	delta := mouse.Sub(gizmo.dragStartMouse)
+
+	switch gizmo.Mode {
+
+	case GizmoModeTranslate:
+
+		lengthSquared := gizmo.dragScreenDir.MagnitudeSquared()
+		if lengthSquared == 0 {
+			return
+		}
+
+		dist := delta.Dot(gizmo.dragScreenDir) / lengthSquared * gizmo.dragHandleLength
+		target.SetWorldPosition(NewVector3FromVector(gizmo.dragStartPosition).Add(gizmo.dragAxisVector.Scale(dist)).ToVector())
+
+	case GizmoModeScale:
+
+		lengthSquared := gizmo.dragScreenDir.MagnitudeSquared()
+		if lengthSquared == 0 {
+			return
+		}
+
+		scale := gizmo.dragStartScale.Clone()
+		scale[gizmo.dragAxis] = math.Max(scale[gizmo.dragAxis]*(1+delta.Dot(gizmo.dragScreenDir)/lengthSquared), 0.001)
+		target.SetLocalScale(scale)
+
+	case GizmoModeRotate:
+
+		cs := gizmo.toScreen(NewVector3FromVector(gizmo.dragStartPosition))
+		start := gizmo.dragStartMouse.Sub(cs)
+		current := mouse.Sub(cs)
+
+		// Screen angles increase clockwise (as Y points down), while positive rotations are counter-clockwise when looking down the axis.
+		angle := math.Atan2(current.Y, current.X) - math.Atan2(start.Y, start.X)
+
+		toCamera := NewVector3FromVector(gizmo.Camera.WorldPosition()).Sub(NewVector3FromVector(gizmo.dragStartPosition))
+		if gizmo.dragAxisVector.Dot(toCamera) > 0 {
+			angle = -angle
+		}
+
+		axis := gizmo.dragAxisVector
+		target.SetWorldRotation(gizmo.dragStartRotation.Mult(NewMatrix4Rotate(axis.X, axis.Y, axis.Z, angle)))
+
+	}
+
+}
+
+// handles returns the Gizmo's world-space center, axes, and handle length, and whether the Gizmo is in front of the Camera.
+func (gizmo *Gizmo) handles() (Vector3, [3]Vector3, float64, bool) {
+
+	center := NewVector3FromVector(gizmo.Target.WorldPosition())
+
+	axes := [3]Vector3{NewVector3(1, 0, 0), NewVector3(0, 1, 0), NewVector3(0, 0, 1)}
+
+	if gizmo.LocalSpace {
+		rot := gizmo.Target.WorldRotation()
+		axes[0] = NewVector3FromVector(rot.Right())
+		axes[1] = NewVector3FromVector(rot.Up())
+		axes[2] = NewVector3FromVector(rot.Forward())
+	}
+
+	clip := gizmo.Camera.WorldToClip(center.ToVector())
+	if gizmo.Camera.Perspective && clip[3] <= 0 {
+		return center, axes, 0, false
+	}
+
+	// Keep the handles a constant size on screen.
+	camRight := NewVector3FromVector(gizmo.Camera.WorldRotation().Right())
+	pixelsPerUnit := gizmo.toScreen(center.Add(camRight)).Sub(gizmo.toScreen(center)).Magnitude()
+
+	if pixelsPerUnit == 0 {
+		return center, axes, 0, false
+	}
+
+	return center, axes, gizmo.ScreenSize / pixelsPerUnit, true
+
+}
+
+func (gizmo *Gizmo) toScreen(point Vector3) Vector3 {
+	s := gizmo.Camera.WorldToScreen(point.ToVector())
+	return NewVector3(s[0], s[1], 0)
+}
+
+// ringPoints returns the screen-space points of the rotation ring around the given axis.
+func (gizmo *Gizmo) ringPoints(center Vector3, axes [3]Vector3, axis int, length float64) []Vector3 {
+
+	u := axes[(axis+1)%3]
+	v := axes[(axis+2)%3]
+
+	points := make([]Vector3, 0, gizmoRingSegments+1)
+
+	for i := 0; i <= gizmoRingSegments; i++ {
+		angle := math.Pi * 2 * float64(i) / gizmoRingSegments
+		point := center.Add(u.Scale(math.Cos(angle) * length)).Add(v.Scale(math.Sin(angle) * length))
+		points = append(points, gizmo.toScreen(point))
+	}
+
+	return points
+
+}
+
+func (gizmo *Gizmo) pick(mouse, center Vector3, axes [3]Vector3, length float64) int {
+
+	closest := -1
+	closestDist := gizmo.PickRadius
+
+	cs := gizmo.toScreen(center)
+
+	for axis := 0; axis < 3; axis++ {
+
+		var dist float64
+
+		if gizmo.Mode == GizmoModeRotate {
+
+			dist = math.MaxFloat64
+			points := gizmo.ringPoints(center, axes, axis, length)
+			for i := 0; i < len(points)-1; i++ {
+				dist = math.Min(dist, distanceToSegment2D(mouse, points[i], points[i+1]))
+			}
+
+		} else {
+			dist = distanceToSegment2D(mouse, cs, gizmo.toScreen(center.Add(axes[axis].Scale(length))))
+		}
+
+		if dist <= closestDist {
+			closest = axis
+			closestDist = dist
+		}
+
+	}
+
+	return closest
+
+}
+
+func distanceToSegment2D(point, start, end Vector3) float64 {
+
+	segment := end.Sub(start)
+	lengthSquared := segment.MagnitudeSquared()
+
+	if lengthSquared == 0 {
+		return point.Distance(start)
+	}
+
+	t := math.Max(0, math.Min(1, point.Sub(start).Dot(segment)/lengthSquared))
+
+	return point.Distance(start.Add(segment.Scale(t)))
+
+}
+
+// Draw draws the Gizmo's handles to the screen provided.
+func (gizmo *Gizmo) Draw(screen *ebiten.Image) {
+
+	if gizmo.Target == nil || gizmo.Camera == nil {
+		return
+	}
+
+	center, axes, length, ok := gizmo.handles()
+	if !ok {
+		return
+	}
+
+	cs := gizmo.toScreen(center)
+	colors := [3]*Color{gizmo.XColor, gizmo.YColor, gizmo.ZColor}
+
+	for axis := 0; axis < 3; axis++ {
+
+		c := colors[axis].ToRGBA64()
+		if axis == gizmo.hoverAxis || axis == gizmo.dragAxis {
+			c = gizmo.HoverColor.ToRGBA64()
+		}
+
+		if gizmo.Mode == GizmoModeRotate {
+
+			points := gizmo.ringPoints(center, axes, axis, length)
+			for i := 0; i < len(points)-1; i++ {
+				ebitenutil.DrawLine(screen, points[i].X, points[i].Y, points[i+1].X, points[i+1].Y, c)
+			}
+
+		} else {
+
+			end := gizmo.toScreen(center.Add(axes[axis].Scale(length)))
+			ebitenutil.DrawLine(screen, cs.X, cs.Y, end.X, end.Y, c)
+
+			if gizmo.Mode == GizmoModeScale {
+				ebitenutil.DrawRect(screen, end.X-3, end.Y-3, 6, 6, c)
+			} else {
+				// Draw a simple arrowhead.
+				dir := end.Sub(cs).Unit()
+				side := NewVector3(-dir.Y, dir.X, 0)
+				back := end.Sub(dir.Scale(8))
+				left := back.Add(side.Scale(4))
+				right := back.Sub(side.Scale(4))
+				ebitenutil.DrawLine(screen, end.X, end.Y, left.X, left.Y, c)
+				ebitenutil.DrawLine(screen, end.X, end.Y, right.X, right.Y, c)
+			}
+
+		}
+
+	}
+
+}
@@ -248,10 +248,34 @@ func LoadGLTFData(data []byte, gltfLoadOptions *GLTFLoadOptions) (*Library, erro
 		library.Meshes[mesh.Name] = newMesh
 		newMesh.library = library
 
+		// Morph targets are named through the mesh's "targetNames" extra (as Blender exports them), and their default weights are the mesh's weights.
+		morphTargetCount := 0
+		for _, v := range mesh.Primitives {
+			if len(v.Targets) > morphTargetCount {
+				morphTargetCount = len(v.Targets)
+			}
+		}
+
+		for i := 0; i < morphTargetCount; i++ {
+			target := &MorphTarget{Name: "Morph" + strconv.Itoa(i)}
+			if i < len(mesh.Weights) {
+				target.DefaultWeight = float64(mesh.Weights[i])
+			}
+			newMesh.MorphTargets = append(newMesh.MorphTargets, target)
+		}
+
 		if mesh.Extras != nil {
 
 			if dataMap, isMap := mesh.Extras.(map[string]interface{}); isMap {
 
+				if targetNames, exists := dataMap["targetNames"]; exists {
+					for index, name := range targetNames.([]interface{}) {
+						if index < len(newMesh.MorphTargets) {
+							newMesh.MorphTargets[index].Name = name.(string)
+						}
+					}
+				}
+
 				if vcNames, exists := dataMap["t3dVertexColorNames__"]; exists {
 					for index, name := range vcNames.([]interface{}) {
 						newMesh.VertexColorChannelNames[name.(string)] = index
@@ -413,12 +437,59 @@ func LoadGLTFData(data []byte, gltfLoadOptions *GLTFLoadOptions) (*Library, erro
 
 			mp := newMesh.AddMeshPart(mat)
 
-			mp.AddTriangles(newVerts...)
+			firstVertex := newMesh.triIndex * 3
+
+			mp.addTriangles(newVerts...)
+
+			for targetIndex, target := range v.Targets {
+
+				morphTarget := newMesh.MorphTargets[targetIndex]
+
+				for len(morphTarget.PositionOffsets) < newMesh.VertexCount {
+					morphTarget.PositionOffsets = append(morphTarget.PositionOffsets, nil)
+					morphTarget.NormalOffsets = append(morphTarget.NormalOffsets, nil)
+				}
+
+				if positionAccessor, exists := target[gltf.POSITION]; exists {
+
+					offsets, err := modeler.ReadPosition(doc, doc.Accessors[positionAccessor], [][3]float32{})
+
+					if err != nil {
+						return nil, err
+					}
+
+					for i, index := range indices {
+						if o := offsets[index]; o[0] != 0 || o[1] != 0 || o[2] != 0 {
+							morphTarget.PositionOffsets[firstVertex+i] = vector.Vector{float64(o[0]), float64(o[1]), float64(o[2])}
+						}
+					}
+
+				}
+
+				if normalAccessor, exists := target[gltf.NORMAL]; exists {
+
+					offsets, err := modeler.ReadNormal(doc, doc.Accessors[normalAccessor], [][3]float32{})
+
+					if err != nil {
+						return nil, err
+					}
+
+					for i, index := range indices {
+						if o := offsets[index]; o[0] != 0 || o[1] != 0 || o[2] != 0 {
+							morphTarget.NormalOffsets[firstVertex+i] = vector.Vector{float64(o[0]), float64(o[1]), float64(o[2])}
+						}
+					}
+
+				}
 
-			newMesh.UpdateBounds()
+			}
 
 		}
 
+		newMesh.SplitMeshParts()
+
+		newMesh.UpdateBounds()
+
 	}
 
 	for _, gltfAnim := range doc.Animations {
@@ -530,6 +601,52 @@ func LoadGLTFData(data []byte, gltfLoadOptions *GLTFLoadOptions) (*Library, erro
 					}
 				}
 
+			} else if channel.Target.Path == gltf.TRSWeights {
+
+				id, err := modeler.ReadAccessor(doc, doc.Accessors[*sampler.Input], nil)
+
+				if err != nil {
+					return nil, err
+				}
+
+				inputData := id.([]float32)
+
+				od, err := modeler.ReadAccessor(doc, doc.Accessors[*sampler.Output], nil)
+
+				if err != nil {
+					return nil, err
+				}
+
+				outputData := od.([]float32)
+
+				if len(inputData) == 0 {
+					continue
+				}
+
+				// The weights of all of the morph targets are stored one after another for each keyframe; cubic spline keyframes also
+				// store in- and out-tangents before and after the values, which we skip.
+				weightCount := len(outputData) / len(inputData)
+				valueStart := 0
+				if sampler.Interpolation == gltf.InterpolationCubicSpline {
+					weightCount /= 3
+					valueStart = weightCount
+				}
+
+				track := animChannel.AddTrack(TrackTypeMorphWeights)
+				track.Interpolation = int(sampler.Interpolation)
+				for i := 0; i < len(inputData); i++ {
+					t := inputData[i]
+					start := i*len(outputData)/len(inputData) + valueStart
+					weights := make([]float64, weightCount)
+					for w := range weights {
+						weights[w] = float64(outputData[start+w])
+					}
+					track.AddKeyframe(float64(t), weights)
+					if float64(t) > animLength {
+						animLength = float64(t)
+					}
+				}
+
 			}
 
 		}
@@ -584,7 +701,13 @@ func LoadGLTFData(data []byte, gltfLoadOptions *GLTFLoadOptions) (*Library, erro
 
 		if node.Mesh != nil {
 			mesh := library.Meshes[doc.Meshes[*node.Mesh].Name]
-			obj = NewModel(mesh, node.Name)
+			model := NewModel(mesh, node.Name)
+			for i, weight := range node.Weights {
+				if i < len(model.MorphWeights) {
+					model.MorphWeights[i] = float64(weight)
+				}
+			}
+			obj = model
 		} else if node.Camera != nil {
 
 			gltfCam := doc.Cameras[*node.Camera]
@@ -1102,27 +1225,27 @@ func LoadGLTFData(data []byte, gltfLoadOptions *GLTFLoadOptions) (*Library, erro
 				worldColor.ConvertTosRGB()
 				ambientLight := NewAmbientLight("World Ambient", 1, 1, 1, float32(dataMap["t3dWorldEnergy__"].(float64)))
 				ambientLight.Color = worldColor
-				scene.Root.AddChildren(ambientLight)
+				scene.World.AmbientLight = ambientLight
 			}
 
 			if cc, exists := dataMap["t3dClearColor__"]; exists {
 				wcc := cc.([]interface{})
 				clearColor := NewColor(float32(wcc[0].(float64)), float32(wcc[1].(float64)), float32(wcc[2].(float64)), float32(wcc[3].(float64)))
 				clearColor.ConvertTosRGB()
-				scene.ClearColor = clearColor
+				scene.World.ClearColor = clearColor
 			}
 
 			if v, exists := dataMap["t3dFogMode__"]; exists {
 				fm := v.(string)
 				switch fm {
 				case "OFF":
-					scene.FogMode = FogOff
+					scene.World.FogMode = FogOff
 				case "ADDITIVE":
-					scene.FogMode = FogAdd
+					scene.World.FogMode = FogAdd
 				case "MULTIPLY":
-					scene.FogMode = FogMultiply
+					scene.World.FogMode = FogMultiply
 				case "OVERWRITE":
-					scene.FogMode = FogOverwrite
+					scene.World.FogMode = FogOverwrite
 				}
 			}
 
@@ -1130,17 +1253,17 @@ func LoadGLTFData(data []byte, gltfLoadOptions *GLTFLoadOptions) (*Library, erro
 				wcc := v.([]interface{})
 				fogColor := NewColor(float32(wcc[0].(float64)), float32(wcc[1].(float64)), float32(wcc[2].(float64)), float32(wcc[3].(float64)))
 				fogColor.ConvertTosRGB()
-				scene.FogColor = fogColor
+				scene.World.FogColor = fogColor
 			}
 
 			if v, exists := dataMap["t3dFogRangeStart__"]; exists {
 				fogStart := v.(float64)
-				scene.FogRange[0] = float32(fogStart)
+				scene.World.FogRange[0] = float32(fogStart)
 			}
 
 			if v, exists := dataMap["t3dFogRangeEnd__"]; exists {
 				fogEnd := v.(float64)
-				scene.FogRange[1] = float32(fogEnd)
+				scene.World.FogRange[1] = float32(fogEnd)
 			}
 
 		}
@@ -0,0 +1,91 @@
+package tetra3d
+
+import "sort"
+
+// GradientStop is a single color stop in a Gradient.
+type GradientStop struct {
+	Position float64 // The position of the stop in the Gradient, ranging from 0 to 1
+	Color    *Color  // The color of the stop
+}
+
+// Gradient represents a color ramp, made up of a set of color stops at positions ranging from 0 to 1. Gradients can be used for
+// coloring particles over their lifetime, tinting the sky by time of day, vertex color ramps, debug heatmaps, and so on.
+type Gradient struct {
+	Stops   []GradientStop // The color stops of the Gradient, sorted by position. If you alter these directly, call Sort() afterwards.
+	Stepped bool           // If the Gradient should snap to the previous color stop rather than smoothly blending between stops
+}
+
+// NewGradient returns a new Gradient, with the colors provided placed at evenly spaced stops. For example, NewGradient(red, blue)
+// returns a Gradient going from red at 0 to blue at 1.
+func NewGradient(colors ...*Color) *Gradient {
+
+	gradient := &Gradient{
+		Stops: []GradientStop{},
+	}
+
+	for i, color := range colors {
+		pos := 0.0
+		if len(colors) > 1 {
+			pos = float64(i) / float64(len(colors)-1)
+		}
+		gradient.Stops = append(gradient.Stops, GradientStop{Position: pos, Color: color.Clone()})
+	}
+
+	return gradient
+
+}
+
+// Clone returns a deep copy of the Gradient.
+func (gradient *Gradient) Clone() *Gradient {
+	newGradient := &Gradient{
+		Stops:   make([]GradientStop, 0, len(gradient.Stops)),
+		Stepped: gradient.Stepped,
+	}
+	for _, stop := range gradient.Stops {
+		newGradient.Stops = append(newGradient.Stops, GradientStop{Position: stop.Position, Color: stop.Color.Clone()})
+	}
+	return newGradient
+}
+
+// Add adds a color stop to the Gradient at the position provided (ranging from 0 to 1), keeping the stops sorted.
+func (gradient *Gradient) Add(position float64, color *Color) {
+	gradient.Stops = append(gradient.Stops, GradientStop{Position: position, Color: color.Clone()})
+	gradient.Sort()
+}
+
+// Sort sorts the Gradient's color stops by position; this should be called after altering the Stops slice directly.
+func (gradient *Gradient) Sort() {
+	sort.SliceStable(gradient.Stops, func(i, j int) bool { return gradient.Stops[i].Position < gradient.Stops[j].Position })
+}
+
+// Evaluate returns a new Color for the given position along the Gradient (ranging from 0 to 1). Positions before the first stop
+// return the first stop's color, and positions after the last stop return the last stop's color. If the Gradient has no stops,
+// Evaluate returns opaque white.
+func (gradient *Gradient) Evaluate(t float64) *Color {
+
+	if len(gradient.Stops) == 0 {
+		return NewColor(1, 1, 1, 1)
+	}
+
+	first := gradient.Stops[0]
+	if t <= first.Position {
+		return first.Color.Clone()
+	}
+
+	last := gradient.Stops[len(gradient.Stops)-1]
+	if t >= last.Position {
+		return last.Color.Clone()
+	}
+
+	index := sort.Search(len(gradient.Stops), func(i int) bool { return gradient.Stops[i].Position > t })
+
+	start := gradient.Stops[index-1]
+	end := gradient.Stops[index]
+
+	if gradient.Stepped || end.Position <= start.Position {
+		return start.Color.Clone()
+	}
+
+	return start.Color.Lerp(end.Color, (t-start.Position)/(end.Position-start.Position))
+
+}
@@ -0,0 +1,421 @@
+package tetra3d
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/kvartborg/vector"
+)
+
+const (
+	GridCollisionNone      = iota // The GridTile doesn't generate any collision
+	GridCollisionAABB             // The GridTile generates a BoundingAABB the size of its Mesh's bounds in each cell it's placed in
+	GridCollisionTriangles        // The GridTile's triangles are merged into a BoundingTriangles object for each chunk of the GridMap
+)
+
+// GridTile is a kind of tile that can be placed in the cells of a GridMap, consisting of a Mesh and settings on how it's placed.
+type GridTile struct {
+	Name      string // The name of the GridTile, used to place it in a GridMap's cells
+	Mesh      *Mesh  // The Mesh placed in cells containing this GridTile
+	Static    bool   // If the GridTile is static, it's merged with other static tiles in its chunk to save draw calls; otherwise, it's placed as an individual Model. Defaults to true.
+	Collision int    // The kind of collision the GridTile generates; should be one of the GridCollision constants. Defaults to GridCollisionAABB.
+}
+
+// GridCell represents the position of a cell in a GridMap.
+type GridCell struct {
+	X, Y, Z int
+}
+
+type gridCellData struct {
+	tile     *GridTile
+	rotation int
+}
+
+type gridChunk struct {
+	nodes []INode
+	dirty bool
+}
+
+// GridMap is a Node that places Mesh tiles on a 3D grid, allowing you to build levels (like dungeons or voxel-like terrain) in code. Tiles are
+// registered with GridMap.AddTile() (or all at once from a Library with GridMap.AddTilesFromLibrary()), and then placed in cells with
+// GridMap.SetCell(). The GridMap is divided into chunks of cells; after editing cells, call GridMap.Rebuild() to regenerate the chunks that
+// changed. When a chunk is rebuilt, its static tiles are merged into a single Model (saving draw calls while still allowing chunks
+// to be frustum culled), and collision is generated for its tiles according to their Collision settings, available through GridMap.Colliders().
+type GridMap struct {
+	*Node
+	CellSize  vector.Vector        // The size of each cell in the GridMap. Changing this requires calling GridMap.RebuildAll(). Defaults to (1, 1, 1).
+	ChunkSize int                  // How many cells wide, tall, and deep each chunk of the GridMap is. Changing this requires calling GridMap.RebuildAll(). Defaults to 8.
+	Tiles     map[string]*GridTile // The GridTiles registered with the GridMap, by name
+
+	cells  map[GridCell]gridCellData
+	chunks map[GridCell]*gridChunk
+	tiles  *Node
+}
+
+// NewGridMap returns a new, empty GridMap with the name provided.
+func NewGridMap(name string) *GridMap {
+
+	gridMap := &GridMap{
+		Node:      NewNode(name),
+		CellSize:  vector.Vector{1, 1, 1},
+		ChunkSize: 8,
+		Tiles:     map[string]*GridTile{},
+		cells:     map[GridCell]gridCellData{},
+		chunks:    map[GridCell]*gridChunk{},
+		tiles:     NewNode("Tiles"),
+	}
+
+	gridMap.addChildren(gridMap, gridMap.tiles)
+
+	return gridMap
+
+}
+
+// Clone returns a clone of the GridMap. The clone shares the original's GridTiles, and has its chunks rebuilt.
+func (gridMap *GridMap) Clone() INode {
+
+	clone := NewGridMap(gridMap.name)
+	clone.CellSize = gridMap.CellSize.Clone()
+	clone.ChunkSize = gridMap.ChunkSize
+
+	for name, tile := range gridMap.Tiles {
+		clone.Tiles[name] = tile
+	}
+
+	for cell, data := range gridMap.cells {
+		clone.cells[cell] = data
+	}
+
+	tilesIndex := -1
+	for i, child := range gridMap.children {
+		if child == gridMap.tiles {
+			tilesIndex = i
+		}
+	}
+
+	clone.Node = gridMap.Node.Clone().(*Node)
+
+	// The generated tiles are rebuilt rather than cloned, so we discard the clone of the original's tile container.
+	if tilesIndex >= 0 {
+		clone.Node.RemoveChildren(clone.children[tilesIndex])
+	}
+
+	for _, child := range clone.children {
+		child.setParent(clone)
+	}
+
+	clone.addChildren(clone, clone.tiles)
+
+	clone.RebuildAll()
+
+	return clone
+
+}
+
+// AddTile registers a GridTile with the name and Mesh provided, returning it so that its settings can be customized.
+func (gridMap *GridMap) AddTile(name string, mesh *Mesh) *GridTile {
+	tile := &GridTile{
+		Name:      name,
+		Mesh:      mesh,
+		Static:    true,
+		Collision: GridCollisionAABB,
+	}
+	gridMap.Tiles[name] = tile
+	return tile
+}
+
+// AddTilesFromLibrary registers a GridTile for each Mesh in the Library provided, named after the Mesh.
+func (gridMap *GridMap) AddTilesFromLibrary(library *Library) {
+	for name, mesh := range library.Meshes {
+		gridMap.AddTile(name, mesh)
+	}
+}
+
+// SetCell places the GridTile with the name provided in the cell at the given position. Passing an empty tile name clears the cell.
+// SetCell will panic if no GridTile with the name provided has been registered.
+func (gridMap *GridMap) SetCell(x, y, z int, tileName string) {
+	gridMap.SetCellRotated(x, y, z, tileName, 0)
+}
+
+// SetCellRotated places the GridTile with the name provided in the cell at the given position, rotated around the Y axis in 90-degree
+// increments (so a rotation of 1 is rotated 90 degrees, 2 is 180 degrees, and so on). Passing an empty tile name clears the cell.
+// SetCellRotated will panic if no GridTile with the name provided has been registered.
+func (gridMap *GridMap) SetCellRotated(x, y, z int, tileName string, rotation int) {
+
+	cell := GridCell{x, y, z}
+
+	if tileName == "" {
+		if _, exists := gridMap.cells[cell]; exists {
+			delete(gridMap.cells, cell)
+			gridMap.dirtyChunk(cell)
+		}
+		return
+	}
+
+	tile, exists := gridMap.Tiles[tileName]
+	if !exists {
+		panic("error: GridMap has no tile named " + tileName)
+	}
+
+	rotation = ((rotation % 4) + 4) % 4
+
+	if existing, exists := gridMap.cells[cell]; exists && existing.tile == tile && existing.rotation == rotation {
+		return
+	}
+
+	gridMap.cells[cell] = gridCellData{tile: tile, rotation: rotation}
+	gridMap.dirtyChunk(cell)
+
+}
+
+// ClearCell clears the cell at the given position.
+func (gridMap *GridMap) ClearCell(x, y, z int) {
+	gridMap.SetCell(x, y, z, "")
+}
+
+// ClearCells clears all cells in the GridMap.
+func (gridMap *GridMap) ClearCells() {
+	for cell := range gridMap.cells {
+		gridMap.dirtyChunk(cell)
+	}
+	gridMap.cells = map[GridCell]gridCellData{}
+}
+
+// Cell returns the GridTile in the cell at the given position, or nil if the cell is empty.
+func (gridMap *GridMap) Cell(x, y, z int) *GridTile {
+	return gridMap.cells[GridCell{x, y, z}].tile
+}
+
+// CellRotation returns the rotation (in 90-degree increments around the Y axis) of the tile in the cell at the given position.
+func (gridMap *GridMap) CellRotation(x, y, z int) int {
+	return gridMap.cells[GridCell{x, y, z}].rotation
+}
+
+// Cells returns the positions of all filled cells in the GridMap, sorted by Y, then Z, then X.
+func (gridMap *GridMap) Cells() []GridCell {
+	cells := make([]GridCell, 0, len(gridMap.cells))
+	for cell := range gridMap.cells {
+		cells = append(cells, cell)
+	}
+	sortGridCells(cells)
+	return cells
+}
+
+func sortGridCells(cells []GridCell) {
+	sort.Slice(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		return a.X < b.X
+	})
+}
+
+// CellToLocal returns the position of the cell at the given position relative to the GridMap (i.e. where a tile's origin is placed).
+func (gridMap *GridMap) CellToLocal(x, y, z int) vector.Vector {
+	return vector.Vector{float64(x) * gridMap.CellSize[0], float64(y) * gridMap.CellSize[1], float64(z) * gridMap.CellSize[2]}
+}
+
+// CellToWorld returns the world position of the cell at the given position, taking into account the GridMap's transform.
+func (gridMap *GridMap) CellToWorld(x, y, z int) vector.Vector {
+	return gridMap.Transform().MultVec(gridMap.CellToLocal(x, y, z))
+}
+
+// WorldToCell returns the position of the cell closest to the world position provided, taking into account the GridMap's transform.
+func (gridMap *GridMap) WorldToCell(position vector.Vector) GridCell {
+	local := gridMap.Transform().Inverted().MultVec(position)
+	return GridCell{
+		int(math.Round(local[0] / gridMap.CellSize[0])),
+		int(math.Round(local[1] / gridMap.CellSize[1])),
+		int(math.Round(local[2] / gridMap.CellSize[2])),
+	}
+}
+
+func (gridMap *GridMap) chunkOf(cell GridCell) GridCell {
+	size := gridMap.ChunkSize
+	if size < 1 {
+		size = 1
+	}
+	floorDiv := func(v int) int {
+		if v < 0 {
+			return (v+1)/size - 1
+		}
+		return v / size
+	}
+	return GridCell{floorDiv(cell.X), floorDiv(cell.Y), floorDiv(cell.Z)}
+}
+
+func (gridMap *GridMap) dirtyChunk(cell GridCell) {
+	chunkPos := gridMap.chunkOf(cell)
+	chunk, exists := gridMap.chunks[chunkPos]
+	if !exists {
+		chunk = &gridChunk{}
+		gridMap.chunks[chunkPos] = chunk
+	}
+	chunk.dirty = true
+}
+
+// Rebuild regenerates the Models and collision of any chunks whose cells have changed since they were last built. Call this after editing
+// cells (for example, once per frame, after making all of the frame's changes).
+func (gridMap *GridMap) Rebuild() {
+
+	for _, chunkPos := range gridMap.sortedChunks() {
+
+		chunk := gridMap.chunks[chunkPos]
+
+		if !chunk.dirty {
+			continue
+		}
+
+		gridMap.rebuildChunk(chunkPos, chunk)
+
+		if len(chunk.nodes) == 0 {
+			delete(gridMap.chunks, chunkPos)
+		}
+
+	}
+
+}
+
+// RebuildAll regenerates the Models and collision of all of the GridMap's chunks. This is necessary after changing the GridMap's CellSize
+// or ChunkSize, or after editing the Mesh or settings of a GridTile that has already been placed.
+func (gridMap *GridMap) RebuildAll() {
+
+	for _, chunk := range gridMap.chunks {
+		gridMap.tiles.RemoveChildren(chunk.nodes...)
+	}
+
+	gridMap.chunks = map[GridCell]*gridChunk{}
+
+	for cell := range gridMap.cells {
+		gridMap.dirtyChunk(cell)
+	}
+
+	gridMap.Rebuild()
+
+}
+
+func (gridMap *GridMap) sortedChunks() []GridCell {
+	positions := make([]GridCell, 0, len(gridMap.chunks))
+	for pos := range gridMap.chunks {
+		positions = append(positions, pos)
+	}
+	sortGridCells(positions)
+	return positions
+}
+
+func (gridMap *GridMap) rebuildChunk(chunkPos GridCell, chunk *gridChunk) {
+
+	gridMap.tiles.RemoveChildren(chunk.nodes...)
+	chunk.nodes = chunk.nodes[:0]
+	chunk.dirty = false
+
+	chunkName := "Chunk " + strconv.Itoa(chunkPos.X) + "," + strconv.Itoa(chunkPos.Y) + "," + strconv.Itoa(chunkPos.Z)
+
+	staticModels := []*Model{}
+	collisionModels := []*Model{}
+
+	size := gridMap.ChunkSize
+	if size < 1 {
+		size = 1
+	}
+
+	// Cells are visited in a fixed order so that the generated Meshes are identical from build to build.
+	for y := chunkPos.Y * size; y < (chunkPos.Y+1)*size; y++ {
+		for z := chunkPos.Z * size; z < (chunkPos.Z+1)*size; z++ {
+			for x := chunkPos.X * size; x < (chunkPos.X+1)*size; x++ {
+
+				data, exists := gridMap.cells[GridCell{x, y, z}]
+				if !exists || data.tile.Mesh == nil {
+					continue
+				}
+
+				tile := data.tile
+				position := gridMap.CellToLocal(x, y, z)
+				rotation := NewMatrix4Rotate(0, 1, 0, float64(data.rotation)*math.Pi/2)
+				cellName := tile.Name + " " + strconv.Itoa(x) + "," + strconv.Itoa(y) + "," + strconv.Itoa(z)
+
+				model := NewModel(tile.Mesh, cellName)
+				model.SetLocalPosition(position)
+				model.SetLocalRotation(rotation)
+
+				if tile.Static {
+					staticModels = append(staticModels, model)
+				} else {
+					chunk.nodes = append(chunk.nodes, model)
+				}
+
+				switch tile.Collision {
+
+				case GridCollisionAABB:
+					bounds := tile.Mesh.Bounds().Transform(rotation.Mult(NewMatrix4Translate(position[0], position[1], position[2])))
+					box := NewBoundingAABB(cellName+" Collision", bounds.Width(), bounds.Height(), bounds.Depth())
+					box.SetLocalPosition(bounds.Center())
+					chunk.nodes = append(chunk.nodes, box)
+
+				case GridCollisionTriangles:
+					collisionModels = append(collisionModels, model)
+
+				}
+
+			}
+		}
+	}
+
+	if len(staticModels) > 0 {
+		merged := NewModel(NewMesh(chunkName), chunkName)
+		merged.Merge(staticModels...)
+		chunk.nodes = append(chunk.nodes, merged)
+	}
+
+	if len(collisionModels) > 0 {
+		collisionMesh := NewModel(NewMesh(chunkName+" Collision"), chunkName+" Collision")
+		collisionMesh.Merge(collisionModels...)
+		chunk.nodes = append(chunk.nodes, NewBoundingTriangles(chunkName+" Collision", collisionMesh.Mesh))
+	}
+
+	gridMap.tiles.AddChildren(chunk.nodes...)
+
+}
+
+// Colliders returns the BoundingObjects generated for the GridMap's tiles when its chunks were last built, suitable for passing to
+// BoundingObject.CollisionTest() and similar functions.
+func (gridMap *GridMap) Colliders() []BoundingObject {
+
+	colliders := []BoundingObject{}
+
+	for _, chunkPos := range gridMap.sortedChunks() {
+		for _, node := range gridMap.chunks[chunkPos].nodes {
+			if bounds, ok := node.(BoundingObject); ok {
+				colliders = append(colliders, bounds)
+			}
+		}
+	}
+
+	return colliders
+
+}
+
+/////
+
+// AddChildren parents the provided children Nodes to the passed parent Node, inheriting its transformations and being under it in the scenegraph
+// hierarchy. If the children are already parented to other Nodes, they are unparented before doing so.
+func (gridMap *GridMap) AddChildren(children ...INode) {
+	gridMap.addChildren(gridMap, children...)
+}
+
+// Unparent unparents the GridMap from its parent, removing it from the scenegraph.
+func (gridMap *GridMap) Unparent() {
+	if gridMap.parent != nil {
+		gridMap.parent.RemoveChildren(gridMap)
+	}
+}
+
+// Type returns the NodeType for this object.
+func (gridMap *GridMap) Type() NodeType {
+	return NodeTypeGridMap
+}
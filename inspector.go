@@ -0,0 +1,504 @@
+package tetra3d
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	InspectorModeTranslate = iota // The Inspector's transform editing keys move the selected Node
+	InspectorModeRotate           // The Inspector's transform editing keys rotate the selected Node
+	InspectorModeScale            // The Inspector's transform editing keys scale the selected Node
+)
+
+const inspectorLineHeight = 16
+
+// Inspector is an optional, toggleable in-game debug overlay. It lists the scene tree underneath a root Node, and allows you to select a Node
+// (by clicking on it in the list, or using the Up and Down arrow keys) to view and edit its transform, tags, visibility, and bounding shapes.
+// The selected Node is highlighted in the viewport. To use it, call Inspector.Update() from your game's Update() function, and
+// Inspector.Draw() from your game's Draw() function (after drawing the Camera's color texture to the screen).
+//
+// Controls (when the Inspector is visible):
+// Tab cycles between translating, rotating, and scaling the selected Node; J / L, I / K, and U / O edit the X, Y, and Z axes respectively
+// (holding Shift makes changes ten times larger); V toggles the selected Node's visibility; the mouse wheel scrolls the scene tree.
+// [ and ] select one of the selected Node's tags; T adds a new tag, E edits the selected tag, and Delete removes it. When adding or
+// editing, type the tag as "name=value" (or just "name" for a tag set to true) and press Enter to apply it, or Escape to cancel.
+// Values that parse as integers or floats are stored as such; anything else is stored as a string.
+type Inspector struct {
+	Camera    *Camera    // The Camera used to highlight the selected Node in the viewport
+	Root      INode      // The root Node of the scene tree to inspect
+	Visible   bool       // Whether the Inspector is visible (and so handling input)
+	ToggleKey ebiten.Key // The key that toggles the Inspector's visibility. Defaults to F1.
+	Selected  INode      // The currently selected Node; can be nil
+	Mode      int        // The Inspector's transform editing mode; should be one of the InspectorMode constants. Defaults to InspectorModeTranslate.
+
+	MoveStep   float64 // How far the selected Node moves each tick a translation key is held. Defaults to 0.05.
+	RotateStep float64 // How far (in radians) the selected Node rotates each tick a rotation key is held. Defaults to 0.02.
+	ScaleStep  float64 // How much the selected Node's scale changes each tick a scale key is held. Defaults to 0.01.
+
+	TreeWidth      float64 // The width of the scene tree panel. Defaults to 260.
+	DetailsWidth   float64 // The width of the selected Node's details panel. Defaults to 320.
+	TextColor      *Color  // The color of the Inspector's text. Defaults to white.
+	HighlightColor *Color  // The color used to highlight the selected Node. Defaults to yellow.
+	PanelColor     *Color  // The color of the Inspector's panels. Defaults to translucent black.
+
+	scroll     int
+	rows       []INode
+	depths     []int
+	tagIndex   int
+	tagNode    INode
+	editingTag bool
+	editedTag  string // The name of the tag being edited; empty when adding a new tag
+	tagInput   []rune
+}
+
+// NewInspector returns a new Inspector, using the Camera provided to highlight Nodes under the root Node provided.
+func NewInspector(camera *Camera, root INode) *Inspector {
+	return &Inspector{
+		Camera:         camera,
+		Root:           root,
+		ToggleKey:      ebiten.KeyF1,
+		Mode:           InspectorModeTranslate,
+		MoveStep:       0.05,
+		RotateStep:     0.02,
+		ScaleStep:      0.01,
+		TreeWidth:      260,
+		DetailsWidth:   320,
+		TextColor:      NewColor(1, 1, 1, 1),
+		HighlightColor: NewColor(1, 1, 0, 1),
+		PanelColor:     NewColor(0, 0, 0, 0.6),
+	}
+}
+
+// Update handles the Inspector's input. It should be called once per tick from your game's Update() function.
+func (inspector *Inspector) Update() {
+
+	if inpututil.IsKeyJustPressed(inspector.ToggleKey) {
+		inspector.Visible = !inspector.Visible
+		inspector.editingTag = false
+	}
+
+	if !inspector.Visible || inspector.Root == nil {
+		return
+	}
+
+	// Typing a tag takes over the keyboard until it's applied or cancelled.
+	if inspector.editingTag {
+		inspector.updateTagInput()
+		return
+	}
+
+	inspector.updateRows()
+
+	// Scrolling and selection
+
+	_, wheel := ebiten.Wheel()
+	if wheel > 0 {
+		inspector.scroll--
+	} else if wheel < 0 {
+		inspector.scroll++
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyUp) {
+		inspector.selectOffset(-1)
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyDown) {
+		inspector.selectOffset(1)
+	}
+
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		mx, my := ebiten.CursorPosition()
+		if float64(mx) < inspector.TreeWidth {
+			row := my/inspectorLineHeight - 1 + inspector.scroll
+			if row >= 0 && row < len(inspector.rows) {
+				inspector.Selected = inspector.rows[row]
+			}
+		}
+	}
+
+	inspector.clampScroll()
+
+	// Editing
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		inspector.Mode = (inspector.Mode + 1) % 3
+	}
+
+	if inspector.Selected == nil {
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyV) {
+		inspector.Selected.SetVisible(!inspector.Selected.Visible(), false)
+	}
+
+	tagNames := inspector.selectedTagNames()
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyBracketLeft) {
+		inspector.tagIndex--
+	} else if inpututil.IsKeyJustPressed(ebiten.KeyBracketRight) {
+		inspector.tagIndex++
+	}
+
+	if len(tagNames) > 0 {
+		inspector.tagIndex = (inspector.tagIndex + len(tagNames)) % len(tagNames)
+	} else {
+		inspector.tagIndex = 0
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyT) {
+		inspector.editingTag = true
+		inspector.editedTag = ""
+		inspector.tagInput = inspector.tagInput[:0]
+		return
+	}
+
+	if len(tagNames) > 0 {
+
+		selectedTag := tagNames[inspector.tagIndex]
+
+		if inpututil.IsKeyJustPressed(ebiten.KeyE) {
+			inspector.editingTag = true
+			inspector.editedTag = selectedTag
+			inspector.tagInput = append(inspector.tagInput[:0], []rune(fmt.Sprintf("%s=%v", selectedTag, inspector.Selected.Tags().Get(selectedTag)))...)
+			return
+		}
+
+		if inpututil.IsKeyJustPressed(ebiten.KeyDelete) {
+			inspector.Selected.Tags().Remove(selectedTag)
+		}
+
+	}
+
+	axis := [3]float64{}
+
+	keys := [3][2]ebiten.Key{
+		{ebiten.KeyJ, ebiten.KeyL},
+		{ebiten.KeyK, ebiten.KeyI},
+		{ebiten.KeyO, ebiten.KeyU},
+	}
+
+	edited := false
+
+	for i, pair := range keys {
+		if ebiten.IsKeyPressed(pair[0]) {
+			axis[i]--
+			edited = true
+		}
+		if ebiten.IsKeyPressed(pair[1]) {
+			axis[i]++
+			edited = true
+		}
+	}
+
+	if !edited {
+		return
+	}
+
+	multiplier := 1.0
+	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+		multiplier = 10
+	}
+
+	node := inspector.Selected
+
+	switch inspector.Mode {
+
+	case InspectorModeTranslate:
+		step := inspector.MoveStep * multiplier
+		node.Move(axis[0]*step, axis[1]*step, axis[2]*step)
+
+	case InspectorModeRotate:
+		step := inspector.RotateStep * multiplier
+		for i, amount := range axis {
+			if amount != 0 {
+				dir := [3]float64{}
+				dir[i] = 1
+				node.Rotate(dir[0], dir[1], dir[2], amount*step)
+			}
+		}
+
+	case InspectorModeScale:
+		step := inspector.ScaleStep * multiplier
+		scale := node.LocalScale()
+		node.SetLocalScale(scale.Add([]float64{axis[0] * step, axis[1] * step, axis[2] * step}))
+
+	}
+
+}
+
+// selectedTagNames returns the sorted names of the selected Node's tags, resetting the selected tag if the selected Node changed.
+func (inspector *Inspector) selectedTagNames() []string {
+	if inspector.Selected != inspector.tagNode {
+		inspector.tagNode = inspector.Selected
+		inspector.tagIndex = 0
+	}
+	if inspector.Selected == nil {
+		return nil
+	}
+	return inspector.Selected.Tags().Names()
+}
+
+func (inspector *Inspector) updateTagInput() {
+
+	if inspector.Selected == nil || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		inspector.editingTag = false
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter) {
+		inspector.applyTagInput()
+		inspector.editingTag = false
+		return
+	}
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && len(inspector.tagInput) > 0 {
+		inspector.tagInput = inspector.tagInput[:len(inspector.tagInput)-1]
+	}
+
+	inspector.tagInput = ebiten.AppendInputChars(inspector.tagInput)
+
+}
+
+func (inspector *Inspector) applyTagInput() {
+
+	name, valueText := string(inspector.tagInput), ""
+	hasValue := false
+
+	if index := strings.Index(name, "="); index >= 0 {
+		name, valueText = name[:index], name[index+1:]
+		hasValue = true
+	}
+
+	name = strings.TrimSpace(name)
+	valueText = strings.TrimSpace(valueText)
+
+	if name == "" {
+		return
+	}
+
+	var value interface{} = true
+
+	if hasValue {
+		if i, err := strconv.Atoi(valueText); err == nil {
+			value = i
+		} else if f, err := strconv.ParseFloat(valueText, 64); err == nil {
+			value = f
+		} else {
+			value = valueText
+		}
+	}
+
+	tags := inspector.Selected.Tags()
+
+	// Renaming a tag while editing it replaces the original.
+	if inspector.editedTag != "" && inspector.editedTag != name {
+		tags.Remove(inspector.editedTag)
+	}
+
+	tags.Set(name, value)
+
+	for i, tagName := range tags.Names() {
+		if tagName == name {
+			inspector.tagIndex = i
+			break
+		}
+	}
+
+}
+
+func (inspector *Inspector) updateRows() {
+
+	inspector.rows = inspector.rows[:0]
+	inspector.depths = inspector.depths[:0]
+
+	var add func(node INode, depth int)
+
+	add = func(node INode, depth int) {
+		inspector.rows = append(inspector.rows, node)
+		inspector.depths = append(inspector.depths, depth)
+		for _, child := range node.Children() {
+			add(child, depth+1)
+		}
+	}
+
+	add(inspector.Root, 0)
+
+}
+
+func (inspector *Inspector) selectOffset(offset int) {
+
+	if len(inspector.rows) == 0 {
+		return
+	}
+
+	index := -1
+	for i, row := range inspector.rows {
+		if row == inspector.Selected {
+			index = i
+			break
+		}
+	}
+
+	index += offset
+
+	if index < 0 {
+		index = 0
+	} else if index >= len(inspector.rows) {
+		index = len(inspector.rows) - 1
+	}
+
+	inspector.Selected = inspector.rows[index]
+
+	// Keep the selection on-screen.
+	visibleRows := inspector.visibleRowCount()
+	if index < inspector.scroll {
+		inspector.scroll = index
+	} else if index >= inspector.scroll+visibleRows {
+		inspector.scroll = index - visibleRows + 1
+	}
+
+}
+
+func (inspector *Inspector) visibleRowCount() int {
+	if inspector.Camera == nil {
+		return 20
+	}
+	_, h := inspector.Camera.ColorTexture().Size()
+	count := h/inspectorLineHeight - 2
+	if count < 1 {
+		count = 1
+	}
+	return count
+}
+
+func (inspector *Inspector) clampScroll() {
+	max := len(inspector.rows) - inspector.visibleRowCount()
+	if inspector.scroll > max {
+		inspector.scroll = max
+	}
+	if inspector.scroll < 0 {
+		inspector.scroll = 0
+	}
+}
+
+// Draw draws the Inspector (if it's visible) to the screen provided, highlighting the selected Node in the viewport. The screen should be the
+// same size as the Camera's color texture for the highlight to line up.
+func (inspector *Inspector) Draw(screen *ebiten.Image) {
+
+	if !inspector.Visible || inspector.Root == nil || inspector.Camera == nil {
+		return
+	}
+
+	if len(inspector.rows) == 0 {
+		inspector.updateRows()
+	}
+
+	camera := inspector.Camera
+	screenWidth, screenHeight := screen.Size()
+	panelColor := inspector.PanelColor.ToRGBA64()
+
+	// Highlight the selection in the viewport
+
+	if inspector.Selected != nil {
+
+		hc := inspector.HighlightColor
+
+		if _, isModel := inspector.Selected.(*Model); isModel {
+			camera.DrawDebugWireframe(screen, inspector.Selected, hc)
+		}
+
+		camera.DrawDebugBoundsColored(screen, inspector.Selected, hc, hc, hc, hc)
+		camera.drawCircle(screen, inspector.Selected.WorldPosition(), 8, hc.ToRGBA64())
+
+	}
+
+	// Scene tree panel
+
+	ebitenutil.DrawRect(screen, 0, 0, inspector.TreeWidth, float64(screenHeight), panelColor)
+
+	modeNames := []string{"Translate", "Rotate", "Scale"}
+	camera.DebugDrawText(screen, "Scene Tree ["+modeNames[inspector.Mode]+"]", 0, 0, 1, inspector.TextColor)
+
+	visibleRows := inspector.visibleRowCount()
+
+	for i := inspector.scroll; i < len(inspector.rows) && i < inspector.scroll+visibleRows; i++ {
+
+		node := inspector.rows[i]
+		y := float64((i - inspector.scroll + 1) * inspectorLineHeight)
+
+		textColor := inspector.TextColor
+		if node == inspector.Selected {
+			textColor = inspector.HighlightColor
+		} else if !node.Visible() {
+			textColor = inspector.TextColor.Clone()
+			textColor.MultiplyRGBA(0.5, 0.5, 0.5, 1)
+		}
+
+		camera.DebugDrawText(screen, strings.Repeat("  ", inspector.depths[i])+node.Name(), 0, y, 1, textColor)
+
+	}
+
+	// Details panel
+
+	if inspector.Selected == nil {
+		return
+	}
+
+	node := inspector.Selected
+
+	lines := []string{
+		node.Name(),
+		"Type: " + string(node.Type()),
+		fmt.Sprintf("Visible: %t", node.Visible()),
+		"",
+		fmt.Sprintf("Position: %.3f, %.3f, %.3f", node.LocalPosition()[0], node.LocalPosition()[1], node.LocalPosition()[2]),
+	}
+
+	yaw, pitch, roll := node.LocalRotationEuler()
+	lines = append(lines,
+		fmt.Sprintf("Rotation: %.1f, %.1f, %.1f", yaw*180/math.Pi, pitch*180/math.Pi, roll*180/math.Pi),
+		fmt.Sprintf("Scale: %.3f, %.3f, %.3f", node.LocalScale()[0], node.LocalScale()[1], node.LocalScale()[2]),
+		"",
+	)
+
+	tagNames := inspector.selectedTagNames()
+	if len(tagNames) > 0 || inspector.editingTag {
+		lines = append(lines, "Tags:")
+		for i, name := range tagNames {
+			prefix := "  "
+			if i == inspector.tagIndex {
+				prefix = "> "
+			}
+			lines = append(lines, fmt.Sprintf("%s%s: %v", prefix, name, node.Tags().Get(name)))
+		}
+		if inspector.editingTag {
+			lines = append(lines, "  Tag: "+string(inspector.tagInput)+"_")
+		}
+		lines = append(lines, "")
+	}
+
+	bounds := []string{}
+	for _, child := range append([]INode{node}, node.Children()...) {
+		if _, isBounds := child.(BoundingObject); isBounds {
+			bounds = append(bounds, "  "+child.Name()+" ("+string(child.Type())+")")
+		}
+	}
+	if len(bounds) > 0 {
+		lines = append(lines, "Bounds:")
+		lines = append(lines, bounds...)
+	}
+
+	x := float64(screenWidth) - inspector.DetailsWidth
+	ebitenutil.DrawRect(screen, x, 0, inspector.DetailsWidth, float64((len(lines)+1)*inspectorLineHeight), panelColor)
+
+	for i, line := range lines {
+		camera.DebugDrawText(screen, line, x, float64(i*inspectorLineHeight), 1, inspector.TextColor)
+	}
+
+}
@@ -2,8 +2,6 @@ package tetra3d
 
 import (
 	"math"
-
-	"github.com/kvartborg/vector"
 )
 
 // Light represents an interface that is fulfilled by an object that emits light, returning the color a vertex should be given that Vertex and its model matrix.
@@ -18,6 +16,17 @@ type Light interface {
 
 	Light(triIndex int, model *Model) [9]float32 // Light() returns the R, G, and B colors used to light the vertices of the given triangle.
 	isOn() bool                                  // isOn() is simply used to tell if a "generic" Light is on or not.
+	state() lightState                           // state() returns the properties of the Light that affect lighting results; used to tell if cached lighting is still valid.
+}
+
+// lightState represents the state of a Light that affects its lighting results. If a Light's state is unchanged from one frame to the next
+// (and the lit Model hasn't changed either), the lighting results are the same, and so can be cached.
+type lightState struct {
+	light            Light
+	transformVersion uint64
+	r, g, b          float32
+	energy           float32
+	distance         float64
 }
 
 //---------------//
@@ -85,6 +94,16 @@ func (amb *AmbientLight) isOn() bool {
 	return amb.On
 }
 
+func (amb *AmbientLight) state() lightState {
+	return lightState{
+		light:  amb,
+		r:      amb.Color.R,
+		g:      amb.Color.G,
+		b:      amb.Color.B,
+		energy: amb.Energy,
+	}
+}
+
 // Type returns the NodeType for this object.
 func (amb *AmbientLight) Type() NodeType {
 	return NodeTypeAmbientLight
@@ -107,8 +126,8 @@ type PointLight struct {
 	// If the light is on and contributing to the scene.
 	On bool
 
-	workingPosition vector.Vector
-	cameraPosition  vector.Vector
+	workingPosition Vector3
+	cameraPosition  Vector3
 }
 
 // NewPointLight creates a new Point light.
@@ -149,12 +168,13 @@ func (point *PointLight) beginModel(model *Model, camera *Camera) {
 	// point light's position by the inversion of the model's transform to get the same effect and save processing time.
 	// The same technique is used for Sphere - Triangle collision in bounds.go.
 
-	if model.Skinned {
-		point.cameraPosition = camera.WorldPosition()
-		point.workingPosition = point.WorldPosition()
+	if positions, _ := model.worldSpaceVertices(); positions != nil {
+		point.cameraPosition = NewVector3FromVector(camera.WorldPosition())
+		point.workingPosition = NewVector3FromVector(point.WorldPosition())
 	} else {
-		point.cameraPosition = r.MultVec(camera.WorldPosition()).Add(p)
-		point.workingPosition = r.MultVec(point.WorldPosition()).Add(p)
+		pos := NewVector3FromVector(p)
+		point.cameraPosition = r.MultVec3(NewVector3FromVector(camera.WorldPosition())).Add(pos)
+		point.workingPosition = r.MultVec3(NewVector3FromVector(point.WorldPosition())).Add(pos)
 	}
 
 }
@@ -170,18 +190,20 @@ func (point *PointLight) Light(triIndex int, model *Model) [9]float32 {
 	// lit face and backface culling is off, the triangle can still be lit or unlit from the other side. Otherwise,
 	// if the triangle were lit by a light, it would appear lit regardless of the positioning of the camera.
 
-	var triCenter vector.Vector
+	var triCenter Vector3
+
+	positions, normals := model.worldSpaceVertices()
 
-	if model.Skinned {
-		v0 := model.Mesh.vertexSkinnedPositions[triIndex*3].Clone()
-		v1 := model.Mesh.vertexSkinnedPositions[triIndex*3+1]
-		v2 := model.Mesh.vertexSkinnedPositions[triIndex*3+2]
-		triCenter = vector.Vector(vector.In(v0).Add(v1).Add(v2).Scale(1.0 / 3.0))
+	if positions != nil {
+		v0 := positions[triIndex*3]
+		v1 := positions[triIndex*3+1]
+		v2 := positions[triIndex*3+2]
+		triCenter = v0.Add(v1).Add(v2).Scale(1.0 / 3.0)
 	} else {
-		triCenter = model.Mesh.Triangles[triIndex].Center
+		triCenter = NewVector3FromVector(model.Mesh.Triangles[triIndex].Center)
 	}
 
-	dist := fastVectorSub(point.workingPosition, triCenter)
+	dist := point.workingPosition.Sub(triCenter)
 
 	distanceSquared := math.Pow(point.Distance, 2)
 
@@ -194,27 +216,21 @@ func (point *PointLight) Light(triIndex int, model *Model) [9]float32 {
 	// 	return light
 	// }
 
-	var vertPos, vertNormal vector.Vector
+	var vertPos, vertNormal Vector3
 
 	for i := 0; i < 3; i++ {
 
-		if model.Skinned {
-			vertPos = model.Mesh.vertexSkinnedPositions[triIndex*3+i]
-			vertNormal = model.Mesh.vertexSkinnedNormals[triIndex*3+i]
+		if positions != nil {
+			vertPos = positions[triIndex*3+i]
+			vertNormal = normals[triIndex*3+i]
 		} else {
-			vertPos = model.Mesh.VertexPositions[triIndex*3+i]
-			vertNormal = model.Mesh.VertexNormals[triIndex*3+i]
+			vertPos = NewVector3FromVector(model.vertexPositions()[triIndex*3+i])
+			vertNormal = NewVector3FromVector(model.vertexNormals()[triIndex*3+i])
 		}
 
-		lightVec := vector.In(fastVectorSub(point.workingPosition, vertPos)).Unit()
-		diffuse := dot(vertNormal, vector.Vector(lightVec))
-
-		if diffuse < 0 {
-			diffuse = 0
-		}
+		diffuse, distance := pipelinePointDiffuse(point.workingPosition, vertPos, vertNormal)
 
 		var diffuseFactor float64
-		distance := fastVectorDistanceSquared(point.workingPosition, vertPos)
 
 		if point.Distance == 0 {
 			diffuseFactor = diffuse * (1.0 / (1.0 + (0.1 * distance))) * 2
@@ -249,6 +265,18 @@ func (point *PointLight) isOn() bool {
 	return point.On
 }
 
+func (point *PointLight) state() lightState {
+	return lightState{
+		light:            point,
+		transformVersion: point.transformVersion,
+		r:                point.Color.R,
+		g:                point.Color.G,
+		b:                point.Color.B,
+		energy:           point.Energy,
+		distance:         point.Distance,
+	}
+}
+
 // Type returns the NodeType for this object.
 func (point *PointLight) Type() NodeType {
 	return NodeTypePointLight
@@ -265,8 +293,8 @@ type DirectionalLight struct {
 	Energy float32
 	On     bool // If the light is on and contributing to the scene.
 
-	workingForward       vector.Vector // Internal forward vector so we don't have to calculate it for every triangle for every model using this light.
-	workingModelRotation Matrix4       // Similarly, this is an internal rotational transform (without the transformation row) for the Model being lit.
+	workingForward       Vector3        // Internal forward vector so we don't have to calculate it for every triangle for every model using this light.
+	workingModelRotation pipelineMatrix // Similarly, this is an internal rotational transform (without the transformation row) for the Model being lit.
 }
 
 // NewDirectionalLight creates a new Directional Light with the specified RGB color and energy (assuming 1.0 energy is standard / "100%" lighting).
@@ -296,12 +324,12 @@ func (sun *DirectionalLight) Clone() INode {
 }
 
 func (sun *DirectionalLight) beginRender() {
-	sun.workingForward = sun.WorldRotation().Forward() // Already reversed
+	sun.workingForward = NewVector3FromVector(sun.WorldRotation().Forward()) // Already reversed
 }
 
 func (sun *DirectionalLight) beginModel(model *Model, camera *Camera) {
-	if !model.Skinned {
-		sun.workingModelRotation = model.WorldRotation().Inverted().Transposed()
+	if positions, _ := model.worldSpaceVertices(); positions == nil {
+		sun.workingModelRotation = newPipelineMatrix(model.WorldRotation().Inverted().Transposed())
 	}
 }
 
@@ -310,17 +338,19 @@ func (sun *DirectionalLight) Light(triIndex int, model *Model) [9]float32 {
 
 	light := [9]float32{}
 
+	_, normals := model.worldSpaceVertices()
+
 	for i := 0; i < 3; i++ {
 
-		var normal vector.Vector
-		if model.Skinned {
-			// If it's skinned, we don't have to calculate the normal, as that's been pre-calc'd for us
-			normal = model.Mesh.vertexSkinnedNormals[triIndex*3+i]
+		var diffuseFactor float64
+		if normals != nil {
+			// If it's skinned or static, we don't have to calculate the normal, as that's been pre-calc'd for us
+			diffuseFactor = normals[triIndex*3+i].Dot(sun.workingForward)
 		} else {
-			normal = sun.workingModelRotation.MultVec(model.Mesh.VertexNormals[triIndex*3+i])
+			x, y, z := pipelineMultVecRotation(&sun.workingModelRotation, model.vertexNormals()[triIndex*3+i])
+			diffuseFactor = x*sun.workingForward.X + y*sun.workingForward.Y + z*sun.workingForward.Z
 		}
 
-		diffuseFactor := dot(normal, sun.workingForward)
 		if diffuseFactor < 0 {
 			diffuseFactor = 0
 		}
@@ -352,6 +382,17 @@ func (sun *DirectionalLight) isOn() bool {
 	return sun.On
 }
 
+func (sun *DirectionalLight) state() lightState {
+	return lightState{
+		light:            sun,
+		transformVersion: sun.transformVersion,
+		r:                sun.Color.R,
+		g:                sun.Color.G,
+		b:                sun.Color.B,
+		energy:           sun.Energy,
+	}
+}
+
 // Type returns the NodeType for this object.
 func (sun *DirectionalLight) Type() NodeType {
 	return NodeTypeDirectionalLight
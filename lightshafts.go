@@ -0,0 +1,195 @@
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kvartborg/vector"
+)
+
+// lightShaftsShaderSrc blurs the area around a light's on-screen position outwards from it (image 0 being the Camera's depth texture),
+// only counting pixels that aren't blocked by anything closer to the Camera than the light, so shafts of light stream past occluders.
+var lightShaftsShaderSrc = []byte(
+	`package main
+
+	var LightPosition vec2
+	var LightDepth float
+	var LightColor vec3
+	var Radius float
+	var Samples float
+	var Density float
+	var Decay float
+	var Intensity float
+
+	func decodeDepth(rgba vec4) float {
+		return rgba.r + (rgba.g / 255) + (rgba.b / 65025)
+	}
+
+	func emission(pos vec2) float {
+		depth := imageSrc0At(pos / imageSrcTextureSize())
+		if depth.a > 0 && decodeDepth(depth) < LightDepth {
+			return 0
+		}
+		return clamp(1-distance(pos, LightPosition)/Radius, 0, 1)
+	}
+
+	func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+
+		delta := (position.xy - LightPosition) * Density / Samples
+		pos := position.xy
+		weight := 1.0
+		sum := 0.0
+
+		for i := 0; i < 128; i++ {
+			if float(i) >= Samples {
+				break
+			}
+			pos -= delta
+			sum += emission(pos) * weight
+			weight *= Decay
+		}
+
+		sum = clamp(sum*Intensity/Samples, 0, 1)
+
+		return vec4(LightColor*sum, sum)
+
+	}
+	`,
+)
+
+// maxLightShaftSamples is the maximum number of samples the light shafts shader can take for each pixel.
+const maxLightShaftSamples = 128
+
+// LightShafts is a post-processing effect that draws shafts of light (also known as god rays) streaming out from the on-screen positions of
+// point and directional lights past anything blocking them, which is cheap and dramatic for light coming through windows or sunsets.
+// Directional lights are treated as though they're infinitely far away in the direction they shine from, like the sun. As LightShafts uses the
+// Camera's depth texture to tell what's blocking each light, the Camera must render depth. To use it, call LightShafts.Apply() after rendering
+// and before drawing the Camera's ColorTexture() to the screen. Each light drawn with LightShafts costs a full-screen shader pass.
+type LightShafts struct {
+	Samples      int     // How many samples are taken towards each light for each pixel; more samples give smoother shafts but cost more. The maximum is 128. Defaults to 48.
+	Density      float64 // How much of the distance towards each light the samples cover, ranging from 0 to 1; higher values give longer shafts. Defaults to 0.8.
+	Decay        float64 // How much each sample's weight falls off compared to the previous one, ranging from 0 to 1; lower values give shorter shafts. Defaults to 0.96.
+	Intensity    float64 // The overall brightness of the shafts. Defaults to 1.
+	SourceRadius float64 // The radius around each light's on-screen position that emits shafts, as a fraction of the Camera's texture height. Defaults to 0.25.
+
+	shader  *ebiten.Shader
+	options *ebiten.DrawRectShaderOptions
+	lights  []Light
+}
+
+// NewLightShafts returns a new LightShafts post-processing effect.
+func NewLightShafts() *LightShafts {
+
+	shader, err := ebiten.NewShader(lightShaftsShaderSrc)
+
+	if err != nil {
+		panic(err)
+	}
+
+	shafts := &LightShafts{
+		Samples:      48,
+		Density:      0.8,
+		Decay:        0.96,
+		Intensity:    1,
+		SourceRadius: 0.25,
+		shader:       shader,
+		options: &ebiten.DrawRectShaderOptions{
+			CompositeMode: ebiten.CompositeModeLighter,
+			Uniforms:      map[string]interface{}{},
+		},
+	}
+
+	return shafts
+
+}
+
+// Apply draws light shafts for the point and directional lights that are on in the subtree of the rootNode provided (including the rootNode
+// itself) onto the Camera's ColorTexture(), additively. Lights that are behind the Camera or too far off-screen are skipped.
+func (shafts *LightShafts) Apply(camera *Camera, rootNode INode) {
+
+	if !camera.RenderDepth {
+		panic("error: LightShafts requires the Camera to render depth")
+	}
+
+	shafts.lights = shafts.lights[:0]
+
+	if light, isLight := rootNode.(Light); isLight {
+		shafts.lights = append(shafts.lights, light)
+	}
+
+	shafts.lights = appendLightsRecursive(shafts.lights, rootNode)
+
+	samples := shafts.Samples
+	if samples > maxLightShaftSamples {
+		samples = maxLightShaftSamples
+	} else if samples < 1 {
+		samples = 1
+	}
+
+	w, h := camera.ColorTexture().Size()
+	radius := shafts.SourceRadius * float64(h)
+
+	uniforms := shafts.options.Uniforms
+	uniforms["Radius"] = float32(radius)
+	uniforms["Samples"] = float32(samples)
+	uniforms["Density"] = float32(shafts.Density)
+	uniforms["Decay"] = float32(shafts.Decay)
+	uniforms["Intensity"] = float32(shafts.Intensity)
+
+	shafts.options.Images[0] = camera.DepthTexture()
+
+	for _, light := range shafts.lights {
+
+		if !light.isOn() {
+			continue
+		}
+
+		var position vector.Vector
+		var color *Color
+		var energy float32
+
+		// The light's depth is compared against the Camera's depth texture; directional lights are infinitely far away, so anything blocks them.
+		depth := 2.0
+
+		switch l := light.(type) {
+		case *PointLight:
+			position = l.WorldPosition()
+			color = l.Color
+			energy = l.Energy
+		case *DirectionalLight:
+			position = camera.WorldPosition().Add(l.WorldRotation().Forward().Scale(camera.Far))
+			color = l.Color
+			energy = l.Energy
+		default:
+			continue
+		}
+
+		clip := camera.WorldToClip(position)
+
+		if clip[3] <= 0 {
+			continue
+		}
+
+		if _, isPoint := light.(*PointLight); isPoint {
+			depth = (clip[2]+camera.Near)/camera.Far + 0.03
+		}
+
+		screen := camera.ClipToScreen(clip)
+
+		if screen[0] < -radius || screen[0] > float64(w)+radius || screen[1] < -radius || screen[1] > float64(h)+radius {
+			continue
+		}
+
+		uniforms["LightPosition"] = []float32{float32(screen[0]), float32(screen[1])}
+		uniforms["LightDepth"] = float32(depth)
+		uniforms["LightColor"] = []float32{
+			float32(math.Min(float64(color.R*energy), 1)),
+			float32(math.Min(float64(color.G*energy), 1)),
+			float32(math.Min(float64(color.B*energy), 1)),
+		}
+
+		camera.ColorTexture().DrawRectShader(w, h, shafts.shader, shafts.options)
+
+	}
+
+}
@@ -45,12 +45,14 @@ type Material struct {
 	Shadeless         bool                 // If the material should be shadeless (unlit) or not
 	CompositeMode     ebiten.CompositeMode // Blend mode to use when rendering the material (i.e. additive, multiplicative, etc)
 	BillboardMode     int                  // Billboard mode
+	DepthTest         bool                 // If the Material is hidden behind closer objects when the Camera renders depth; if false, it's drawn over everything rendered before it. Defaults to true.
 
-	// VertexTransformFunction is a function that runs on the world position of each vertex position rendered with the material.
+	// VertexTransformFunction is a function that runs on the local (model-space) position of each vertex position rendered with the material.
 	// It accepts the vertex position as an argument, along with the index of the vertex in the mesh.
 	// One can use this to simply transform vertices of the mesh on CPU (note that this is, of course, not as performant as
 	// a traditional GPU vertex shader, but is fine for simple / low-poly mesh transformations).
 	// This function is run after skinning the vertex if the material belongs to a mesh that is skinned by an armature.
+	// For skinned and Static Models, the vertex positions passed are in world space, as that's the space they're cached in.
 	// Note that the VertexTransformFunction must return the vector passed.
 	VertexTransformFunction func(vertexPosition vector.Vector, vertexIndex int) vector.Vector
 
@@ -69,11 +71,45 @@ type Material struct {
 	// CompositeMode property from the Material's CompositeMode. By default, it's an empty DrawTrianglesShaderOptions struct.
 	FragmentShaderOptions *ebiten.DrawTrianglesShaderOptions
 	fragmentSrc           []byte
+	// ShaderUniforms holds custom uniforms passed by name to the Material's fragment shader, along with the standard uniforms the Camera passes
+	// to it (see Material.SetShader()). As with Ebiten's shaders, values must be float32s or []float32s. Uniforms set here take priority over
+	// standard uniforms and FragmentShaderOptions.Uniforms of the same name. By default, it's an empty map.
+	ShaderUniforms map[string]interface{}
 
 	// If a material is tagged as transparent, it's rendered in a separate render pass.
 	// Objects with transparent materials don't render to the depth texture and are sorted and rendered back-to-front, AFTER
 	// all non-transparent materials.
 	TransparencyMode int
+
+	// TerrainMode indicates if the Material is a terrain Material, blending multiple tiling texture layers together; it's set with
+	// Material.SetTerrainSplatMap() or Material.SetTerrainVertexColors(), and is one of the TerrainMode constants. Defaults to TerrainModeNone.
+	TerrainMode int
+	// TerrainTiling is how many times a terrain Material's texture layers repeat across the Mesh's UV values. Defaults to 8.
+	TerrainTiling float64
+
+	// Reflectivity is how strongly Models using the Material reflect the nearest ReflectionProbe, ranging from 0 (not at all) to 1 (only the
+	// reflection is visible). Reflections are blended per-vertex, after lighting. Defaults to 0.
+	Reflectivity float32
+}
+
+// MaterialOverride overrides some of the properties of a MeshPart's Material for a single Model, without altering (or needing to clone) the
+// Material itself, which is shared between all Models using it. This is useful for hit flashes, team colors, or highlighting a selected
+// Model. MaterialOverrides are set using Model.SetMaterialOverride(). Each property only overrides the Material's property when it's set.
+type MaterialOverride struct {
+	Color                 *Color               // If not nil, the MeshPart is rendered with this Color instead of its Material's Color.
+	Texture               *ebiten.Image        // If not nil, the MeshPart is rendered with this texture instead of its Material's Texture.
+	TransparencyMode      int                  // If not TransparencyModeAuto, the MeshPart is rendered with this transparency mode instead of its Material's.
+	CompositeMode         ebiten.CompositeMode // The composite mode the MeshPart is rendered with instead of its Material's, if OverrideCompositeMode is true.
+	OverrideCompositeMode bool                 // Whether CompositeMode overrides the Material's CompositeMode.
+}
+
+// Clone returns a clone of the MaterialOverride.
+func (override *MaterialOverride) Clone() *MaterialOverride {
+	clone := *override
+	if override.Color != nil {
+		clone.Color = override.Color.Clone()
+	}
+	return &clone
 }
 
 // NewMaterial creates a new Material with the name given.
@@ -85,15 +121,19 @@ func NewMaterial(name string) *Material {
 		TextureFilterMode:     ebiten.FilterNearest,
 		TextureWrapMode:       ebiten.AddressRepeat,
 		BackfaceCulling:       true,
+		DepthTest:             true,
 		TriangleSortMode:      TriangleSortModeBackToFront,
 		TransparencyMode:      TransparencyModeAuto,
 		FragmentShaderOptions: &ebiten.DrawTrianglesShaderOptions{},
 		FragmentShaderOn:      true,
+		ShaderUniforms:        map[string]interface{}{},
 		CompositeMode:         ebiten.CompositeModeSourceOver,
+		TerrainTiling:         8,
 	}
 }
 
-// Clone creates a clone of the specified Material. Note that Clone() cannot clone the Material's fragment shader or shader options.
+// Clone creates a clone of the specified Material. The clone compiles its own copy of the Material's fragment shader, and shares the images and
+// uniform values of the Material's shader options.
 func (material *Material) Clone() *Material {
 	newMat := NewMaterial(material.Name)
 	newMat.library = material.library
@@ -109,9 +149,14 @@ func (material *Material) Clone() *Material {
 	newMat.CompositeMode = material.CompositeMode
 
 	newMat.BillboardMode = material.BillboardMode
+	newMat.DepthTest = material.DepthTest
 	newMat.VertexTransformFunction = material.VertexTransformFunction
 	newMat.VertexClipFunction = material.VertexClipFunction
-	newMat.SetShader(material.fragmentSrc)
+	if material.fragmentSrc != nil {
+		newMat.SetShader(material.fragmentSrc)
+	} else {
+		newMat.fragmentShader = material.fragmentShader
+	}
 	newMat.FragmentShaderOn = material.FragmentShaderOn
 
 	newMat.FragmentShaderOptions.CompositeMode = material.FragmentShaderOptions.CompositeMode
@@ -119,10 +164,20 @@ func (material *Material) Clone() *Material {
 	for i := range material.FragmentShaderOptions.Images {
 		newMat.FragmentShaderOptions.Images[i] = material.FragmentShaderOptions.Images[i]
 	}
-	for k, v := range newMat.FragmentShaderOptions.Uniforms {
-		newMat.FragmentShaderOptions.Uniforms[k] = v
+	if material.FragmentShaderOptions.Uniforms != nil {
+		newMat.FragmentShaderOptions.Uniforms = map[string]interface{}{}
+		for k, v := range material.FragmentShaderOptions.Uniforms {
+			newMat.FragmentShaderOptions.Uniforms[k] = v
+		}
+	}
+	for k, v := range material.ShaderUniforms {
+		newMat.ShaderUniforms[k] = v
 	}
 
+	newMat.TerrainMode = material.TerrainMode
+	newMat.TerrainTiling = material.TerrainTiling
+	newMat.Reflectivity = material.Reflectivity
+
 	return newMat
 }
 
@@ -131,6 +186,33 @@ func (material *Material) Clone() *Material {
 // compositing the finished render to the screen after fog. If the shader is nil, the Material will render using the default Tetra3D
 // render setup (e.g. texture, UV values, vertex colors, and vertex lighting).
 // SetShader will return the Shader, and an error if the Shader failed to compile.
+//
+// The shader is drawn with the MeshPart's texture as its first image (unless another is set in FragmentShaderOptions.Images[0]), so sampling
+// it at texCoord gives the texture's color at the fragment's UV position, while color is the vertex's lit color. When the Camera renders depth,
+// depth testing and fog are applied to the shader's output afterwards as normal. To help with effects like scrolling UVs, dissolves, or toon
+// ramps, the Camera also passes the following standard uniforms to the shader, which it can use by declaring them:
+//
+//	var Time float           // The time in seconds since Tetra3D started
+//	var Model mat4           // The Model's world transform
+//	var View mat4            // The Camera's view matrix
+//	var Projection mat4      // The Camera's projection matrix
+//	var Fog vec4             // The Scene's fog color (RGB) and fog mode (A, one of the FogMode constants)
+//	var FogRange [2]float    // The Scene's fog range, as in World.FogRange
+//	var CameraRange [2]float // The Camera's near and far clipping planes, which depth values in the Camera's depth texture are relative to
+//
+// The matrices are laid out so that they transform column vectors, as is usual in shaders (i.e. Projection * View * Model * vec4(position, 1)
+// transforms a vertex position from the Model's local space into clip space). Custom uniforms can be passed using Material.ShaderUniforms.
+//
+// When the Camera renders depth, its depth texture (holding the depth of everything rendered so far this frame) is passed as the shader's
+// fourth image (unless another is set in FragmentShaderOptions.Images[3]). As Ebiten requires all of a shader's images to be the same size,
+// it's stretched to the size of the first image, so it's sampled by the fragment's screen position rather than texCoord. Depth is encoded
+// across the red, green, and blue channels, ranging from 0 at the Camera's near plane to 1 at its far plane. For example:
+//
+//	dstOrigin, dstSize := imageDstRegionOnTexture()
+//	srcOrigin, srcSize := imageSrcRegionOnTexture()
+//	screenPos := (position.xy/imageDstTextureSize() - dstOrigin) / dstSize
+//	d := imageSrc3At(srcOrigin + screenPos*srcSize)
+//	depth := d.r + d.g/255 + d.b/65025
 func (material *Material) SetShader(src []byte) (*ebiten.Shader, error) {
 
 	if src == nil {
@@ -151,6 +233,15 @@ func (material *Material) SetShader(src []byte) (*ebiten.Shader, error) {
 
 }
 
+// SetCompiledShader sets the Material's custom fragment shader to the already-compiled shader provided, which is useful for sharing one shader
+// between many Materials without compiling it for each. The shader is used in the same way as shaders set with Material.SetShader(). As the
+// Material doesn't have the shader's source, cloning the Material shares the shader with the clone rather than compiling a new one, so be
+// careful when disposing it. Passing nil removes the Material's custom shader.
+func (material *Material) SetCompiledShader(shader *ebiten.Shader) {
+	material.fragmentShader = shader
+	material.fragmentSrc = nil
+}
+
 // Shader returns the custom Kage fragment shader for the Material.
 func (material *Material) Shader() *ebiten.Shader {
 	return material.fragmentShader
@@ -163,6 +163,60 @@ func NewMatrix4RotateFromQuaternion(quat *Quaternion) Matrix4 {
 	return m1.Mult(m2)
 }
 
+// NewMatrix4RotateEuler returns a new rotation Matrix4 from the provided Euler angles in radians: yaw (rotation around the Y axis), pitch
+// (rotation around the X axis), and roll (rotation around the Z axis). The rotations are applied in the order of roll, then pitch, then yaw
+// (so the result is equivalent to NewMatrix4Rotate(0, 0, 1, roll).Mult(NewMatrix4Rotate(1, 0, 0, pitch)).Mult(NewMatrix4Rotate(0, 1, 0, yaw))),
+// which is what you'd usually want for an FPS camera or turret - pitching up or down doesn't change which way it's turned.
+func NewMatrix4RotateEuler(yaw, pitch, roll float64) Matrix4 {
+
+	sy, cy := math.Sin(yaw), math.Cos(yaw)
+	sp, cp := math.Sin(pitch), math.Cos(pitch)
+	sr, cr := math.Sin(roll), math.Cos(roll)
+
+	mat := NewMatrix4()
+
+	mat[0][0] = cr*cy + sr*sp*sy
+	mat[0][1] = sr * cp
+	mat[0][2] = -cr*sy + sr*sp*cy
+
+	mat[1][0] = -sr*cy + cr*sp*sy
+	mat[1][1] = cr * cp
+	mat[1][2] = sr*sy + cr*sp*cy
+
+	mat[2][0] = cp * sy
+	mat[2][1] = -sp
+	mat[2][2] = cp * cy
+
+	return mat
+
+}
+
+// Euler returns the rotation of the Matrix4 as Euler angles in radians - yaw (rotation around the Y axis), pitch (rotation around the X axis),
+// and roll (rotation around the Z axis), using the same rotation order as NewMatrix4RotateEuler(). The Matrix4 should be a pure rotation matrix
+// (i.e. without scale). When pitched straight up or down, roll is returned as 0, as yaw and roll can't be told apart.
+func (matrix Matrix4) Euler() (yaw, pitch, roll float64) {
+
+	sp := -matrix[2][1]
+	if sp > 1 {
+		sp = 1
+	} else if sp < -1 {
+		sp = -1
+	}
+
+	pitch = math.Asin(sp)
+
+	if math.Abs(sp) < 0.9999999 {
+		yaw = math.Atan2(matrix[2][0], matrix[2][2])
+		roll = math.Atan2(matrix[0][1], matrix[1][1])
+	} else {
+		yaw = math.Atan2(-matrix[0][2], matrix[0][0])
+		roll = 0
+	}
+
+	return
+
+}
+
 // Right returns the right-facing rotational component of the Matrix4. For an identity matrix, this would be [1, 0, 0], or +X.
 func (matrix Matrix4) Right() vector.Vector {
 	return vector.Vector{
@@ -385,6 +439,26 @@ func (matrix Matrix4) Inverted() Matrix4 {
 
 }
 
+// Determinant returns the determinant of the Matrix4. A Matrix4 with a determinant of 0 can't be inverted (and so Matrix4.Inverted() would return an identity Matrix4).
+func (matrix Matrix4) Determinant() float64 {
+
+	m := matrix
+
+	// Cofactor expansion along the first row, using 2x2 determinants of the bottom two rows.
+	s0 := m[2][0]*m[3][1] - m[2][1]*m[3][0]
+	s1 := m[2][0]*m[3][2] - m[2][2]*m[3][0]
+	s2 := m[2][0]*m[3][3] - m[2][3]*m[3][0]
+	s3 := m[2][1]*m[3][2] - m[2][2]*m[3][1]
+	s4 := m[2][1]*m[3][3] - m[2][3]*m[3][1]
+	s5 := m[2][2]*m[3][3] - m[2][3]*m[3][2]
+
+	return m[0][0]*(m[1][1]*s5-m[1][2]*s4+m[1][3]*s3) -
+		m[0][1]*(m[1][0]*s5-m[1][2]*s2+m[1][3]*s1) +
+		m[0][2]*(m[1][0]*s4-m[1][1]*s2+m[1][3]*s0) -
+		m[0][3]*(m[1][0]*s3-m[1][1]*s1+m[1][2]*s0)
+
+}
+
 func (matrix *Matrix4) setIndex(index int, value float64) {
 	matrix[index/4][index%4] = value
 }
@@ -510,6 +584,64 @@ func (matrix Matrix4) MultVecW(vect vector.Vector) vector.Vector {
 
 }
 
+// FrustumPlanes extracts the six planes of the view frustum described by a Camera's view-projection Matrix4 (i.e. camera.ViewMatrix().Mult(camera.Projection())),
+// in the order of left, right, bottom, top, near, and far. Each plane is returned as a normalized 4D vector of [A, B, C, D], where [A, B, C] is the plane's normal
+// (facing inwards, into the frustum) and D is its distance from the origin, so that a point P is inside the plane if A*P.X + B*P.Y + C*P.Z + D >= 0.
+// This follows the renderer's clip space, where visible points satisfy -W/2 <= X, Y <= W/2 after multiplying by the Matrix4. For orthographic
+// projections, the near plane lies at the Camera's position, as their clip-space Z doesn't include the near distance.
+func (matrix Matrix4) FrustumPlanes() [6]vector.Vector {
+
+	column := func(index int) [4]float64 {
+		return [4]float64{matrix[0][index], matrix[1][index], matrix[2][index], matrix[3][index]}
+	}
+
+	cx, cy, cz, cw := column(0), column(1), column(2), column(3)
+
+	planes := [6]vector.Vector{}
+
+	combine := func(a, b [4]float64, sign float64) vector.Vector {
+		plane := vector.Vector{a[0]/2 + b[0]*sign, a[1]/2 + b[1]*sign, a[2]/2 + b[2]*sign, a[3]/2 + b[3]*sign}
+		length := math.Sqrt(plane[0]*plane[0] + plane[1]*plane[1] + plane[2]*plane[2])
+		if length > 0 {
+			plane = plane.Scale(1 / length)
+		}
+		return plane
+	}
+
+	planes[0] = combine(cw, cx, 1)  // Left
+	planes[1] = combine(cw, cx, -1) // Right
+	planes[2] = combine(cw, cy, 1)  // Bottom
+	planes[3] = combine(cw, cy, -1) // Top
+
+	// The renderer's clip-space Z isn't normalized to the near and far distances, so instead, we find the plane giving each point's distance in
+	// front of the camera and recover the near and far distances from the projection. For perspective projections, W is that distance scaled by
+	// 2*far*near / (far-near), and Z is that distance scaled by (far+near) / (far-near), minus 1. For orthographic projections, W is 1 and Z is
+	// the distance scaled by 2 / (far-near).
+	var depth [4]float64
+	var near, far float64
+
+	if scale := math.Sqrt(cw[0]*cw[0] + cw[1]*cw[1] + cw[2]*cw[2]); scale > 0 {
+		for i := range depth {
+			depth[i] = cw[i] / scale
+		}
+		zScale := cz[0]*depth[0] + cz[1]*depth[1] + cz[2]*depth[2]
+		near = scale / (zScale + 1)
+		far = scale / (zScale - 1)
+	} else {
+		scale = math.Sqrt(cz[0]*cz[0] + cz[1]*cz[1] + cz[2]*cz[2])
+		for i := range depth {
+			depth[i] = cz[i] / scale
+		}
+		far = 2 / scale
+	}
+
+	planes[4] = vector.Vector{depth[0], depth[1], depth[2], depth[3] - near}   // Near
+	planes[5] = vector.Vector{-depth[0], -depth[1], -depth[2], far - depth[3]} // Far
+
+	return planes
+
+}
+
 // Mult multiplies a Matrix4 by another provided Matrix4 - this effectively combines them.
 func (matrix Matrix4) Mult(other Matrix4) Matrix4 {
 
@@ -635,3 +767,69 @@ func NewLookAtMatrix(target, center, up vector.Vector) Matrix4 {
 		{0, 0, 0, 1},
 	}
 }
+
+// NewMatrix4LookRotation generates a new rotation Matrix4 that faces the forward direction provided (i.e. the resulting Matrix4's Forward() is
+// the forward vector), with its upward direction being as close to the provided up vector as possible ( usually +Y, or [0, 1, 0] ). If forward
+// and up are parallel, a different upward direction is chosen.
+func NewMatrix4LookRotation(forward, up vector.Vector) Matrix4 {
+
+	z := NewVector3FromVector(forward).Unit()
+	x := NewVector3FromVector(up).Cross(z)
+
+	if x.MagnitudeSquared() < 1e-12 {
+		x = NewVector3(0, 0, 1).Cross(z)
+		if x.MagnitudeSquared() < 1e-12 {
+			x = NewVector3(1, 0, 0).Cross(z)
+		}
+	}
+
+	x = x.Unit()
+	y := z.Cross(x)
+
+	return Matrix4{
+		{x.X, x.Y, x.Z, 0},
+		{y.X, y.Y, y.Z, 0},
+		{z.X, z.Y, z.Z, 0},
+		{0, 0, 0, 1},
+	}
+
+}
+
+// RotateTowards returns a new rotation Matrix4 that rotates from the current rotation Matrix4 towards the target rotation Matrix4 by at most
+// maxRadians, using spherical interpolation. If the rotations are closer than maxRadians apart, the target rotation is returned. This is useful
+// for smoothly turning AI or cameras at a fixed angular speed. Both Matrix4s should be pure rotation matrices (i.e. not scaled or translated).
+func RotateTowards(current, target Matrix4, maxRadians float64) Matrix4 {
+
+	start := NewQuaternionFromMatrix4(current).Normalized()
+	end := NewQuaternionFromMatrix4(target).Normalized()
+
+	dot := start.Dot(end)
+	if dot < 0 {
+		end = end.Negated()
+		dot = -dot
+	}
+
+	angle := 2 * math.Acos(math.Min(dot, 1))
+
+	if angle <= math.Max(maxRadians, 0) || angle < 1e-9 {
+		return target.Clone()
+	}
+
+	if maxRadians <= 0 {
+		return current.Clone()
+	}
+
+	half := angle / 2
+	sinHalf := math.Sin(half)
+	t := maxRadians / angle
+	a := math.Sin((1-t)*half) / sinHalf
+	b := math.Sin(t*half) / sinHalf
+
+	return NewMatrix4RotateFromQuaternion(NewQuaternion(
+		start.X*a+end.X*b,
+		start.Y*a+end.Y*b,
+		start.Z*a+end.Z*b,
+		start.W*a+end.W*b,
+	))
+
+}
@@ -5,7 +5,6 @@ import (
 	"log"
 	"math"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/kvartborg/vector"
 )
 
@@ -54,6 +53,92 @@ func (dim Dimensions) Clone() Dimensions {
 	}
 }
 
+// Union returns a new Dimensions set that encloses both the calling Dimensions and the other Dimensions provided.
+func (dim Dimensions) Union(other Dimensions) Dimensions {
+	return Dimensions{
+		{math.Min(dim[0][0], other[0][0]), math.Min(dim[0][1], other[0][1]), math.Min(dim[0][2], other[0][2])},
+		{math.Max(dim[1][0], other[1][0]), math.Max(dim[1][1], other[1][1]), math.Max(dim[1][2], other[1][2])},
+	}
+}
+
+// Intersection returns a new Dimensions set covering the overlapping volume of the calling Dimensions and the other Dimensions provided,
+// and whether they overlap at all. If they don't, the returned Dimensions is nil.
+func (dim Dimensions) Intersection(other Dimensions) (Dimensions, bool) {
+
+	result := Dimensions{
+		{math.Max(dim[0][0], other[0][0]), math.Max(dim[0][1], other[0][1]), math.Max(dim[0][2], other[0][2])},
+		{math.Min(dim[1][0], other[1][0]), math.Min(dim[1][1], other[1][1]), math.Min(dim[1][2], other[1][2])},
+	}
+
+	for axis := 0; axis < 3; axis++ {
+		if result[0][axis] > result[1][axis] {
+			return nil, false
+		}
+	}
+
+	return result, true
+
+}
+
+// Intersects returns if the calling Dimensions and the other Dimensions provided overlap.
+func (dim Dimensions) Intersects(other Dimensions) bool {
+	for axis := 0; axis < 3; axis++ {
+		if dim[0][axis] > other[1][axis] || dim[1][axis] < other[0][axis] {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains returns if the point provided lies within the Dimensions (inclusive).
+func (dim Dimensions) Contains(point vector.Vector) bool {
+	for axis := 0; axis < 3; axis++ {
+		if point[axis] < dim[0][axis] || point[axis] > dim[1][axis] {
+			return false
+		}
+	}
+	return true
+}
+
+// Expand returns a new Dimensions set grown by the margin provided on all sides. A negative margin shrinks the Dimensions.
+func (dim Dimensions) Expand(margin float64) Dimensions {
+	return Dimensions{
+		{dim[0][0] - margin, dim[0][1] - margin, dim[0][2] - margin},
+		{dim[1][0] + margin, dim[1][1] + margin, dim[1][2] + margin},
+	}
+}
+
+// Transform returns a new axis-aligned Dimensions set enclosing the calling Dimensions after being transformed by the Matrix4 provided
+// (i.e. all eight corners are transformed, and the result is the bounds of those corners). Note that rotating Dimensions this way can
+// grow them beyond the tightest bounds of the original geometry.
+func (dim Dimensions) Transform(matrix Matrix4) Dimensions {
+
+	var result Dimensions
+
+	for i := 0; i < 8; i++ {
+
+		corner := matrix.MultVec(vector.Vector{
+			dim[i&1][0],
+			dim[(i>>1)&1][1],
+			dim[(i>>2)&1][2],
+		})
+
+		if result == nil {
+			result = Dimensions{corner.Clone(), corner.Clone()}
+			continue
+		}
+
+		for axis := 0; axis < 3; axis++ {
+			result[0][axis] = math.Min(result[0][axis], corner[axis])
+			result[1][axis] = math.Max(result[1][axis], corner[axis])
+		}
+
+	}
+
+	return result
+
+}
+
 // Mesh represents a mesh that can be represented visually in different locations via Models. By default, a new Mesh has no MeshParts (so you would need to add one
 // manually if you want to construct a Mesh via code).
 type Mesh struct {
@@ -67,11 +152,16 @@ type Mesh struct {
 	// Each vertex property (position, normal, UV, colors, weights, bones, etc) is stored
 	// here and indexed in order of triangle ID * 3 + vertex (so the first triangle, 0, has
 	// the vertices 0, 1, and 2, while the 10th triangle would have the vertices 30, 31, and 32).
-	vertexTransforms         []vector.Vector
+	// Positions, normals, and UVs are stored in flat, parallel float64 arrays (3, 3, and 2 values per vertex, respectively).
+	// VertexPositions, VertexNormals, and VertexUVs are views into these arrays, so they can be altered in place, but their
+	// elements shouldn't be replaced. VertexInfo (along with MeshPart.AddTriangles() and Mesh.GetVertexInfo()) serves as the
+	// array-of-structs compatibility layer for constructing and reading back vertices.
+	vertexPositionData       []float64
+	vertexNormalData         []float64
+	vertexUVData             []float64
+	vertexTransforms         []Vector4
 	VertexPositions          []vector.Vector
 	VertexNormals            []vector.Vector
-	vertexSkinnedNormals     []vector.Vector
-	vertexSkinnedPositions   []vector.Vector
 	VertexUVs                []vector.Vector
 	VertexColors             [][]*Color
 	VertexActiveColorChannel []int
@@ -81,9 +171,17 @@ type Mesh struct {
 	VertexMax                int
 
 	VertexColorChannelNames map[string]int
-	Dimensions              Dimensions
-	triIndex                int
-	Tags                    *Tags
+
+	// MorphTargets are the Mesh's morph targets (also known as shape keys or blend shapes), which deform its vertices when applied using a
+	// Model's MorphWeights; this is useful for facial expressions or squash-and-stretch effects.
+	MorphTargets []*MorphTarget
+
+	// Dimensions holds the Mesh's bounds. Note that bounds are recalculated lazily, so after altering the Mesh's vertices, this can be out of
+	// date until Mesh.Bounds() or Mesh.UpdateBounds() is called (which Tetra3D does automatically when it needs the bounds).
+	Dimensions  Dimensions
+	boundsDirty bool
+	triIndex    int
+	Tags        *Tags
 }
 
 // NewMesh takes a name and a slice of *Vertex instances, and returns a new Mesh. If you provide *Vertex instances, the number must be divisible by 3,
@@ -98,11 +196,9 @@ func NewMesh(name string) *Mesh {
 		triIndex:                0,
 		Tags:                    NewTags(),
 
-		vertexTransforms:         []vector.Vector{},
+		vertexTransforms:         []Vector4{},
 		VertexPositions:          []vector.Vector{},
 		VertexNormals:            []vector.Vector{},
-		vertexSkinnedNormals:     []vector.Vector{},
-		vertexSkinnedPositions:   []vector.Vector{},
 		VertexUVs:                []vector.Vector{},
 		VertexColors:             [][]*Color{},
 		VertexActiveColorChannel: []int{},
@@ -122,9 +218,9 @@ func (mesh *Mesh) Clone() *Mesh {
 	newMesh.triIndex = mesh.triIndex
 
 	newMesh.allocateVertexBuffers(mesh.VertexMax)
-	copy(newMesh.VertexPositions, mesh.VertexPositions)
-	copy(newMesh.VertexNormals, mesh.VertexNormals)
-	copy(newMesh.VertexUVs, mesh.VertexUVs)
+	copy(newMesh.vertexPositionData, mesh.vertexPositionData)
+	copy(newMesh.vertexNormalData, mesh.vertexNormalData)
+	copy(newMesh.vertexUVData, mesh.vertexUVData)
 	copy(newMesh.VertexColors, mesh.VertexColors)
 	copy(newMesh.VertexActiveColorChannel, mesh.VertexActiveColorChannel)
 	copy(newMesh.VertexBones, mesh.VertexBones)
@@ -143,6 +239,10 @@ func (mesh *Mesh) Clone() *Mesh {
 		newMesh.VertexColorChannelNames[channelName] = index
 	}
 
+	for _, target := range mesh.MorphTargets {
+		newMesh.MorphTargets = append(newMesh.MorphTargets, target.Clone())
+	}
+
 	return newMesh
 }
 
@@ -150,17 +250,28 @@ func (mesh *Mesh) Clone() *Mesh {
 // we append to a slice and have its backing buffer automatically expanded (which is slower).
 func (mesh *Mesh) allocateVertexBuffers(size int) {
 
-	newVP := make([]vector.Vector, size)
-	copy(newVP, mesh.VertexPositions)
-	mesh.VertexPositions = newVP
+	newPositionData := make([]float64, size*3)
+	copy(newPositionData, mesh.vertexPositionData)
+	mesh.vertexPositionData = newPositionData
 
-	newVN := make([]vector.Vector, size)
-	copy(newVN, mesh.VertexNormals)
-	mesh.VertexNormals = newVN
+	newNormalData := make([]float64, size*3)
+	copy(newNormalData, mesh.vertexNormalData)
+	mesh.vertexNormalData = newNormalData
 
-	newVUVs := make([]vector.Vector, size)
-	copy(newVUVs, mesh.VertexUVs)
-	mesh.VertexUVs = newVUVs
+	newUVData := make([]float64, size*2)
+	copy(newUVData, mesh.vertexUVData)
+	mesh.vertexUVData = newUVData
+
+	// The vertex property views point into the old arrays, so they're recreated to point into the new ones.
+	mesh.VertexPositions = make([]vector.Vector, size)
+	mesh.VertexNormals = make([]vector.Vector, size)
+	mesh.VertexUVs = make([]vector.Vector, size)
+
+	for i := 0; i < size; i++ {
+		mesh.VertexPositions[i] = mesh.vertexPositionData[i*3 : i*3+3 : i*3+3]
+		mesh.VertexNormals[i] = mesh.vertexNormalData[i*3 : i*3+3 : i*3+3]
+		mesh.VertexUVs[i] = mesh.vertexUVData[i*2 : i*2+2 : i*2+2]
+	}
 
 	newVC := make([][]*Color, size)
 	copy(newVC, mesh.VertexColors)
@@ -178,18 +289,10 @@ func (mesh *Mesh) allocateVertexBuffers(size int) {
 	copy(newWeights, mesh.VertexWeights)
 	mesh.VertexWeights = newWeights
 
-	newTransforms := make([]vector.Vector, size)
+	newTransforms := make([]Vector4, size)
 	copy(newTransforms, mesh.vertexTransforms)
 	mesh.vertexTransforms = newTransforms
 
-	newNormals := make([]vector.Vector, size)
-	copy(newNormals, mesh.vertexSkinnedNormals)
-	mesh.vertexSkinnedNormals = newNormals
-
-	newPositions := make([]vector.Vector, size)
-	copy(newPositions, mesh.vertexSkinnedPositions)
-	mesh.vertexSkinnedPositions = newPositions
-
 	mesh.VertexMax = size
 
 }
@@ -216,40 +319,130 @@ func (mesh *Mesh) Library() *Library {
 	return mesh.library
 }
 
-// UpdateBounds updates the mesh's dimensions; call this after manually changing vertex positions.
+// DirtyBounds marks the Mesh's bounds as needing to be recalculated; call this after manually changing vertex positions. Unlike UpdateBounds(),
+// this is cheap, as the bounds are only recalculated when they're next needed (i.e. by calling Mesh.Bounds()), so it can be called after
+// each of many edits.
+func (mesh *Mesh) DirtyBounds() {
+	mesh.boundsDirty = true
+}
+
+// Bounds returns the Mesh's dimensions, recalculating them first if they're dirty (i.e. the Mesh's vertices have been altered since the
+// bounds were last calculated).
+func (mesh *Mesh) Bounds() Dimensions {
+	if mesh.boundsDirty {
+		mesh.UpdateBounds()
+	}
+	return mesh.Dimensions
+}
+
+// UpdateBounds immediately updates the mesh's dimensions. Generally, it's better to call Mesh.DirtyBounds() after manually changing vertex positions,
+// as the bounds will then only be recalculated once when they're needed.
 func (mesh *Mesh) UpdateBounds() {
 
+	mesh.boundsDirty = false
+
 	mesh.Dimensions[1] = vector.Vector{-math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64}
 	mesh.Dimensions[0] = vector.Vector{math.MaxFloat64, math.MaxFloat64, math.MaxFloat64}
 
-	for _, position := range mesh.VertexPositions {
+	for i := 0; i < mesh.VertexCount*3; i += 3 {
+		mesh.expandBounds(mesh.vertexPositionData[i], mesh.vertexPositionData[i+1], mesh.vertexPositionData[i+2])
+	}
 
-		if mesh.Dimensions[0][0] > position[0] {
-			mesh.Dimensions[0][0] = position[0]
+	// Morph targets can move vertices outside of the Mesh's original shape, so the bounds include each vertex fully morphed by each target.
+	for _, target := range mesh.MorphTargets {
+		for i, offset := range target.PositionOffsets {
+			if offset != nil && i < mesh.VertexCount {
+				position := mesh.VertexPositions[i]
+				mesh.expandBounds(position[0]+offset[0], position[1]+offset[1], position[2]+offset[2])
+			}
 		}
+	}
 
-		if mesh.Dimensions[0][1] > position[1] {
-			mesh.Dimensions[0][1] = position[1]
-		}
+}
 
-		if mesh.Dimensions[0][2] > position[2] {
-			mesh.Dimensions[0][2] = position[2]
-		}
+// expandBounds expands the Mesh's dimensions to include the point provided.
+func (mesh *Mesh) expandBounds(x, y, z float64) {
 
-		if mesh.Dimensions[1][0] < position[0] {
-			mesh.Dimensions[1][0] = position[0]
-		}
+	if mesh.Dimensions[0][0] > x {
+		mesh.Dimensions[0][0] = x
+	}
 
-		if mesh.Dimensions[1][1] < position[1] {
-			mesh.Dimensions[1][1] = position[1]
-		}
+	if mesh.Dimensions[0][1] > y {
+		mesh.Dimensions[0][1] = y
+	}
 
-		if mesh.Dimensions[1][2] < position[2] {
-			mesh.Dimensions[1][2] = position[2]
-		}
+	if mesh.Dimensions[0][2] > z {
+		mesh.Dimensions[0][2] = z
+	}
+
+	if mesh.Dimensions[1][0] < x {
+		mesh.Dimensions[1][0] = x
+	}
+
+	if mesh.Dimensions[1][1] < y {
+		mesh.Dimensions[1][1] = y
+	}
+
+	if mesh.Dimensions[1][2] < z {
+		mesh.Dimensions[1][2] = z
+	}
+
+}
+
+// MorphTarget is a morph target (also known as a shape key or blend shape) of a Mesh, holding how far each of the Mesh's vertices moves
+// (and how much its normal changes) when the MorphTarget is fully applied. MorphTargets are applied to a Model's vertices by setting the
+// Model's MorphWeights.
+type MorphTarget struct {
+	Name string
+	// DefaultWeight is the weight Models of the Mesh start with for this MorphTarget. Defaults to 0.
+	DefaultWeight float64
+	// PositionOffsets and NormalOffsets are the offsets of each of the Mesh's vertices when the MorphTarget is fully applied, indexed in the same
+	// order as Mesh.VertexPositions. A nil offset (or a slice that's shorter than the Mesh's vertex count) indicates that a vertex isn't affected.
+	PositionOffsets []vector.Vector
+	NormalOffsets   []vector.Vector
+}
+
+// Clone returns a clone of the MorphTarget.
+func (target *MorphTarget) Clone() *MorphTarget {
+
+	newTarget := &MorphTarget{
+		Name:          target.Name,
+		DefaultWeight: target.DefaultWeight,
+	}
+
+	for _, offset := range target.PositionOffsets {
+		newTarget.PositionOffsets = append(newTarget.PositionOffsets, offset.Clone())
+	}
+
+	for _, offset := range target.NormalOffsets {
+		newTarget.NormalOffsets = append(newTarget.NormalOffsets, offset.Clone())
+	}
+
+	return newTarget
+
+}
 
+// AddMorphTarget adds a new, empty MorphTarget to the Mesh with the name provided and returns it. Note that Models created before adding the
+// MorphTarget won't have a weight for it until their MorphWeights are extended.
+func (mesh *Mesh) AddMorphTarget(name string) *MorphTarget {
+	target := &MorphTarget{
+		Name:            name,
+		PositionOffsets: make([]vector.Vector, mesh.VertexCount),
+		NormalOffsets:   make([]vector.Vector, mesh.VertexCount),
 	}
+	mesh.MorphTargets = append(mesh.MorphTargets, target)
+	return target
+}
 
+// MorphTargetIndex returns the index of the Mesh's MorphTarget with the name provided (which is also the index of its weight in a Model's
+// MorphWeights), or -1 if the Mesh has no MorphTarget by that name.
+func (mesh *Mesh) MorphTargetIndex(name string) int {
+	for i, target := range mesh.MorphTargets {
+		if target.Name == name {
+			return i
+		}
+	}
+	return -1
 }
 
 // GetVertexInfo returns a VertexInfo struct containing the vertex information for the vertex with the provided index.
@@ -257,14 +450,14 @@ func (mesh *Mesh) GetVertexInfo(vertexIndex int) VertexInfo {
 
 	v := VertexInfo{
 		ID:                 vertexIndex,
-		X:                  mesh.VertexPositions[vertexIndex][0],
-		Y:                  mesh.VertexPositions[vertexIndex][1],
-		Z:                  mesh.VertexPositions[vertexIndex][2],
-		U:                  mesh.VertexUVs[vertexIndex][0],
-		V:                  mesh.VertexUVs[vertexIndex][1],
-		NormalX:            mesh.VertexNormals[vertexIndex][0],
-		NormalY:            mesh.VertexNormals[vertexIndex][1],
-		NormalZ:            mesh.VertexNormals[vertexIndex][2],
+		X:                  mesh.vertexPositionData[vertexIndex*3],
+		Y:                  mesh.vertexPositionData[vertexIndex*3+1],
+		Z:                  mesh.vertexPositionData[vertexIndex*3+2],
+		U:                  mesh.vertexUVData[vertexIndex*2],
+		V:                  mesh.vertexUVData[vertexIndex*2+1],
+		NormalX:            mesh.vertexNormalData[vertexIndex*3],
+		NormalY:            mesh.vertexNormalData[vertexIndex*3+1],
+		NormalZ:            mesh.vertexNormalData[vertexIndex*3+2],
 		Colors:             mesh.VertexColors[vertexIndex],
 		ActiveColorChannel: mesh.VertexActiveColorChannel[vertexIndex],
 		Bones:              mesh.VertexBones[vertexIndex],
@@ -379,6 +572,8 @@ func (vs *VertexSelection) ApplyMatrix(matrix Matrix4) {
 
 	}
 
+	vs.Mesh.DirtyBounds()
+
 }
 
 // Move moves all vertices contained within the VertexSelection by the provided x, y, and z values.
@@ -392,6 +587,8 @@ func (vs *VertexSelection) Move(x, y, z float64) {
 
 	}
 
+	vs.Mesh.DirtyBounds()
+
 }
 
 // Move moves all vertices contained within the VertexSelection by the provided 3D vector.
@@ -405,6 +602,8 @@ func (vs *VertexSelection) MoveVec(vec vector.Vector) {
 
 	}
 
+	vs.Mesh.DirtyBounds()
+
 }
 
 // NewCube creates a new Cube Mesh and gives it a new material (suitably named "Cube").
@@ -540,6 +739,85 @@ type sortingTriangle struct {
 	rendered bool
 }
 
+// radixSortTriangles sorts the provided triangles by depth using a stable LSD radix sort over depth values quantized to 16 bits (or 8 bits, if
+// coarse is true, which only takes one pass), which is considerably faster than a comparison sort for the numbers of triangles a MeshPart can
+// have. buffer is used as scratch space, and must be at least as long as tris.
+func radixSortTriangles(tris, buffer []sortingTriangle, backToFront, coarse bool) {
+
+	if len(tris) < 2 {
+		return
+	}
+
+	min := tris[0].depth
+	max := tris[0].depth
+
+	for _, t := range tris {
+		if t.depth < min {
+			min = t.depth
+		}
+		if t.depth > max {
+			max = t.depth
+		}
+	}
+
+	// All triangles are at the same depth, so there's nothing to do.
+	if max <= min {
+		return
+	}
+
+	bits := uint32(16)
+	if coarse {
+		bits = 8
+	}
+
+	maxKey := uint32(1)<<bits - 1
+
+	scale := float32(maxKey) / (max - min)
+
+	key := func(depth float32) uint32 {
+		k := uint32((depth - min) * scale)
+		if k > maxKey {
+			k = maxKey
+		}
+		if backToFront {
+			k = maxKey - k
+		}
+		return k
+	}
+
+	src := tris
+	dst := buffer[:len(tris)]
+
+	// Passes of 8 bits each.
+	for shift := uint32(0); shift < bits; shift += 8 {
+
+		counts := [257]int{}
+
+		for _, t := range src {
+			counts[((key(t.depth)>>shift)&0xFF)+1]++
+		}
+
+		for i := 1; i < len(counts); i++ {
+			counts[i] += counts[i-1]
+		}
+
+		for _, t := range src {
+			b := (key(t.depth) >> shift) & 0xFF
+			dst[counts[b]] = t
+			counts[b]++
+		}
+
+		src, dst = dst, src
+
+	}
+
+	// With an odd number of passes, the sorted result ends up in the buffer rather than tris.
+	if &src[0] != &tris[0] {
+		copy(tris, src)
+	}
+
+}
+
 // A Triangle represents the smallest renderable object in Tetra3D. A triangle contains very little data, and is mainly used to help identify triads of vertices.
 type Triangle struct {
 	ID int // Unique identifier number (index) in the Mesh. You can use the ID to find a triangle's vertices
@@ -641,6 +919,7 @@ type MeshPart struct {
 	TriangleStart    int
 	TriangleEnd      int
 	sortingTriangles []sortingTriangle
+	sortingBuffer    []sortingTriangle // Scratch space for sorting triangles
 }
 
 // NewMeshPart creates a new MeshPart that renders using the specified Material.
@@ -666,6 +945,67 @@ func (part *MeshPart) Clone() *MeshPart {
 	return newMP
 }
 
+// SetMaterial sets the Material the MeshPart is rendered with. As Meshes are shared between Models, this changes the Material for all Models
+// using the MeshPart's Mesh; to change the Material for a single Model, use Model.SetMaterial() instead.
+func (part *MeshPart) SetMaterial(material *Material) {
+	part.Material = material
+}
+
+// SplitMeshParts splits any of the Mesh's MeshParts that have more triangles than can be rendered in a single draw call (MaxTrianglesPerMeshPart)
+// into multiple MeshParts sharing the same Material, so the Mesh can be rendered. The first MeshPart of each split keeps the original MeshPart's
+// place (and pointer), while the rest follow it. This is done automatically for Meshes loaded from GLTF or DAE files. SplitMeshParts returns
+// true if any MeshParts were split.
+func (mesh *Mesh) SplitMeshParts() bool {
+
+	split := false
+
+	parts := make([]*MeshPart, 0, len(mesh.MeshParts))
+
+	for _, part := range mesh.MeshParts {
+
+		start, end := part.TriangleStart, part.TriangleEnd
+
+		if start < 0 || end-start <= MaxTrianglesPerMeshPart {
+			parts = append(parts, part)
+			continue
+		}
+
+		split = true
+
+		for batchStart := start; batchStart < end; batchStart += MaxTrianglesPerMeshPart {
+
+			batchEnd := batchStart + MaxTrianglesPerMeshPart
+			if batchEnd > end {
+				batchEnd = end
+			}
+
+			newPart := part
+			if batchStart != start {
+				newPart = NewMeshPart(mesh, part.Material)
+			}
+
+			newPart.TriangleStart = batchStart
+			newPart.TriangleEnd = batchEnd
+			newPart.sortingTriangles = make([]sortingTriangle, 0, batchEnd-batchStart)
+			newPart.sortingBuffer = nil
+
+			for id := batchStart; id < batchEnd; id++ {
+				mesh.Triangles[id].MeshPart = newPart
+				newPart.sortingTriangles = append(newPart.sortingTriangles, sortingTriangle{ID: id})
+			}
+
+			parts = append(parts, newPart)
+
+		}
+
+	}
+
+	mesh.MeshParts = parts
+
+	return split
+
+}
+
 // func (part *MeshPart) allocateSortingBuffer(size int) {
 // 	part.sortingTriangles = make([]sortingTriangle, size)
 // }
@@ -673,6 +1013,22 @@ func (part *MeshPart) Clone() *MeshPart {
 // AddTriangles adds triangles to the MeshPart using the provided VertexInfo slice. Note that
 func (part *MeshPart) AddTriangles(verts ...VertexInfo) {
 
+	part.addTriangles(verts...)
+
+	if part.TriangleEnd-part.TriangleStart > MaxTrianglesPerMeshPart {
+		matName := "nil"
+		if part.Material != nil {
+			matName = part.Material.Name
+		}
+		log.Println("warning: mesh [" + part.Mesh.Name + "] has part with material named [" + matName + "], which has " + fmt.Sprintf("%d", part.TriangleCount()) + " triangles. This exceeds the renderable maximum of 21845 triangles total for one MeshPart; please break up the mesh into multiple MeshParts using materials, call Mesh.SplitMeshParts() after adding triangles, or split it up into multiple models. Otherwise, the game will crash if it renders over the maximum number of triangles.")
+	}
+
+}
+
+// addTriangles adds triangles to the MeshPart without warning if it exceeds the maximum renderable triangle count; this is used when
+// loading Meshes, which are split afterwards.
+func (part *MeshPart) addTriangles(verts ...VertexInfo) {
+
 	mesh := part.Mesh
 
 	if part.TriangleEnd > -1 && part.TriangleEnd < mesh.triIndex {
@@ -691,22 +1047,26 @@ func (part *MeshPart) AddTriangles(verts ...VertexInfo) {
 		part.Mesh.allocateVertexBuffers(part.Mesh.VertexMax + len(verts))
 	}
 
+	positions := mesh.vertexPositionData
+	normals := mesh.vertexNormalData
+	uvs := mesh.vertexUVData
+
 	for i := 0; i < len(verts); i += 3 {
 
 		for j := 0; j < 3; j++ {
 			vertInfo := verts[i+j]
 			index := (mesh.triIndex * 3) + j
-			mesh.VertexPositions[index] = vector.Vector{vertInfo.X, vertInfo.Y, vertInfo.Z}
-			mesh.VertexNormals[index] = vector.Vector{vertInfo.NormalX, vertInfo.NormalY, vertInfo.NormalZ}
-			mesh.VertexUVs[index] = vector.Vector{vertInfo.U, vertInfo.V}
+
+			positions[index*3], positions[index*3+1], positions[index*3+2] = vertInfo.X, vertInfo.Y, vertInfo.Z
+			normals[index*3], normals[index*3+1], normals[index*3+2] = vertInfo.NormalX, vertInfo.NormalY, vertInfo.NormalZ
+			uvs[index*2], uvs[index*2+1] = vertInfo.U, vertInfo.V
+
 			mesh.VertexColors[index] = vertInfo.Colors
 			mesh.VertexActiveColorChannel[index] = vertInfo.ActiveColorChannel
 			mesh.VertexBones[index] = vertInfo.Bones
 			mesh.VertexWeights[index] = vertInfo.Weights
 
-			mesh.vertexTransforms[index] = vector.Vector{0, 0, 0, 0}
-			mesh.vertexSkinnedNormals[index] = vector.Vector{0, 0, 0}
-			mesh.vertexSkinnedPositions[index] = vector.Vector{0, 0, 0}
+			mesh.vertexTransforms[index] = Vector4{}
 		}
 
 		newTri := NewTriangle(part, mesh.triIndex)
@@ -721,13 +1081,7 @@ func (part *MeshPart) AddTriangles(verts ...VertexInfo) {
 
 	}
 
-	if part.TriangleCount() >= ebiten.MaxIndicesNum/3 {
-		matName := "nil"
-		if part.Material != nil {
-			matName = part.Material.Name
-		}
-		log.Println("warning: mesh [" + part.Mesh.Name + "] has part with material named [" + matName + "], which has " + fmt.Sprintf("%d", part.TriangleCount()) + " triangles. This exceeds the renderable maximum of 21845 triangles total for one MeshPart; please break up the mesh into multiple MeshParts using materials, or split it up into multiple models. Otherwise, the game will crash if it renders over the maximum number of triangles.")
-	}
+	mesh.DirtyBounds()
 
 	part.TriangleEnd = mesh.triIndex
 
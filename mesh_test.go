@@ -0,0 +1,111 @@
+package tetra3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kvartborg/vector"
+)
+
+func dimensionsEqual(a, b Dimensions) bool {
+	return len(a) == len(b) && (a == nil || (vectorsEqual(a[0], b[0]) && vectorsEqual(a[1], b[1])))
+}
+
+func TestDimensions(t *testing.T) {
+
+	unit := Dimensions{{-1, -1, -1}, {1, 1, 1}}
+	offset := Dimensions{{0, 0, 0}, {3, 2, 4}}
+	apart := Dimensions{{5, 5, 5}, {6, 6, 6}}
+
+	sizes := []struct {
+		name  string
+		value float64
+		want  float64
+	}{
+		{"width", offset.Width(), 3},
+		{"height", offset.Height(), 2},
+		{"depth", offset.Depth(), 4},
+		{"max dimension", offset.MaxDimension(), 4},
+		{"max span", offset.MaxSpan(), math.Sqrt(29)},
+	}
+
+	for _, test := range sizes {
+		if math.Abs(test.value-test.want) > testEpsilon {
+			t.Errorf("%s = %f, want %f", test.name, test.value, test.want)
+		}
+	}
+
+	if center := offset.Center(); !vectorsEqual(center, vector.Vector{1.5, 1, 2}) {
+		t.Errorf("center = %v, want %v", center, vector.Vector{1.5, 1, 2})
+	}
+
+	intersection, _ := unit.Intersection(offset)
+	noIntersection, _ := unit.Intersection(apart)
+
+	dimensions := []struct {
+		name  string
+		value Dimensions
+		want  Dimensions
+	}{
+		{"union", unit.Union(apart), Dimensions{{-1, -1, -1}, {6, 6, 6}}},
+		{"intersection", intersection, Dimensions{{0, 0, 0}, {1, 1, 1}}},
+		{"no intersection", noIntersection, nil},
+		{"expand", unit.Expand(1), Dimensions{{-2, -2, -2}, {2, 2, 2}}},
+		{"translate", unit.Transform(NewMatrix4Translate(1, 2, 3)), Dimensions{{0, 1, 2}, {2, 3, 4}}},
+		{"rotate", offset.Transform(NewMatrix4Rotate(0, 1, 0, math.Pi/2)), Dimensions{{0, 0, -3}, {4, 2, 0}}},
+	}
+
+	for _, test := range dimensions {
+		if !dimensionsEqual(test.value, test.want) {
+			t.Errorf("%s = %v, want %v", test.name, test.value, test.want)
+		}
+	}
+
+	checks := []struct {
+		name  string
+		value bool
+		want  bool
+	}{
+		{"intersects", unit.Intersects(offset), true},
+		{"doesn't intersect", unit.Intersects(apart), false},
+		{"contains", unit.Contains(vector.Vector{1, 0, -1}), true},
+		{"doesn't contain", unit.Contains(vector.Vector{1.5, 0, 0}), false},
+	}
+
+	for _, test := range checks {
+		if test.value != test.want {
+			t.Errorf("%s = %t, want %t", test.name, test.value, test.want)
+		}
+	}
+
+}
+
+func TestMeshBounds(t *testing.T) {
+
+	mesh := NewCube()
+
+	if dim := mesh.Bounds(); !dimensionsEqual(dim, Dimensions{{-1, -1, -1}, {1, 1, 1}}) {
+		t.Errorf("cube bounds = %v", dim)
+	}
+
+	// Vertex positions are views into the Mesh's position array, so altering them in place affects the Mesh.
+	mesh.VertexPositions[0][1] = 5
+	mesh.DirtyBounds()
+
+	if info := mesh.GetVertexInfo(0); info.Y != 5 {
+		t.Errorf("vertex 0 Y = %f, want 5", info.Y)
+	}
+
+	if dim := mesh.Bounds(); !dimensionsEqual(dim, Dimensions{{-1, -1, -1}, {1, 5, 1}}) {
+		t.Errorf("altered cube bounds = %v", dim)
+	}
+
+	// Clones have their own vertex data.
+	clone := mesh.Clone()
+	clone.VertexPositions[0][1] = 0
+
+	if mesh.VertexPositions[0][1] != 5 {
+		t.Errorf("altering a clone's vertices altered the original")
+	}
+
+}
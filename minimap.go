@@ -0,0 +1,137 @@
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/kvartborg/vector"
+)
+
+// MinimapLayer indicates the flat Color that Models with a given tag are drawn with on a Minimap.
+type MinimapLayer struct {
+	Tag   string // The tag a Model must have to be drawn as part of this layer
+	Color *Color // The flat Color Models in this layer are drawn with
+}
+
+// Minimap renders a top-down view of a Scene into a texture using a secondary orthographic Camera, drawing each Model with a flat Color
+// according to its tags (rather than its material, lighting, or fog). This is useful for minimaps, radar displays, or overhead maps. To use it,
+// call Minimap.Render() from your game's Draw() function, draw Minimap.Texture() to the screen, and then use Minimap.WorldToMinimap() to
+// position markers (like the player or objectives) on it. To control how much of the world the Minimap shows, set its Camera's OrthoScale.
+type Minimap struct {
+	Camera           *Camera        // The orthographic Camera the Minimap renders with
+	Target           INode          // The Node the Minimap centers on; if nil, the Minimap centers on Center instead
+	Center           vector.Vector  // The point the Minimap centers on if Target is nil; if Target is set, this is an offset from the Target's world position
+	Altitude         float64        // How high above the center the Camera is placed; this should be above the highest point you want drawn. Defaults to 100.
+	RotateWithTarget bool           // If the Minimap rotates so that the Target's forward direction always points up; otherwise, -Z always points up. Defaults to false.
+	Layers           []MinimapLayer // The layers to draw Models with; a Model is drawn with the Color of the first layer whose tag it has
+	DefaultColor     *Color         // The Color Models that aren't part of any layer are drawn with; if nil, they aren't drawn. Defaults to gray.
+	BackgroundColor  *Color         // The Color the Minimap's texture is filled with before rendering; if nil, it's left transparent. Defaults to translucent black.
+
+	scene  *Scene
+	models []*Model
+}
+
+// NewMinimap returns a new Minimap that renders to a texture of the width and height provided.
+func NewMinimap(width, height int) *Minimap {
+
+	camera := NewCamera(width, height)
+	camera.SetOrthographic(50)
+	camera.Far = 200
+
+	minimap := &Minimap{
+		Camera:          camera,
+		Center:          vector.Vector{0, 0, 0},
+		Altitude:        100,
+		Layers:          []MinimapLayer{},
+		DefaultColor:    NewColor(0.5, 0.5, 0.5, 1),
+		BackgroundColor: NewColor(0, 0, 0, 0.5),
+		scene:           NewScene("Minimap"),
+	}
+
+	minimap.scene.World.LightingOn = false
+
+	return minimap
+
+}
+
+// AddLayer adds a layer to the Minimap, drawing Models with the tag provided using the given Color.
+func (minimap *Minimap) AddLayer(tag string, color *Color) {
+	minimap.Layers = append(minimap.Layers, MinimapLayer{Tag: tag, Color: color})
+}
+
+// CenterPosition returns the world position the Minimap is centered on.
+func (minimap *Minimap) CenterPosition() vector.Vector {
+	if minimap.Target != nil {
+		return minimap.Target.WorldPosition().Add(minimap.Center)
+	}
+	return minimap.Center.Clone()
+}
+
+// Render positions the Minimap's Camera over its center and renders the Models in the Scene provided to the Minimap's texture.
+func (minimap *Minimap) Render(scene *Scene) {
+
+	camera := minimap.Camera
+
+	minimap.updateCamera()
+
+	minimap.models = minimap.models[:0]
+
+	for _, node := range scene.Root.ChildrenRecursive() {
+		if model, ok := node.(*Model); ok && model.Mesh != nil && minimap.colorFor(model) != nil {
+			minimap.models = append(minimap.models, model)
+		}
+	}
+
+	camera.Clear()
+
+	if minimap.BackgroundColor != nil {
+		camera.resultColorTexture.Fill(minimap.BackgroundColor.ToRGBA64())
+	}
+
+	// The Minimap renders through its own unlit, fogless Scene that shares the original Scene's tree.
+	minimap.scene.Root = scene.Root
+
+	camera.flatColorFunc = minimap.colorFor
+	camera.Render(minimap.scene, minimap.models...)
+	camera.flatColorFunc = nil
+
+}
+
+// updateCamera places the Minimap's Camera above its center, looking straight down.
+func (minimap *Minimap) updateCamera() {
+
+	yaw := 0.0
+
+	if minimap.RotateWithTarget && minimap.Target != nil {
+		forward := minimap.Target.WorldRotation().Forward()
+		if forward[0] != 0 || forward[2] != 0 {
+			// With no yaw, -Z points up on the Minimap, so we turn the Camera to point the Target's forward direction up instead.
+			yaw = math.Atan2(-forward[0], -forward[2])
+		}
+	}
+
+	minimap.Camera.SetWorldRotation(NewMatrix4Rotate(1, 0, 0, -math.Pi/2).Mult(NewMatrix4Rotate(0, 1, 0, yaw)))
+	minimap.Camera.SetWorldPosition(minimap.CenterPosition().Add(vector.Vector{0, minimap.Altitude, 0}))
+
+}
+
+// colorFor returns the flat Color the Model provided should be drawn with, or nil if it shouldn't be drawn.
+func (minimap *Minimap) colorFor(model *Model) *Color {
+	for _, layer := range minimap.Layers {
+		if model.Tags().Has(layer.Tag) {
+			return layer.Color
+		}
+	}
+	return minimap.DefaultColor
+}
+
+// Texture returns the Minimap's texture, as rendered by the last call to Minimap.Render().
+func (minimap *Minimap) Texture() *ebiten.Image {
+	return minimap.Camera.ColorTexture()
+}
+
+// WorldToMinimap converts the world position provided to a position in pixels on the Minimap's texture, as of the last call to Minimap.Render().
+// Positions outside of the area the Minimap shows lie outside of the texture's bounds, so they can be clamped to its edges if desired.
+func (minimap *Minimap) WorldToMinimap(point vector.Vector) vector.Vector {
+	return minimap.Camera.WorldToScreen(point)
+}
@@ -3,7 +3,6 @@ package tetra3d
 import (
 	"errors"
 	"math"
-	"sort"
 	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -15,7 +14,7 @@ import (
 type Model struct {
 	*Node
 	Mesh              *Mesh
-	FrustumCulling    bool                                                 // Whether the Model is culled when it leaves the frustum.
+	frustumCulling    bool                                                 // Whether the Model is culled when it leaves the frustum.
 	Color             *Color                                               // The overall multiplicative color of the Model.
 	ColorBlendingFunc func(model *Model, meshPart *MeshPart) ebiten.ColorM // The blending function used to color the Model; by default, it basically modulates the model by the color.
 	BoundingSphere    *BoundingSphere
@@ -23,19 +22,83 @@ type Model struct {
 	DynamicBatchModels []*Model // Models that are dynamically merged into this one.
 	DynamicBatchOwner  *Model
 
-	Skinned        bool  // If the model is skinned and this is enabled, the model will tranform its vertices to match the skinning armature (Model.SkinRoot).
-	SkinRoot       INode // The root node of the armature skinning this Model.
-	skinMatrix     Matrix4
-	bones          [][]*Node // The bones (nodes) of the Model, assuming it has been skinned. A Mesh's bones slice will point to indices indicating bones in the Model.
-	skinVectorPool *VectorPool
+	Skinned    bool  // If the model is skinned and this is enabled, the model will tranform its vertices to match the skinning armature (Model.SkinRoot).
+	SkinRoot   INode // The root node of the armature skinning this Model.
+	skinMatrix Matrix4
+	bones      [][]*Node // The bones (nodes) of the Model, assuming it has been skinned. A Mesh's bones slice will point to indices indicating bones in the Model.
+
+	// Skinned vertex positions and normals are cached per Model and only recalculated when the pose (the bones' transforms) changes,
+	// so rendering a skinned Model with multiple cameras or passes doesn't skin it multiple times.
+	skinBones        []*Node // The unique bones influencing the Model, used to determine the pose version.
+	skinnedPositions []Vector3
+	skinnedNormals   []Vector3
+	skinPoseVersion  uint64
+	skinHasNormals   bool
+	skinCacheValid   bool
+
+	// Static indicates that the Model doesn't move (or rarely moves), like level geometry. Static Models cache their vertices' world-space
+	// positions and normals, so only the camera's view-projection matrix is applied to them when rendering, and lights don't need to
+	// transform anything to light them. The cache is automatically refreshed when the Model's transform changes, but that's relatively
+	// expensive, so Static should only be set on Models that don't move often. Note that MeshParts with billboarding Materials are
+	// transformed as usual, and that a Material's VertexTransformFunction receives world-space positions for Static Models.
+	Static bool
+
+	staticTransform  Matrix4
+	staticPositions  []Vector3
+	staticNormals    []Vector3
+	staticCacheValid bool
+
+	// CacheLighting indicates that the Model should cache the lighting results for its vertices, only recalculating them when the
+	// Model's transform (or pose, for skinned Models) or any of the lights lighting it change. This is on by default.
+	CacheLighting bool
+
+	// ReceivesLight indicates whether the Model is lit by the Scene's Lights. If it's false, the Model is drawn unlit, as though its
+	// Materials were shadeless. This is on by default.
+	ReceivesLight bool
+
+	// MaxLights is the maximum number of Lights that can light the Model at once; if more Lights are active, only the ones nearest to the Model
+	// are used. Ambient and directional Lights aren't positioned, so they're always considered nearest. Limiting the number of Lights lighting a
+	// Model is useful to bound the cost of lighting in Scenes with many Lights. If MaxLights is 0 (the default), there's no limit.
+	MaxLights int
+
+	// MorphWeights holds how much each of the Mesh's MorphTargets (shape keys) is applied to the Model, in the same order as Mesh.MorphTargets,
+	// with 0 being not applied at all and 1 being fully applied. MorphWeights can be set directly or animated by an AnimationPlayer, and the
+	// Model's vertices are only morphed again when they change. Morphing is applied before skinning.
+	MorphWeights []float64
+
+	morphedPositions   []vector.Vector
+	morphedNormals     []vector.Vector
+	morphApplied       []float64 // The MorphWeights the morphed vertices were calculated with
+	morphActive        bool      // If the morphed vertices are in use (i.e. any weight is non-zero)
+	morphVersion       uint64    // Incremented whenever the Model's morphed vertices change, to invalidate the caches that depend on them
+	skinMorphVersion   uint64
+	staticMorphVersion uint64
+	lightCacheMorph    uint64
+
+	// fixedSizeDistance, if above 0, scales the Model according to its distance from a perspective camera when rendering, so that it stays
+	// the size on screen it would be at that distance. This is used by TextLabels that don't scale with distance.
+	fixedSizeDistance float64
+
+	materialOverrides map[*MeshPart]*MaterialOverride // MaterialOverrides by MeshPart; the override under the nil key applies to all MeshParts
+	materials         map[*MeshPart]*Material         // Materials set for MeshParts for just this Model, replacing the MeshParts' own Materials
+
+	octreeCell  *octreeCell // The cell of a Scene's Octree the Model is in, if any
+	octreeIndex int         // The Model's index in its octree cell's models slice
+
+	lightCache           []float32 // Cached light results; 9 values (R, G, and B for each vertex) for each triangle.
+	lightCacheVersions   []uint32  // The lightCacheGeneration each triangle's cached light results were calculated in.
+	lightCacheGeneration uint32
+	lightCacheLights     []lightState
+	lightCacheTransform  Matrix4
+	lightCachePose       uint64
 }
 
 var defaultColorBlendingFunc = func(model *Model, meshPart *MeshPart) ebiten.ColorM {
 	colorM := ebiten.ColorM{}
 	colorM.Scale(model.Color.ToFloat64s())
 
-	if meshPart.Material != nil {
-		colorM.Scale(meshPart.Material.Color.ToFloat64s())
+	if color := model.materialColor(meshPart); color != nil {
+		colorM.Scale(color.ToFloat64s())
 	}
 
 	return colorM
@@ -47,20 +110,21 @@ func NewModel(mesh *Mesh, name string) *Model {
 	model := &Model{
 		Node:               NewNode(name),
 		Mesh:               mesh,
-		FrustumCulling:     true,
+		frustumCulling:     true,
 		Color:              NewColor(1, 1, 1, 1),
 		ColorBlendingFunc:  defaultColorBlendingFunc,
 		skinMatrix:         NewMatrix4(),
 		DynamicBatchModels: []*Model{},
-	}
-
-	if mesh != nil {
-		model.skinVectorPool = NewVectorPool(mesh.VertexCount * 2) // Both position and normal
+		CacheLighting:      true,
+		ReceivesLight:      true,
 	}
 
 	radius := 0.0
 	if mesh != nil {
-		radius = mesh.Dimensions.MaxSpan() / 2
+		radius = mesh.Bounds().MaxSpan() / 2
+		for _, target := range mesh.MorphTargets {
+			model.MorphWeights = append(model.MorphWeights, target.DefaultWeight)
+		}
 	}
 	model.BoundingSphere = NewBoundingSphere("bounding sphere", radius)
 
@@ -72,12 +136,27 @@ func NewModel(mesh *Mesh, name string) *Model {
 func (model *Model) Clone() INode {
 	newModel := NewModel(model.Mesh, model.name)
 	newModel.BoundingSphere = model.BoundingSphere.Clone().(*BoundingSphere)
-	newModel.FrustumCulling = model.FrustumCulling
+	newModel.frustumCulling = model.frustumCulling
 	newModel.visible = model.visible
 	newModel.Color = model.Color.Clone()
 	newModel.DynamicBatchModels = append(newModel.DynamicBatchModels, model.DynamicBatchModels...)
 	newModel.DynamicBatchOwner = model.DynamicBatchOwner
 
+	newModel.Static = model.Static
+	newModel.CacheLighting = model.CacheLighting
+	newModel.ReceivesLight = model.ReceivesLight
+	newModel.MaxLights = model.MaxLights
+	newModel.fixedSizeDistance = model.fixedSizeDistance
+	newModel.MorphWeights = append([]float64{}, model.MorphWeights...)
+
+	for meshPart, material := range model.materials {
+		newModel.SetMaterial(meshPart, material)
+	}
+
+	for meshPart, override := range model.materialOverrides {
+		newModel.SetMaterialOverride(meshPart, override.Clone())
+	}
+
 	newModel.Skinned = model.Skinned
 	newModel.SkinRoot = model.SkinRoot
 	for i := range model.bones {
@@ -113,13 +192,15 @@ func (model *Model) Transform() Matrix4 {
 
 		var center vector.Vector
 
+		bounds := model.Mesh.Bounds()
+
 		// We do this because if a model is skinned and we've parented the model to the armature, then the center is
 		// now from origin relative to the base of the armature on scene export.
 		if model.SkinRoot != nil && model.Skinned && model.parent == model.SkinRoot {
 			parent := model.parent.(*Node)
-			center = model.Mesh.Dimensions.Center().Sub(parent.originalLocalPosition)
+			center = bounds.Center().Sub(parent.originalLocalPosition)
 		} else {
-			center = model.Mesh.Dimensions.Center()
+			center = bounds.Center()
 		}
 
 		wp[0] += center[0]
@@ -128,7 +209,7 @@ func (model *Model) Transform() Matrix4 {
 
 		model.BoundingSphere.SetLocalPosition(wp)
 
-		dim := model.Mesh.Dimensions.Clone()
+		dim := bounds.Clone()
 		scale := model.WorldScale()
 		dim[0][0] *= scale[0]
 		dim[0][1] *= scale[1]
@@ -146,6 +227,68 @@ func (model *Model) Transform() Matrix4 {
 
 }
 
+// updateStaticCache updates the cached world-space vertex positions and normals for a Static Model if its transform has changed since the
+// cache was last updated.
+func (model *Model) updateStaticCache() {
+
+	transform := model.Transform()
+
+	vertCount := len(model.Mesh.VertexPositions)
+
+	if model.staticCacheValid && transform == model.staticTransform && model.staticMorphVersion == model.morphVersion && len(model.staticPositions) == vertCount {
+		return
+	}
+
+	if len(model.staticPositions) != vertCount {
+		model.staticPositions = make([]Vector3, vertCount)
+		model.staticNormals = make([]Vector3, vertCount)
+	}
+
+	normalMatrix := transform.Inverted().Transposed()
+
+	positions, normals := model.vertexPositions(), model.vertexNormals()
+
+	for i := 0; i < model.Mesh.VertexCount; i++ {
+
+		model.staticPositions[i] = transform.MultVec3(NewVector3FromVector(positions[i]))
+
+		n := NewVector3FromVector(normals[i])
+		model.staticNormals[i] = Vector3{
+			normalMatrix[0][0]*n.X + normalMatrix[1][0]*n.Y + normalMatrix[2][0]*n.Z,
+			normalMatrix[0][1]*n.X + normalMatrix[1][1]*n.Y + normalMatrix[2][1]*n.Z,
+			normalMatrix[0][2]*n.X + normalMatrix[1][2]*n.Y + normalMatrix[2][2]*n.Z,
+		}.Unit()
+
+	}
+
+	model.staticTransform = transform
+	model.staticMorphVersion = model.morphVersion
+	model.staticCacheValid = true
+
+}
+
+// InvalidateStaticCache forces a Static Model to recalculate its cached world-space vertex positions and normals the next time it's rendered.
+// This is only necessary if the Model's Mesh has been altered (as changes to the Model's transform are automatically detected).
+func (model *Model) InvalidateStaticCache() {
+	model.staticCacheValid = false
+}
+
+// worldSpaceVertices returns the world-space vertex positions and normals for the Model if they're available (i.e. the Model is skinned
+// or static). If they aren't available, nil slices are returned and the Mesh's local vertex positions and normals should be used.
+func (model *Model) worldSpaceVertices() (positions, normals []Vector3) {
+
+	if model.Skinned {
+		return model.skinnedPositions, model.skinnedNormals
+	}
+
+	if model.Static && model.staticCacheValid {
+		return model.staticPositions, model.staticNormals
+	}
+
+	return nil, nil
+
+}
+
 func (model *Model) modelAlreadyDynamicallyBatched(batchedModel *Model) bool {
 	for _, m := range model.DynamicBatchModels {
 		if m == batchedModel {
@@ -170,7 +313,8 @@ func (model *Model) DynamicBatchAdd(batchedModels ...*Model) error {
 
 		triCount := model.DynamicBatchTriangleCount()
 
-		if triCount+len(other.Mesh.Triangles) > maxTriangleCount {
+		if triCount+len(other.Mesh.Triangles) > MaxTrianglesPerMeshPart {
+			model.dirtySubtreeBounds()
 			return errors.New("too many triangles in dynamic merge")
 		}
 
@@ -179,7 +323,7 @@ func (model *Model) DynamicBatchAdd(batchedModels ...*Model) error {
 		// 	var targetPart *MeshPart
 
 		// 	for _, mp := range model.Mesh.MeshParts {
-		// 		if mp.Material == otherPart.Material && mp.TriangleCount()+otherPart.TriangleCount() < maxTriangleCount {
+		// 		if mp.Material == otherPart.Material && mp.TriangleCount()+otherPart.TriangleCount() < MaxTrianglesPerMeshPart {
 		// 			targetPart = mp
 		// 			break
 		// 		}
@@ -199,6 +343,9 @@ func (model *Model) DynamicBatchAdd(batchedModels ...*Model) error {
 
 	}
 
+	// Owning dynamically batched Models changes whether the Model can be culled.
+	model.dirtySubtreeBounds()
+
 	return nil
 
 }
@@ -215,6 +362,20 @@ func (model *Model) DynamicBatchRemove(batched ...*Model) {
 			}
 		}
 	}
+	model.dirtySubtreeBounds()
+}
+
+// FrustumCulling returns whether the Model is culled when it leaves the frustum. Defaults to true.
+func (model *Model) FrustumCulling() bool {
+	return model.frustumCulling
+}
+
+// SetFrustumCulling sets whether the Model is culled when it leaves the frustum.
+func (model *Model) SetFrustumCulling(on bool) {
+	if model.frustumCulling != on {
+		model.frustumCulling = on
+		model.dirtySubtreeBounds()
+	}
 }
 
 // DynamicBatchTriangleCount returns the total number of triangles of Models in the calling Model's dynamic batch.
@@ -274,14 +435,14 @@ func (model *Model) Merge(models ...*Model) {
 			var targetPart *MeshPart
 
 			for _, mp := range model.Mesh.MeshParts {
-				if mp.Material == otherPart.Material && mp.TriangleCount()+otherPart.TriangleCount() < maxTriangleCount {
+				if mp.Material == other.Material(otherPart) && mp.TriangleCount()+otherPart.TriangleCount() < MaxTrianglesPerMeshPart {
 					targetPart = mp
 					break
 				}
 			}
 
 			if targetPart == nil {
-				targetPart = model.Mesh.AddMeshPart(otherPart.Material)
+				targetPart = model.Mesh.AddMeshPart(other.Material(otherPart))
 			}
 
 			verts := []VertexInfo{}
@@ -304,12 +465,9 @@ func (model *Model) Merge(models ...*Model) {
 
 	}
 
-	model.Mesh.UpdateBounds()
-
-	model.BoundingSphere.SetLocalPosition(model.Mesh.Dimensions.Center())
-	model.BoundingSphere.Radius = model.Mesh.Dimensions.MaxSpan() / 2
-
-	model.skinVectorPool = NewVectorPool(len(model.Mesh.VertexPositions))
+	// The Mesh's bounds have been dirtied by adding triangles; by dirtying the Model's transform, its BoundingSphere will be updated
+	// to match the new bounds when it's next needed, rather than immediately.
+	model.dirtyTransform()
 
 }
 
@@ -336,6 +494,8 @@ func (model *Model) ReassignBones(armatureRoot INode) {
 	}
 
 	model.SkinRoot = armatureRoot
+	model.skinBones = nil
+	model.skinCacheValid = false
 
 	for vertexIndex := range model.bones {
 
@@ -347,12 +507,12 @@ func (model *Model) ReassignBones(armatureRoot INode) {
 
 }
 
-func (model *Model) skinVertex(vertID int, transformNormal bool) (vector.Vector, vector.Vector) {
+func (model *Model) skinVertex(vertID int, transformNormal bool) (Vector3, Vector3) {
 
 	// Avoid reallocating a new matrix for every vertex; that's wasteful
 	model.skinMatrix.Clear()
 
-	var normal vector.Vector
+	var normal Vector3
 
 	for boneIndex, bone := range model.bones[vertID] {
 
@@ -375,7 +535,7 @@ func (model *Model) skinVertex(vertID int, transformNormal bool) (vector.Vector,
 
 	}
 
-	vertOut := model.skinVectorPool.MultVecW(model.skinMatrix, model.Mesh.VertexPositions[vertID])
+	vertOut := model.skinMatrix.MultVec3(NewVector3FromVector(model.vertexPositions()[vertID]))
 
 	if transformNormal {
 		model.skinMatrix[3][0] = 0
@@ -383,35 +543,356 @@ func (model *Model) skinVertex(vertID int, transformNormal bool) (vector.Vector,
 		model.skinMatrix[3][2] = 0
 		model.skinMatrix[3][3] = 1
 
-		normal = model.skinVectorPool.MultVecW(model.skinMatrix, model.Mesh.VertexNormals[vertID])
+		normal = model.skinMatrix.MultVec3(NewVector3FromVector(model.vertexNormals()[vertID]))
 	}
 
 	return vertOut, normal
 
 }
 
+// updateLightCache checks the provided lights and the Model's transform (or pose) against the state they were in when the Model's lighting
+// results were cached, invalidating the cache if anything's changed.
+func (model *Model) updateLightCache(lights []Light) {
+
+	triCount := len(model.Mesh.Triangles)
+
+	if len(model.lightCacheVersions) != triCount {
+		model.lightCache = make([]float32, triCount*9)
+		model.lightCacheVersions = make([]uint32, triCount)
+		model.lightCacheGeneration++
+	}
+
+	changed := len(lights) != len(model.lightCacheLights)
+
+	if !changed {
+		for i, light := range lights {
+			if light.state() != model.lightCacheLights[i] {
+				changed = true
+				break
+			}
+		}
+	}
+
+	if model.Skinned {
+		if pose := model.poseVersion(); pose != model.lightCachePose {
+			model.lightCachePose = pose
+			changed = true
+		}
+	} else if transform := model.Transform(); transform != model.lightCacheTransform {
+		model.lightCacheTransform = transform
+		changed = true
+	}
+
+	if model.morphVersion != model.lightCacheMorph {
+		model.lightCacheMorph = model.morphVersion
+		changed = true
+	}
+
+	if changed {
+
+		model.lightCacheLights = model.lightCacheLights[:0]
+		for _, light := range lights {
+			model.lightCacheLights = append(model.lightCacheLights, light.state())
+		}
+
+		model.lightCacheGeneration++
+
+	}
+
+}
+
+// cachedLight returns the cached light results for the given triangle, and whether they're valid.
+func (model *Model) cachedLight(triIndex int) ([9]float32, bool) {
+
+	results := [9]float32{}
+
+	if model.lightCacheVersions[triIndex] != model.lightCacheGeneration {
+		return results, false
+	}
+
+	copy(results[:], model.lightCache[triIndex*9:triIndex*9+9])
+
+	return results, true
+
+}
+
+// setCachedLight stores the light results for the given triangle in the light cache.
+func (model *Model) setCachedLight(triIndex int, results [9]float32) {
+	copy(model.lightCache[triIndex*9:triIndex*9+9], results[:])
+	model.lightCacheVersions[triIndex] = model.lightCacheGeneration
+}
+
+// InvalidateLightCache forces the Model to recalculate its lighting the next time it's rendered. This is only necessary if the Model's Mesh
+// has been altered (as changes to the Model's transform or pose and the Scene's lights are automatically detected).
+func (model *Model) InvalidateLightCache() {
+	model.lightCacheGeneration++
+}
+
+// poseVersion returns the latest transform version of the bones influencing the Model; if it differs from the version the skinned
+// vertices were cached with, the pose has changed since.
+func (model *Model) poseVersion() uint64 {
+
+	if model.skinBones == nil {
+
+		model.skinBones = []*Node{}
+		unique := map[*Node]bool{}
+
+		for _, vertexBones := range model.bones {
+			for _, bone := range vertexBones {
+				if !unique[bone] {
+					unique[bone] = true
+					model.skinBones = append(model.skinBones, bone)
+				}
+			}
+		}
+
+	}
+
+	version := uint64(0)
+
+	for _, bone := range model.skinBones {
+		if bone.transformVersion > version {
+			version = bone.transformVersion
+		}
+	}
+
+	return version
+
+}
+
+// updateSkinCache skins all of the Model's vertices (and normals, if requested) if the pose has changed since they were last skinned.
+func (model *Model) updateSkinCache(transformNormals bool) {
+
+	vertCount := len(model.Mesh.VertexPositions)
+
+	version := model.poseVersion()
+
+	if model.skinCacheValid && version == model.skinPoseVersion && model.skinMorphVersion == model.morphVersion && (model.skinHasNormals || !transformNormals) && len(model.skinnedPositions) == vertCount {
+		return
+	}
+
+	if len(model.skinnedPositions) != vertCount {
+		model.skinnedPositions = make([]Vector3, vertCount)
+		model.skinnedNormals = make([]Vector3, vertCount)
+	}
+
+	for i := 0; i < model.Mesh.VertexCount; i++ {
+		model.skinnedPositions[i], model.skinnedNormals[i] = model.skinVertex(i, transformNormals)
+	}
+
+	// Skinning calls Transform() on the bones, which can't dirty them again, so the version is still accurate.
+	model.skinPoseVersion = version
+	model.skinMorphVersion = model.morphVersion
+	model.skinHasNormals = transformNormals
+	model.skinCacheValid = true
+
+}
+
+// InvalidateSkinCache forces a skinned Model to re-skin its vertices the next time it's rendered. This is only necessary if the Model's
+// Mesh has been altered (as changes to the Model's pose are automatically detected).
+func (model *Model) InvalidateSkinCache() {
+	model.skinCacheValid = false
+}
+
+// MorphWeight returns the weight of the Model's morph target with the name provided, or 0 if its Mesh has no morph target by that name.
+func (model *Model) MorphWeight(name string) float64 {
+	if model.Mesh == nil {
+		return 0
+	}
+	if index := model.Mesh.MorphTargetIndex(name); index >= 0 && index < len(model.MorphWeights) {
+		return model.MorphWeights[index]
+	}
+	return 0
+}
+
+// SetMorphWeight sets the weight of the Model's morph target with the name provided, returning true if its Mesh has a morph target by that name.
+func (model *Model) SetMorphWeight(name string, weight float64) bool {
+
+	if model.Mesh == nil {
+		return false
+	}
+
+	index := model.Mesh.MorphTargetIndex(name)
+
+	if index < 0 {
+		return false
+	}
+
+	for len(model.MorphWeights) <= index {
+		model.MorphWeights = append(model.MorphWeights, 0)
+	}
+
+	model.MorphWeights[index] = weight
+
+	return true
+
+}
+
+// updateMorph morphs the Model's vertices according to its MorphWeights if they've changed since the vertices were last morphed.
+func (model *Model) updateMorph() {
+
+	mesh := model.Mesh
+
+	weights := model.MorphWeights
+	if len(weights) > len(mesh.MorphTargets) {
+		weights = weights[:len(mesh.MorphTargets)]
+	}
+
+	active := false
+
+	for _, weight := range weights {
+		if weight != 0 {
+			active = true
+			break
+		}
+	}
+
+	if !active {
+		if model.morphActive {
+			model.morphActive = false
+			model.morphVersion++
+		}
+		return
+	}
+
+	if model.morphActive && len(model.morphedPositions) == len(mesh.VertexPositions) && len(model.morphApplied) == len(weights) {
+
+		changed := false
+
+		for i, weight := range weights {
+			if model.morphApplied[i] != weight {
+				changed = true
+				break
+			}
+		}
+
+		if !changed {
+			return
+		}
+
+	}
+
+	if len(model.morphedPositions) != len(mesh.VertexPositions) {
+
+		vertCount := len(mesh.VertexPositions)
+		backing := make([]float64, vertCount*6)
+		model.morphedPositions = make([]vector.Vector, vertCount)
+		model.morphedNormals = make([]vector.Vector, vertCount)
+
+		for i := 0; i < vertCount; i++ {
+			model.morphedPositions[i] = backing[i*6 : i*6+3 : i*6+3]
+			model.morphedNormals[i] = backing[i*6+3 : i*6+6 : i*6+6]
+		}
+
+	}
+
+	for i := 0; i < mesh.VertexCount; i++ {
+		copy(model.morphedPositions[i], mesh.VertexPositions[i])
+		copy(model.morphedNormals[i], mesh.VertexNormals[i])
+	}
+
+	for t, weight := range weights {
+
+		if weight == 0 {
+			continue
+		}
+
+		target := mesh.MorphTargets[t]
+
+		for i, offset := range target.PositionOffsets {
+			if offset != nil && i < mesh.VertexCount {
+				pos := model.morphedPositions[i]
+				pos[0] += offset[0] * weight
+				pos[1] += offset[1] * weight
+				pos[2] += offset[2] * weight
+			}
+		}
+
+		for i, offset := range target.NormalOffsets {
+			if offset != nil && i < mesh.VertexCount {
+				normal := model.morphedNormals[i]
+				normal[0] += offset[0] * weight
+				normal[1] += offset[1] * weight
+				normal[2] += offset[2] * weight
+			}
+		}
+
+	}
+
+	for i := 0; i < mesh.VertexCount; i++ {
+		normal := model.morphedNormals[i]
+		if length := math.Sqrt(normal[0]*normal[0] + normal[1]*normal[1] + normal[2]*normal[2]); length > 0 {
+			normal[0] /= length
+			normal[1] /= length
+			normal[2] /= length
+		}
+	}
+
+	model.morphApplied = append(model.morphApplied[:0], weights...)
+	model.morphActive = true
+	model.morphVersion++
+
+}
+
+// vertexPositions returns the Model's local vertex positions; these are its Mesh's vertex positions, morphed if the Model has any MorphWeights.
+func (model *Model) vertexPositions() []vector.Vector {
+	if model.morphActive {
+		return model.morphedPositions
+	}
+	return model.Mesh.VertexPositions
+}
+
+// vertexNormals returns the Model's local vertex normals; these are its Mesh's vertex normals, morphed if the Model has any MorphWeights.
+func (model *Model) vertexNormals() []vector.Vector {
+	if model.morphActive {
+		return model.morphedNormals
+	}
+	return model.Mesh.VertexNormals
+}
+
 // ProcessVertices processes the vertices a Model has in preparation for rendering, given a view-projection
 // matrix, a camera, and the MeshPart being rendered.
 func (model *Model) ProcessVertices(vpMatrix Matrix4, camera *Camera, meshPart *MeshPart, scene *Scene) {
+	model.processVertices(vpMatrix, camera, meshPart, scene, false)
+}
+
+// processVertices processes the Model's vertices for rendering; if needNormals is true, skinned normals are calculated even if lighting
+// is off, as they're also used to sample ReflectionProbes and DarknessVolumes.
+func (model *Model) processVertices(vpMatrix Matrix4, camera *Camera, meshPart *MeshPart, scene *Scene, needNormals bool) {
+
+	stageBegin(ProfileStageTransform)
+
+	model.updateMorph()
 
 	var transformFunc func(vertPos vector.Vector, index int) vector.Vector
 
-	if meshPart.Material != nil && meshPart.Material.VertexTransformFunction != nil {
-		transformFunc = meshPart.Material.VertexTransformFunction
+	// transformVec is a scratch vector that vertex positions are copied into before being passed to the VertexTransformFunction, so that
+	// the Mesh's positions aren't altered and we don't allocate a new vector for each vertex.
+	var transformVec vector.Vector
+
+	mat := model.Material(meshPart)
+
+	if mat != nil && mat.VertexTransformFunction != nil {
+		transformFunc = mat.VertexTransformFunction
+		transformVec = vector.Vector{0, 0, 0}
 	}
 
 	if model.Skinned {
 
-		lightingOn := false
+		skinNormals := needNormals
 		if scene != nil {
-			lightingOn = scene.LightingOn && (meshPart.Material == nil || !meshPart.Material.Shadeless)
+			skinNormals = skinNormals || (scene.world().LightingOn && (mat == nil || !mat.Shadeless))
 		}
 
-		model.skinVectorPool.Reset()
+		vp := newPipelineMatrix(vpMatrix)
 
 		t := time.Now()
 
 		// If we're skinning a model, it will automatically copy the armature's position, scale, and rotation by copying its bones
+		model.updateSkinCache(skinNormals)
+
+		camera.DebugInfo.animationTime += time.Since(t)
+
 		for i := 0; i < len(meshPart.sortingTriangles); i++ {
 
 			tri := meshPart.sortingTriangles[i]
@@ -420,20 +901,13 @@ func (model *Model) ProcessVertices(vpMatrix Matrix4, camera *Camera, meshPart *
 
 			for v := 0; v < 3; v++ {
 
-				vertPos, vertNormal := model.skinVertex(tri.ID*3+v, lightingOn)
+				vertPos := model.skinnedPositions[tri.ID*3+v]
 				if transformFunc != nil {
-					vertPos = transformFunc(vertPos, tri.ID*3+v)
-				}
-				if vertNormal != nil {
-					model.Mesh.vertexSkinnedNormals[tri.ID*3+v] = vertNormal
-					model.Mesh.vertexSkinnedPositions[tri.ID*3+v] = vertPos
+					transformVec[0], transformVec[1], transformVec[2] = vertPos.X, vertPos.Y, vertPos.Z
+					vertPos = NewVector3FromVector(transformFunc(transformVec, tri.ID*3+v))
 				}
-				transformed := model.Mesh.vertexTransforms[tri.ID*3+v]
-				x, y, z, w := fastMatrixMultVecW(vpMatrix, vertPos)
-				transformed[0] = x
-				transformed[1] = y
-				transformed[2] = z
-				transformed[3] = w
+				x, y, z, w := pipelineMultVec3W(&vp, vertPos)
+				model.Mesh.vertexTransforms[tri.ID*3+v] = Vector4{x, y, z, w}
 
 				if w < depth {
 					depth = w
@@ -444,11 +918,41 @@ func (model *Model) ProcessVertices(vpMatrix Matrix4, camera *Camera, meshPart *
 
 		}
 
-		camera.DebugInfo.animationTime += time.Since(t)
+	} else if model.Static && (mat == nil || mat.BillboardMode == BillboardModeNone) {
 
-	} else {
+		// Static Models have their world-space vertex positions cached, so we only need to apply the view-projection matrix.
+		model.updateStaticCache()
+
+		vp := newPipelineMatrix(vpMatrix)
+
+		for i := 0; i < len(meshPart.sortingTriangles); i++ {
+
+			tri := meshPart.sortingTriangles[i]
+			depth := math.MaxFloat64
+
+			for v := 0; v < 3; v++ {
+
+				vertPos := model.staticPositions[tri.ID*3+v]
+
+				if transformFunc != nil {
+					transformVec[0], transformVec[1], transformVec[2] = vertPos.X, vertPos.Y, vertPos.Z
+					vertPos = NewVector3FromVector(transformFunc(transformVec, tri.ID*3+v))
+				}
 
-		mat := meshPart.Material
+				x, y, z, w := pipelineMultVec3W(&vp, vertPos)
+				model.Mesh.vertexTransforms[tri.ID*3+v] = Vector4{x, y, z, w}
+
+				if w < depth {
+					depth = w
+				}
+
+			}
+
+			meshPart.sortingTriangles[i].depth = float32(depth)
+
+		}
+
+	} else {
 
 		var base Matrix4
 		if mat == nil || mat.BillboardMode == BillboardModeNone {
@@ -461,7 +965,14 @@ func (model *Model) ProcessVertices(vpMatrix Matrix4, camera *Camera, meshPart *
 			base = NewLookAtMatrix(camera.WorldPosition(), model.WorldPosition(), vector.Y).Mult(model.Transform())
 		}
 
-		mvp := fastMatrixMult(base, vpMatrix)
+		if model.fixedSizeDistance > 0 && camera != nil && camera.Perspective {
+			scale := camera.WorldPosition().Sub(model.WorldPosition()).Magnitude() / model.fixedSizeDistance
+			base = NewMatrix4Scale(scale, scale, scale).Mult(base)
+		}
+
+		mvp := newPipelineMatrix(fastMatrixMult(base, vpMatrix))
+
+		positions := model.vertexPositions()
 
 		for i := 0; i < len(meshPart.sortingTriangles); i++ {
 
@@ -469,18 +980,16 @@ func (model *Model) ProcessVertices(vpMatrix Matrix4, camera *Camera, meshPart *
 			depth := math.MaxFloat64
 
 			for i := 0; i < 3; i++ {
-				v0 := model.Mesh.VertexPositions[tri.ID*3+i]
+
+				v0 := positions[tri.ID*3+i]
 
 				if transformFunc != nil {
-					v0 = transformFunc(v0.Clone(), tri.ID*3+i)
+					copy(transformVec, v0)
+					v0 = transformFunc(transformVec, tri.ID*3+i)
 				}
 
-				t0 := model.Mesh.vertexTransforms[tri.ID*3+i]
-				x, y, z, w := fastMatrixMultVecW(mvp, v0)
-				t0[0] = x
-				t0[1] = y
-				t0[2] = z
-				t0[3] = w
+				x, y, z, w := pipelineMultVecW(&mvp, v0)
+				model.Mesh.vertexTransforms[tri.ID*3+i] = Vector4{x, y, z, w}
 
 				if w < depth {
 					depth = w
@@ -493,32 +1002,191 @@ func (model *Model) ProcessVertices(vpMatrix Matrix4, camera *Camera, meshPart *
 
 	}
 
+	stageEnd(ProfileStageTransform)
+
 	sortMode := TriangleSortModeBackToFront
 
-	if meshPart.Material != nil {
-		sortMode = meshPart.Material.TriangleSortMode
+	if mat != nil {
+		sortMode = mat.TriangleSortMode
+	}
+
+	// Opaque triangles don't need to be sorted if the depth buffer's active and the Camera has been told it's alright to skip it.
+	if camera != nil && !camera.SortOpaqueTriangles && camera.RenderDepth && !model.isTransparent(meshPart) {
+		sortMode = TriangleSortModeNone
 	}
 
-	// Preliminary tests indicate sort.SliceStable is faster than sort.Slice for our purposes
+	if sortMode == TriangleSortModeBackToFront || sortMode == TriangleSortModeFrontToBack {
+
+		if len(meshPart.sortingBuffer) < len(meshPart.sortingTriangles) {
+			meshPart.sortingBuffer = make([]sortingTriangle, len(meshPart.sortingTriangles))
+		}
+
+		stageBegin(ProfileStageSort)
+
+		sortStart := time.Now()
+
+		radixSortTriangles(meshPart.sortingTriangles, meshPart.sortingBuffer, sortMode == TriangleSortModeBackToFront, camera != nil && camera.CoarseSorting)
+
+		if camera != nil {
+			camera.RenderStats.SortTime += time.Since(sortStart)
+		}
+
+		stageEnd(ProfileStageSort)
 
-	if sortMode == TriangleSortModeBackToFront {
-		sort.SliceStable(meshPart.sortingTriangles, func(i, j int) bool {
-			return meshPart.sortingTriangles[i].depth > meshPart.sortingTriangles[j].depth
-		})
-	} else if sortMode == TriangleSortModeFrontToBack {
-		sort.SliceStable(meshPart.sortingTriangles, func(i, j int) bool {
-			return meshPart.sortingTriangles[i].depth < meshPart.sortingTriangles[j].depth
-		})
 	}
 
 }
 
 // isTransparent returns true if the provided MeshPart has a Material with TransparencyModeTransparent, or if it's
 // TransparencyModeAuto with the model or material alpha color being under 0.99. This is a helper function for sorting
-// MeshParts into either transparent or opaque buckets for rendering.
+// MeshParts into either transparent or opaque buckets for rendering. Any MaterialOverride the Model has for the MeshPart is taken into account.
 func (model *Model) isTransparent(meshPart *MeshPart) bool {
-	mat := meshPart.Material
-	return mat != nil && (mat.TransparencyMode == TransparencyModeTransparent || mat.CompositeMode != ebiten.CompositeModeSourceOver || (mat.TransparencyMode == TransparencyModeAuto && (mat.Color.A < 0.99 || model.Color.A < 0.99)))
+
+	if model.Material(meshPart) == nil && model.materialOverride(meshPart) == nil {
+		return false
+	}
+
+	mode := model.materialTransparencyMode(meshPart)
+	color := model.materialColor(meshPart)
+
+	return mode == TransparencyModeTransparent || model.materialCompositeMode(meshPart) != ebiten.CompositeModeSourceOver || (mode == TransparencyModeAuto && ((color != nil && color.A < 0.99) || model.Color.A < 0.99))
+
+}
+
+// Material returns the Material the MeshPart provided is rendered with on this Model; this is the Material set for the MeshPart with
+// Model.SetMaterial() if there is one, and the MeshPart's own Material otherwise.
+func (model *Model) Material(meshPart *MeshPart) *Material {
+	if mat, exists := model.materials[meshPart]; exists {
+		return mat
+	}
+	return meshPart.Material
+}
+
+// SetMaterial sets the Material the MeshPart provided is rendered with for just this Model, without altering the MeshPart (which is shared
+// between all Models using its Mesh). This is useful for swapping skins, damage states, or seasonal variants on individual Models without
+// rebuilding or cloning their Meshes. The Camera batches MeshParts by the Material they're rendered with, so Models sharing a swapped
+// Material can still be batched together. If the Material is nil, the Model goes back to rendering the MeshPart with its own Material.
+// To change the Material for all Models using the Mesh, use MeshPart.SetMaterial() instead.
+func (model *Model) SetMaterial(meshPart *MeshPart, material *Material) {
+
+	if material == nil {
+		delete(model.materials, meshPart)
+		return
+	}
+
+	if model.materials == nil {
+		model.materials = map[*MeshPart]*Material{}
+	}
+
+	model.materials[meshPart] = material
+
+}
+
+// SetMaterialByName sets the Material the MeshPart whose own Material has the name provided is rendered with for just this Model
+// (see Model.SetMaterial()). SetMaterialByName returns false if the Model's Mesh has no MeshPart with a Material of that name.
+func (model *Model) SetMaterialByName(partName string, material *Material) bool {
+
+	if model.Mesh == nil {
+		return false
+	}
+
+	for _, meshPart := range model.Mesh.MeshParts {
+		if meshPart.Material != nil && meshPart.Material.Name == partName {
+			model.SetMaterial(meshPart, material)
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// SetMaterialOverride sets a MaterialOverride for the MeshPart provided, overriding properties of its Material for just this Model. If the
+// MeshPart is nil, the MaterialOverride applies to all of the Model's MeshParts (though MaterialOverrides set for specific MeshParts take
+// precedence). If the MaterialOverride is nil, any existing MaterialOverride for the MeshPart is removed. Note that the Model's
+// default ColorBlendingFunc takes the override Color into account, but custom ColorBlendingFuncs need to handle it themselves.
+func (model *Model) SetMaterialOverride(meshPart *MeshPart, override *MaterialOverride) {
+
+	if override == nil {
+		delete(model.materialOverrides, meshPart)
+		return
+	}
+
+	if model.materialOverrides == nil {
+		model.materialOverrides = map[*MeshPart]*MaterialOverride{}
+	}
+
+	model.materialOverrides[meshPart] = override
+
+}
+
+// MaterialOverride returns the MaterialOverride set for the MeshPart provided (or for all MeshParts, if the MeshPart is nil), or nil if there isn't one.
+func (model *Model) MaterialOverride(meshPart *MeshPart) *MaterialOverride {
+	return model.materialOverrides[meshPart]
+}
+
+// ClearMaterialOverrides removes all MaterialOverrides from the Model.
+func (model *Model) ClearMaterialOverrides() {
+	model.materialOverrides = nil
+}
+
+// materialOverride returns the MaterialOverride that applies to the MeshPart provided, or nil if there isn't one.
+func (model *Model) materialOverride(meshPart *MeshPart) *MaterialOverride {
+
+	if len(model.materialOverrides) == 0 {
+		return nil
+	}
+
+	if override, exists := model.materialOverrides[meshPart]; exists {
+		return override
+	}
+
+	return model.materialOverrides[nil]
+
+}
+
+// materialColor returns the Color the MeshPart is rendered with, taking the Model's MaterialOverrides into account.
+func (model *Model) materialColor(meshPart *MeshPart) *Color {
+	if override := model.materialOverride(meshPart); override != nil && override.Color != nil {
+		return override.Color
+	}
+	if mat := model.Material(meshPart); mat != nil {
+		return mat.Color
+	}
+	return nil
+}
+
+// materialTexture returns the texture the MeshPart is rendered with, taking the Model's MaterialOverrides into account.
+func (model *Model) materialTexture(meshPart *MeshPart) *ebiten.Image {
+	if override := model.materialOverride(meshPart); override != nil && override.Texture != nil {
+		return override.Texture
+	}
+	if mat := model.Material(meshPart); mat != nil {
+		return mat.Texture
+	}
+	return nil
+}
+
+// materialTransparencyMode returns the transparency mode the MeshPart is rendered with, taking the Model's MaterialOverrides into account.
+func (model *Model) materialTransparencyMode(meshPart *MeshPart) int {
+	if override := model.materialOverride(meshPart); override != nil && override.TransparencyMode != TransparencyModeAuto {
+		return override.TransparencyMode
+	}
+	if mat := model.Material(meshPart); mat != nil {
+		return mat.TransparencyMode
+	}
+	return TransparencyModeOpaque
+}
+
+// materialCompositeMode returns the composite mode the MeshPart is rendered with, taking the Model's MaterialOverrides into account.
+func (model *Model) materialCompositeMode(meshPart *MeshPart) ebiten.CompositeMode {
+	if override := model.materialOverride(meshPart); override != nil && override.OverrideCompositeMode {
+		return override.CompositeMode
+	}
+	if mat := model.Material(meshPart); mat != nil {
+		return mat.CompositeMode
+	}
+	return ebiten.CompositeModeSourceOver
 }
 
 ////////
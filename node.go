@@ -1,8 +1,10 @@
 package tetra3d
 
 import (
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/kvartborg/vector"
 )
@@ -18,6 +20,13 @@ const (
 	NodeTypeCamera NodeType = "NodeCamera" // NodeTypeCamera represents specifically a Camera
 	NodeTypePath   NodeType = "NodePath"   // NodeTypePath represents specifically a Path
 
+	NodeTypeAudioEmitter NodeType = "NodeAudioEmitter" // NodeTypeAudioEmitter represents specifically an AudioEmitter
+	NodeTypeGridMap      NodeType = "NodeGridMap"      // NodeTypeGridMap represents specifically a GridMap
+	NodeTypeTextLabel    NodeType = "NodeTextLabel"    // NodeTypeTextLabel represents specifically a TextLabel
+
+	NodeTypeReflectionProbe NodeType = "NodeReflectionProbe" // NodeTypeReflectionProbe represents specifically a ReflectionProbe
+	NodeTypeDarknessVolume  NodeType = "NodeDarknessVolume"  // NodeTypeDarknessVolume represents specifically a DarknessVolume
+
 	NodeTypeBoundingObject    NodeType = "NodeBounding"          // NodeTypeBoundingObject represents any generic bounding object
 	NodeTypeBoundingAABB      NodeType = "NodeBoundingAABB"      // NodeTypeBoundingAABB represents specifically a BoundingAABB
 	NodeTypeBoundingCapsule   NodeType = "NodeBoundingCapsule"   // NodeTypeBoundingCapsule represents specifically a BoundingCapsule
@@ -101,6 +110,14 @@ type INode interface {
 	LocalRotation() Matrix4
 	// SetLocalRotation sets the object's local rotation Matrix4 (relative to any parent).
 	SetLocalRotation(rotation Matrix4)
+	// LocalRotationEuler returns the object's local rotation as Euler angles in radians (yaw, pitch, and roll).
+	LocalRotationEuler() (yaw, pitch, roll float64)
+	// SetLocalRotationEuler sets the object's local rotation (relative to any parent) using Euler angles in radians (yaw, pitch, and roll).
+	SetLocalRotationEuler(yaw, pitch, roll float64)
+	// LocalTransform returns a copy of the object's local Transform (its position, scale, and rotation relative to any parent).
+	LocalTransform() Transform
+	// SetLocalTransform sets the object's local position, scale, and rotation (relative to any parent) using the Transform provided.
+	SetLocalTransform(transform Transform)
 	LocalPosition() vector.Vector
 	// SetLocalPosition sets the object's local position (position relative to its parent). If this object has no parent, the position should be
 	// relative to world origin (0, 0, 0). position should be a 3D vector (i.e. X, Y, and Z components).
@@ -216,6 +233,16 @@ func (tags *Tags) Has(nodeTags ...string) bool {
 	return true
 }
 
+// Names returns the names of all of the tags in the Tags object, sorted alphabetically.
+func (tags *Tags) Names() []string {
+	names := make([]string, 0, len(tags.tags))
+	for name := range tags.tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Get returns the value associated with the specified tag (key).
 // Note that this does not sanity check to ensure the tag exists first.
 func (tags *Tags) Get(tagName string) interface{} {
@@ -277,9 +304,7 @@ func (tags *Tags) GetAsInt(tagName string) int {
 // into their structs to automatically easily implement Node.
 type Node struct {
 	name                  string
-	position              vector.Vector
-	scale                 vector.Vector
-	rotation              Matrix4
+	local                 Transform // The local position, scale, and rotation of the Node
 	originalLocalPosition vector.Vector
 	visible               bool
 	data                  interface{} // A place to store a pointer to something if you need it
@@ -294,16 +319,19 @@ type Node struct {
 	boneInfluence         Matrix4
 	library               *Library // The Library this Node was instantiated from (nil if it wasn't instantiated with a library at all)
 	scene                 *Scene
+	subtreeBounds         subtreeBounds
+	transformVersion      uint64 // Incremented (from a global counter) whenever the Node's transform is dirtied; used to tell if a skinned Model's pose has changed.
 }
 
+// transformVersionCounter is a global counter used to give each transform change a unique, increasing version number.
+var transformVersionCounter uint64
+
 // NewNode returns a new Node.
 func NewNode(name string) *Node {
 
 	nb := &Node{
 		name:             name,
-		position:         vector.Vector{0, 0, 0},
-		scale:            vector.Vector{1, 1, 1},
-		rotation:         NewMatrix4(),
+		local:            NewTransform(),
 		children:         []INode{},
 		visible:          true,
 		isTransformDirty: true,
@@ -311,6 +339,7 @@ func NewNode(name string) *Node {
 		// We set this just in case we call a transform property getter before setting it and caching anything
 		cachedTransform:       NewMatrix4(),
 		originalLocalPosition: vector.Vector{0, 0, 0},
+		subtreeBounds:         subtreeBounds{dirty: true, octreeDirty: true},
 	}
 
 	nb.animationPlayer = NewAnimationPlayer(nb)
@@ -349,9 +378,7 @@ func (node *Node) setLibrary(library *Library) {
 // Clone returns a new Node.
 func (node *Node) Clone() INode {
 	newNode := NewNode(node.name)
-	newNode.position = node.position.Clone()
-	newNode.scale = node.scale.Clone()
-	newNode.rotation = node.rotation.Clone()
+	newNode.local = node.local.Clone()
 	newNode.visible = node.visible
 	newNode.data = node.data
 	newNode.isTransformDirty = true
@@ -404,9 +431,7 @@ func (node *Node) Transform() Matrix4 {
 		return node.cachedTransform
 	}
 
-	transform := NewMatrix4Scale(node.scale[0], node.scale[1], node.scale[2])
-	transform = transform.Mult(node.rotation)
-	transform = transform.Mult(NewMatrix4Translate(node.position[0], node.position[1], node.position[2]))
+	transform := node.local.Compose()
 
 	if node.parent != nil {
 		transform = transform.Mult(node.parent.Transform())
@@ -451,6 +476,10 @@ func (node *Node) dirtyTransform() {
 
 	node.isTransformDirty = true
 
+	node.transformVersion = atomic.AddUint64(&transformVersionCounter, 1)
+
+	node.dirtySubtreeBounds()
+
 }
 
 // updateLocalTransform updates the local transform properties for a Node given a change in parenting. This is done so that, for example,
@@ -463,16 +492,16 @@ func (node *Node) dirtyTransform() {
 // 		parentTransform := newParent.Transform()
 // 		parentPos, parentScale, parentRot := parentTransform.Decompose()
 
-// 		diff := node.position.Sub(parentPos)
+// 		diff := node.local.Position.Sub(parentPos)
 // 		diff[0] /= parentScale[0]
 // 		diff[1] /= parentScale[1]
 // 		diff[2] /= parentScale[2]
-// 		node.position = parentRot.Transposed().MultVec(diff)
-// 		node.rotation = node.rotation.Mult(parentRot.Transposed())
+// 		node.local.Position = parentRot.Transposed().MultVec(diff)
+// 		node.local.Rotation = node.local.Rotation.Mult(parentRot.Transposed())
 
-// 		node.scale[0] /= parentScale[0]
-// 		node.scale[1] /= parentScale[1]
-// 		node.scale[2] /= parentScale[2]
+// 		node.local.Scale[0] /= parentScale[0]
+// 		node.local.Scale[1] /= parentScale[1]
+// 		node.local.Scale[2] /= parentScale[2]
 
 // 	} else {
 
@@ -481,16 +510,16 @@ func (node *Node) dirtyTransform() {
 // 		parentTransform := node.Parent().Transform()
 // 		parentPos, parentScale, parentRot := parentTransform.Decompose()
 
-// 		pr := parentRot.MultVec(node.position)
+// 		pr := parentRot.MultVec(node.local.Position)
 // 		pr[0] *= parentScale[0]
 // 		pr[1] *= parentScale[1]
 // 		pr[2] *= parentScale[2]
-// 		node.position = parentPos.Add(pr)
-// 		node.rotation = node.rotation.Mult(parentRot)
+// 		node.local.Position = parentPos.Add(pr)
+// 		node.local.Rotation = node.local.Rotation.Mult(parentRot)
 
-// 		node.scale[0] *= parentScale[0]
-// 		node.scale[1] *= parentScale[1]
-// 		node.scale[2] *= parentScale[2]
+// 		node.local.Scale[0] *= parentScale[0]
+// 		node.local.Scale[1] *= parentScale[1]
+// 		node.local.Scale[2] *= parentScale[2]
 
 // 	}
 
@@ -501,20 +530,20 @@ func (node *Node) dirtyTransform() {
 // LocalPosition returns a 3D Vector consisting of the object's local position (position relative to its parent). If this object has no parent, the position will be
 // relative to world origin (0, 0, 0).
 func (node *Node) LocalPosition() vector.Vector {
-	return node.position
+	return node.local.Position
 }
 
 // ResetLocalTransform resets the local transform properties (position, scale, and rotation) for the Node. This can be useful because
 // by default, when you parent one Node to another, the local transform properties (position, scale, and rotation) are altered to keep the
 // object in the same absolute location, even though the origin changes.
 func (node *Node) ResetLocalTransform() {
-	node.position[0] = 0
-	node.position[1] = 0
-	node.position[2] = 0
-	node.scale[0] = 1
-	node.scale[1] = 1
-	node.scale[2] = 1
-	node.rotation = NewMatrix4()
+	node.local.Position[0] = 0
+	node.local.Position[1] = 0
+	node.local.Position[2] = 0
+	node.local.Scale[0] = 1
+	node.local.Scale[1] = 1
+	node.local.Scale[2] = 1
+	node.local.Rotation = NewMatrix4()
 	node.dirtyTransform()
 }
 
@@ -527,9 +556,9 @@ func (node *Node) WorldPosition() vector.Vector {
 // SetLocalPosition sets the object's local position (position relative to its parent). If this object has no parent, the position should be
 // relative to world origin (0, 0, 0). position should be a 3D vector (i.e. X, Y, and Z components).
 func (node *Node) SetLocalPosition(position vector.Vector) {
-	node.position[0] = position[0]
-	node.position[1] = position[1]
-	node.position[2] = position[2]
+	node.local.Position[0] = position[0]
+	node.local.Position[1] = position[1]
+	node.local.Position[2] = position[2]
 	node.dirtyTransform()
 }
 
@@ -547,12 +576,12 @@ func (node *Node) SetWorldPosition(position vector.Vector) {
 		pr[1] /= parentScale[1]
 		pr[2] /= parentScale[2]
 
-		node.position = pr
+		node.local.Position = pr
 
 	} else {
-		node.position[0] = position[0]
-		node.position[1] = position[1]
-		node.position[2] = position[2]
+		node.local.Position[0] = position[0]
+		node.local.Position[1] = position[1]
+		node.local.Position[2] = position[2]
 	}
 
 	node.dirtyTransform()
@@ -561,15 +590,15 @@ func (node *Node) SetWorldPosition(position vector.Vector) {
 
 // LocalScale returns the object's local scale (scale relative to its parent). If this object has no parent, the scale will be absolute.
 func (node *Node) LocalScale() vector.Vector {
-	return node.scale
+	return node.local.Scale
 }
 
 // SetLocalScale sets the object's local scale (scale relative to its parent). If this object has no parent, the scale would be absolute.
 // scale should be a 3D vector (i.e. X, Y, and Z components).
 func (node *Node) SetLocalScale(scale vector.Vector) {
-	node.scale[0] = scale[0]
-	node.scale[1] = scale[1]
-	node.scale[2] = scale[2]
+	node.local.Scale[0] = scale[0]
+	node.local.Scale[1] = scale[1]
+	node.local.Scale[2] = scale[2]
 	node.dirtyTransform()
 }
 
@@ -587,16 +616,16 @@ func (node *Node) SetWorldScale(scale vector.Vector) {
 		parentTransform := node.parent.Transform()
 		_, parentScale, _ := parentTransform.Decompose()
 
-		node.scale = vector.Vector{
+		node.local.Scale = vector.Vector{
 			scale[0] / parentScale[0],
 			scale[1] / parentScale[1],
 			scale[2] / parentScale[2],
 		}
 
 	} else {
-		node.scale[0] = scale[0]
-		node.scale[1] = scale[1]
-		node.scale[2] = scale[2]
+		node.local.Scale[0] = scale[0]
+		node.local.Scale[1] = scale[1]
+		node.local.Scale[2] = scale[2]
 	}
 
 	node.dirtyTransform()
@@ -604,15 +633,39 @@ func (node *Node) SetWorldScale(scale vector.Vector) {
 
 // LocalRotation returns the object's local rotation Matrix4.
 func (node *Node) LocalRotation() Matrix4 {
-	return node.rotation
+	return node.local.Rotation
 }
 
 // SetLocalRotation sets the object's local rotation Matrix4 (relative to any parent).
 func (node *Node) SetLocalRotation(rotation Matrix4) {
-	node.rotation = rotation.Clone()
+	node.local.Rotation = rotation.Clone()
 	node.dirtyTransform()
 }
 
+// LocalTransform returns a copy of the object's local Transform (its position, scale, and rotation relative to any parent).
+func (node *Node) LocalTransform() Transform {
+	return node.local.Clone()
+}
+
+// SetLocalTransform sets the object's local position, scale, and rotation (relative to any parent) using the Transform provided.
+func (node *Node) SetLocalTransform(transform Transform) {
+	node.local = transform.Clone()
+	node.dirtyTransform()
+}
+
+// LocalRotationEuler returns the object's local rotation (relative to any parent) as Euler angles in radians - yaw (rotation around the Y axis),
+// pitch (rotation around the X axis), and roll (rotation around the Z axis). See NewMatrix4RotateEuler() for the rotation order.
+func (node *Node) LocalRotationEuler() (yaw, pitch, roll float64) {
+	return node.local.Rotation.Euler()
+}
+
+// SetLocalRotationEuler sets the object's local rotation (relative to any parent) using Euler angles in radians - yaw (rotation around the Y axis),
+// pitch (rotation around the X axis), and roll (rotation around the Z axis). The rotations are applied in the order of roll, then pitch, then yaw,
+// so for an FPS camera, you can simply set the yaw from horizontal mouse movement and the pitch from vertical mouse movement.
+func (node *Node) SetLocalRotationEuler(yaw, pitch, roll float64) {
+	node.SetLocalRotation(NewMatrix4RotateEuler(yaw, pitch, roll))
+}
+
 // WorldRotation returns an absolute rotation Matrix4 representing the object's rotation.
 func (node *Node) WorldRotation() Matrix4 {
 	_, _, rotation := node.Transform().Decompose()
@@ -626,10 +679,10 @@ func (node *Node) SetWorldRotation(rotation Matrix4) {
 
 		parentTransform := node.parent.Transform()
 		_, _, parentRot := parentTransform.Decompose()
-		node.rotation = parentRot.Transposed().Mult(rotation)
+		node.local.Rotation = parentRot.Transposed().Mult(rotation)
 
 	} else {
-		node.rotation = rotation.Clone()
+		node.local.Rotation = rotation.Clone()
 	}
 
 	node.dirtyTransform()
@@ -637,9 +690,9 @@ func (node *Node) SetWorldRotation(rotation Matrix4) {
 
 // Move moves a Node in local space by the x, y, and z values provided.
 func (node *Node) Move(x, y, z float64) {
-	node.position[0] += x
-	node.position[1] += y
-	node.position[2] += z
+	node.local.Position[0] += x
+	node.local.Position[1] += y
+	node.local.Position[2] += z
 	node.dirtyTransform()
 }
 
@@ -688,6 +741,7 @@ func (node *Node) Scene() *Scene {
 // correct, specific Node as parent (because I can't really think of a better way to do this rn). Basically, without this approach,
 // after parent.AddChildren(child), child.Parent() wouldn't be parent, but rather parent.Node, which is no good.
 func (node *Node) addChildren(parent INode, children ...INode) {
+	panicIfUpdating(node)
 	for _, child := range children {
 		// child.updateLocalTransform(parent)
 		if child.Parent() != nil {
@@ -696,6 +750,7 @@ func (node *Node) addChildren(parent INode, children ...INode) {
 		child.setParent(parent)
 		node.children = append(node.children, child)
 	}
+	node.dirtySubtreeBounds()
 }
 
 // AddChildren parents the provided children Nodes to the passed parent Node, inheriting its transformations and being under it in the scenegraph
@@ -707,6 +762,8 @@ func (node *Node) AddChildren(children ...INode) {
 // RemoveChildren removes the provided children from this object.
 func (node *Node) RemoveChildren(children ...INode) {
 
+	panicIfUpdating(node)
+
 	for _, child := range children {
 		for i, c := range node.children {
 			if c == child {
@@ -714,11 +771,14 @@ func (node *Node) RemoveChildren(children ...INode) {
 				child.setParent(nil)
 				node.children[i] = nil
 				node.children = append(node.children[:i], node.children[i+1:]...)
+				resetOctree(child)
 				break
 			}
 		}
 	}
 
+	node.dirtySubtreeBounds()
+
 }
 
 // Unparent unparents the Node from its parent, removing it from the scenegraph. Note that this needs to be overridden for objects that embed Node.
@@ -733,6 +793,139 @@ func (node *Node) Children() NodeFilter {
 	return append(make(NodeFilter, 0, len(node.children)), node.children...)
 }
 
+// nodeInternals is implemented by Node, and so by every node type that embeds one; it exposes the scene tree bookkeeping used
+// internally for hierarchical culling without adding it to the public INode interface.
+type nodeInternals interface {
+	// rawChildren returns the Node's internal children slice without copying it; it shouldn't be modified.
+	rawChildren() []INode
+	// getSubtreeBounds returns the Node's cached subtree bounds, used for hierarchical frustum culling.
+	getSubtreeBounds() *subtreeBounds
+	// dirtySubtreeBounds marks the Node's subtree bounds, as well as those of its parents, as needing to be recalculated.
+	dirtySubtreeBounds()
+}
+
+// rawChildren returns the provided Node's internal children slice without copying it.
+func rawChildren(node INode) []INode {
+	return node.(nodeInternals).rawChildren()
+}
+
+// getSubtreeBounds returns the provided Node's cached subtree bounds.
+func getSubtreeBounds(node INode) *subtreeBounds {
+	return node.(nodeInternals).getSubtreeBounds()
+}
+
+func (node *Node) rawChildren() []INode {
+	return node.children
+}
+
+func (node *Node) getSubtreeBounds() *subtreeBounds {
+	return &node.subtreeBounds
+}
+
+func (node *Node) dirtySubtreeBounds() {
+
+	// If the bounds are already dirty, the parents' bounds must be dirty as well.
+	if node.subtreeBounds.dirty && node.subtreeBounds.octreeDirty {
+		return
+	}
+
+	node.subtreeBounds.dirty = true
+	node.subtreeBounds.octreeDirty = true
+
+	if node.parent != nil {
+		node.parent.(nodeInternals).dirtySubtreeBounds()
+	}
+
+}
+
+// subtreeBounds is a bounding sphere encompassing all of the Models in a Node's subtree (including the Node itself). It's used to frustum
+// cull entire branches of the scene tree at once.
+type subtreeBounds struct {
+	center   Vector3
+	radius   float64
+	empty    bool // If there are no Models with Meshes in the subtree
+	unculled bool // If a Model in the subtree can't be culled (i.e. it has FrustumCulling off or it owns dynamically batched Models)
+	models   int  // The number of Models with Meshes in the subtree
+	dirty    bool
+
+	octreeDirty bool // If the subtree has changed since its Scene's Octree was last updated; this is tracked separately from dirty, as the two are updated at different times
+}
+
+// merge expands the bounds to encompass the sphere provided.
+func (bounds *subtreeBounds) merge(center Vector3, radius float64) {
+
+	if bounds.empty {
+		bounds.center = center
+		bounds.radius = radius
+		bounds.empty = false
+		return
+	}
+
+	diff := center.Sub(bounds.center)
+	dist := diff.Magnitude()
+
+	// The other sphere is already inside of the bounds
+	if dist+radius <= bounds.radius {
+		return
+	}
+
+	// The bounds are inside of the other sphere
+	if dist+bounds.radius <= radius {
+		bounds.center = center
+		bounds.radius = radius
+		return
+	}
+
+	newRadius := (dist + radius + bounds.radius) / 2
+	bounds.center = bounds.center.Add(diff.Scale((newRadius - bounds.radius) / dist))
+	bounds.radius = newRadius
+
+}
+
+// updateSubtreeBounds recalculates the subtree bounds of the provided Node if necessary, returning them.
+func updateSubtreeBounds(node INode) *subtreeBounds {
+
+	bounds := getSubtreeBounds(node)
+
+	if !bounds.dirty {
+		return bounds
+	}
+
+	bounds.empty = true
+	bounds.unculled = false
+	bounds.models = 0
+
+	if model, isModel := node.(*Model); isModel && model.Mesh != nil {
+		model.Transform()
+		if !model.frustumCulling || len(model.DynamicBatchModels) > 0 {
+			bounds.unculled = true
+		}
+		bounds.merge(NewVector3FromVector(model.BoundingSphere.WorldPosition()), model.BoundingSphere.WorldRadius())
+		bounds.models++
+	}
+
+	for _, child := range rawChildren(node) {
+
+		childBounds := updateSubtreeBounds(child)
+
+		if childBounds.unculled {
+			bounds.unculled = true
+		}
+
+		bounds.models += childBounds.models
+
+		if !childBounds.empty {
+			bounds.merge(childBounds.center, childBounds.radius)
+		}
+
+	}
+
+	bounds.dirty = false
+
+	return bounds
+
+}
+
 // ChildrenRecursive() returns the Node's recursive children (i.e. children, grandchildren, etc)
 // as a NodeFilter.
 func (node *Node) ChildrenRecursive() NodeFilter {
@@ -799,6 +992,16 @@ func (node *Node) HierarchyAsString() string {
 			prefix = "TRI"
 		} else if nodeType.Is(NodeTypePath) {
 			prefix = "CURVE"
+		} else if nodeType.Is(NodeTypeAudioEmitter) {
+			prefix = "AUDIO"
+		} else if nodeType.Is(NodeTypeGridMap) {
+			prefix = "GRID"
+		} else if nodeType.Is(NodeTypeTextLabel) {
+			prefix = "TEXT"
+		} else if nodeType.Is(NodeTypeReflectionProbe) {
+			prefix = "PROBE"
+		} else if nodeType.Is(NodeTypeDarknessVolume) {
+			prefix = "DARK"
 		} else {
 			prefix = "NODE"
 		}
@@ -0,0 +1,432 @@
+package noise
+
+// package noise contains functions to generate coherent noise (Perlin, simplex, and value noise in 1, 2, and 3 dimensions) for procedural
+// content like terrain generation, camera shake, or animating materials. All noise functions return values ranging (roughly) from -1 to 1.
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Noise is a seeded noise generator. Two Noise instances created with the same seed produce identical results.
+type Noise struct {
+	Seed int64
+	perm [512]int
+}
+
+// New returns a new Noise generator, using the seed provided to shuffle its internal permutation table.
+func New(seed int64) *Noise {
+
+	noise := &Noise{Seed: seed}
+
+	random := rand.New(rand.NewSource(seed))
+
+	for i := 0; i < 256; i++ {
+		noise.perm[i] = i
+	}
+
+	random.Shuffle(256, func(i, j int) { noise.perm[i], noise.perm[j] = noise.perm[j], noise.perm[i] })
+
+	for i := 0; i < 256; i++ {
+		noise.perm[i+256] = noise.perm[i]
+	}
+
+	return noise
+
+}
+
+func (noise *Noise) hash(i int) int {
+	return noise.perm[i&255]
+}
+
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+func floor(x float64) int {
+	return int(math.Floor(x))
+}
+
+// Perlin
+
+func perlinGrad1(hash int, x float64) float64 {
+	if hash&1 == 0 {
+		return x
+	}
+	return -x
+}
+
+func perlinGrad2(hash int, x, y float64) float64 {
+	switch hash & 3 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	default:
+		return -x - y
+	}
+}
+
+func perlinGrad3(hash int, x, y, z float64) float64 {
+
+	h := hash & 15
+
+	u := y
+	if h < 8 {
+		u = x
+	}
+
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+
+	return u + v
+
+}
+
+// Perlin1D returns 1D Perlin noise at the position provided.
+func (noise *Noise) Perlin1D(x float64) float64 {
+
+	xi := floor(x)
+	xf := x - float64(xi)
+
+	u := fade(xf)
+
+	return lerp(perlinGrad1(noise.hash(xi), xf), perlinGrad1(noise.hash(xi+1), xf-1), u) * 2
+
+}
+
+// Perlin2D returns 2D Perlin noise at the position provided.
+func (noise *Noise) Perlin2D(x, y float64) float64 {
+
+	xi := floor(x)
+	yi := floor(y)
+	xf := x - float64(xi)
+	yf := y - float64(yi)
+
+	u := fade(xf)
+	v := fade(yf)
+
+	p := noise.perm[:]
+	x0 := xi & 255
+	y0 := yi & 255
+
+	aa := p[p[x0]+y0]
+	ab := p[p[x0]+y0+1]
+	ba := p[p[x0+1]+y0]
+	bb := p[p[x0+1]+y0+1]
+
+	return lerp(
+		lerp(perlinGrad2(aa, xf, yf), perlinGrad2(ba, xf-1, yf), u),
+		lerp(perlinGrad2(ab, xf, yf-1), perlinGrad2(bb, xf-1, yf-1), u),
+		v,
+	)
+
+}
+
+// Perlin3D returns 3D Perlin noise at the position provided.
+func (noise *Noise) Perlin3D(x, y, z float64) float64 {
+
+	xi := floor(x)
+	yi := floor(y)
+	zi := floor(z)
+	xf := x - float64(xi)
+	yf := y - float64(yi)
+	zf := z - float64(zi)
+
+	u := fade(xf)
+	v := fade(yf)
+	w := fade(zf)
+
+	p := noise.perm[:]
+	x0 := xi & 255
+	y0 := yi & 255
+	z0 := zi & 255
+
+	a := p[x0] + y0
+	aa := p[a] + z0
+	ab := p[a+1] + z0
+	b := p[x0+1] + y0
+	ba := p[b] + z0
+	bb := p[b+1] + z0
+
+	return lerp(
+		lerp(
+			lerp(perlinGrad3(p[aa], xf, yf, zf), perlinGrad3(p[ba], xf-1, yf, zf), u),
+			lerp(perlinGrad3(p[ab], xf, yf-1, zf), perlinGrad3(p[bb], xf-1, yf-1, zf), u),
+			v,
+		),
+		lerp(
+			lerp(perlinGrad3(p[aa+1], xf, yf, zf-1), perlinGrad3(p[ba+1], xf-1, yf, zf-1), u),
+			lerp(perlinGrad3(p[ab+1], xf, yf-1, zf-1), perlinGrad3(p[bb+1], xf-1, yf-1, zf-1), u),
+			v,
+		),
+		w,
+	)
+
+}
+
+// Simplex
+
+var simplexGrad3 = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+func simplexGrad1(hash int, x float64) float64 {
+	grad := 1 + float64(hash&7)
+	if hash&8 != 0 {
+		grad = -grad
+	}
+	return grad * x
+}
+
+// Simplex1D returns 1D simplex noise at the position provided.
+func (noise *Noise) Simplex1D(x float64) float64 {
+
+	i0 := floor(x)
+	x0 := x - float64(i0)
+	x1 := x0 - 1
+
+	t0 := 1 - x0*x0
+	t0 *= t0
+	n0 := t0 * t0 * simplexGrad1(noise.hash(i0), x0)
+
+	t1 := 1 - x1*x1
+	t1 *= t1
+	n1 := t1 * t1 * simplexGrad1(noise.hash(i0+1), x1)
+
+	return 0.395 * (n0 + n1)
+
+}
+
+var (
+	simplexF2 = 0.5 * (math.Sqrt(3) - 1)
+	simplexG2 = (3 - math.Sqrt(3)) / 6
+)
+
+// Simplex2D returns 2D simplex noise at the position provided.
+func (noise *Noise) Simplex2D(x, y float64) float64 {
+
+	s := (x + y) * simplexF2
+	i := floor(x + s)
+	j := floor(y + s)
+
+	t := float64(i+j) * simplexG2
+	x0 := x - (float64(i) - t)
+	y0 := y - (float64(j) - t)
+
+	i1, j1 := 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+
+	x1 := x0 - float64(i1) + simplexG2
+	y1 := y0 - float64(j1) + simplexG2
+	x2 := x0 - 1 + 2*simplexG2
+	y2 := y0 - 1 + 2*simplexG2
+
+	p := noise.perm[:]
+	ii := i & 255
+	jj := j & 255
+
+	corner := func(gi int, cx, cy float64) float64 {
+		t := 0.5 - cx*cx - cy*cy
+		if t < 0 {
+			return 0
+		}
+		g := simplexGrad3[gi%12]
+		t *= t
+		return t * t * (g[0]*cx + g[1]*cy)
+	}
+
+	n0 := corner(p[ii+p[jj]], x0, y0)
+	n1 := corner(p[ii+i1+p[jj+j1]], x1, y1)
+	n2 := corner(p[ii+1+p[jj+1]], x2, y2)
+
+	return 70 * (n0 + n1 + n2)
+
+}
+
+// Simplex3D returns 3D simplex noise at the position provided.
+func (noise *Noise) Simplex3D(x, y, z float64) float64 {
+
+	const f3 = 1.0 / 3.0
+	const g3 = 1.0 / 6.0
+
+	s := (x + y + z) * f3
+	i := floor(x + s)
+	j := floor(y + s)
+	k := floor(z + s)
+
+	t := float64(i+j+k) * g3
+	x0 := x - (float64(i) - t)
+	y0 := y - (float64(j) - t)
+	z0 := z - (float64(k) - t)
+
+	// Determine which simplex (of the six in a cube) we're in.
+	var i1, j1, k1, i2, j2, k2 int
+
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+		}
+	} else {
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+		}
+	}
+
+	x1 := x0 - float64(i1) + g3
+	y1 := y0 - float64(j1) + g3
+	z1 := z0 - float64(k1) + g3
+	x2 := x0 - float64(i2) + 2*g3
+	y2 := y0 - float64(j2) + 2*g3
+	z2 := z0 - float64(k2) + 2*g3
+	x3 := x0 - 1 + 3*g3
+	y3 := y0 - 1 + 3*g3
+	z3 := z0 - 1 + 3*g3
+
+	p := noise.perm[:]
+	ii := i & 255
+	jj := j & 255
+	kk := k & 255
+
+	corner := func(gi int, cx, cy, cz float64) float64 {
+		t := 0.6 - cx*cx - cy*cy - cz*cz
+		if t < 0 {
+			return 0
+		}
+		g := simplexGrad3[gi%12]
+		t *= t
+		return t * t * (g[0]*cx + g[1]*cy + g[2]*cz)
+	}
+
+	n0 := corner(p[ii+p[jj+p[kk]]], x0, y0, z0)
+	n1 := corner(p[ii+i1+p[jj+j1+p[kk+k1]]], x1, y1, z1)
+	n2 := corner(p[ii+i2+p[jj+j2+p[kk+k2]]], x2, y2, z2)
+	n3 := corner(p[ii+1+p[jj+1+p[kk+1]]], x3, y3, z3)
+
+	return 32 * (n0 + n1 + n2 + n3)
+
+}
+
+// Value
+
+func (noise *Noise) value(hash int) float64 {
+	return float64(hash)/255*2 - 1
+}
+
+// Value1D returns 1D value noise at the position provided.
+func (noise *Noise) Value1D(x float64) float64 {
+
+	xi := floor(x)
+	u := fade(x - float64(xi))
+
+	return lerp(noise.value(noise.hash(xi)), noise.value(noise.hash(xi+1)), u)
+
+}
+
+// Value2D returns 2D value noise at the position provided.
+func (noise *Noise) Value2D(x, y float64) float64 {
+
+	xi := floor(x)
+	yi := floor(y)
+	u := fade(x - float64(xi))
+	v := fade(y - float64(yi))
+
+	p := noise.perm[:]
+	x0 := xi & 255
+	y0 := yi & 255
+
+	return lerp(
+		lerp(noise.value(p[p[x0]+y0]), noise.value(p[p[x0+1]+y0]), u),
+		lerp(noise.value(p[p[x0]+y0+1]), noise.value(p[p[x0+1]+y0+1]), u),
+		v,
+	)
+
+}
+
+// Value3D returns 3D value noise at the position provided.
+func (noise *Noise) Value3D(x, y, z float64) float64 {
+
+	xi := floor(x)
+	yi := floor(y)
+	zi := floor(z)
+	u := fade(x - float64(xi))
+	v := fade(y - float64(yi))
+	w := fade(z - float64(zi))
+
+	p := noise.perm[:]
+	x0 := xi & 255
+	y0 := yi & 255
+	z0 := zi & 255
+
+	a := p[x0] + y0
+	b := p[x0+1] + y0
+
+	return lerp(
+		lerp(
+			lerp(noise.value(p[p[a]+z0]), noise.value(p[p[b]+z0]), u),
+			lerp(noise.value(p[p[a+1]+z0]), noise.value(p[p[b+1]+z0]), u),
+			v,
+		),
+		lerp(
+			lerp(noise.value(p[p[a]+z0+1]), noise.value(p[p[b]+z0+1]), u),
+			lerp(noise.value(p[p[a+1]+z0+1]), noise.value(p[p[b+1]+z0+1]), u),
+			v,
+		),
+		w,
+	)
+
+}
+
+// Fractal returns the sum of several octaves of the noise function provided (i.e. fractal Brownian motion), with each octave doubling
+// in frequency and halving in strength (scaled by persistence). The result is normalized to range (roughly) from -1 to 1. For example,
+// noise.Fractal(4, 0.5, func(f float64) float64 { return n.Perlin2D(x*f, y*f) }).
+func Fractal(octaves int, persistence float64, noiseFunc func(frequency float64) float64) float64 {
+
+	total := 0.0
+	amplitude := 1.0
+	maxAmplitude := 0.0
+	frequency := 1.0
+
+	for i := 0; i < octaves; i++ {
+		total += noiseFunc(frequency) * amplitude
+		maxAmplitude += amplitude
+		amplitude *= persistence
+		frequency *= 2
+	}
+
+	if maxAmplitude == 0 {
+		return 0
+	}
+
+	return total / maxAmplitude
+
+}
@@ -0,0 +1,101 @@
+package noise
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNoise(t *testing.T) {
+
+	n := New(1)
+	same := New(1)
+	other := New(2)
+
+	tests := []struct {
+		name    string
+		sample  func(noise *Noise, x, y, z float64) float64
+		lattice bool // If the noise is 0 at integer coordinates
+	}{
+		{"perlin 1D", func(n *Noise, x, y, z float64) float64 { return n.Perlin1D(x) }, true},
+		{"perlin 2D", func(n *Noise, x, y, z float64) float64 { return n.Perlin2D(x, y) }, true},
+		{"perlin 3D", func(n *Noise, x, y, z float64) float64 { return n.Perlin3D(x, y, z) }, true},
+		{"simplex 1D", func(n *Noise, x, y, z float64) float64 { return n.Simplex1D(x) }, true},
+		{"simplex 2D", func(n *Noise, x, y, z float64) float64 { return n.Simplex2D(x, y) }, false},
+		{"simplex 3D", func(n *Noise, x, y, z float64) float64 { return n.Simplex3D(x, y, z) }, false},
+		{"value 1D", func(n *Noise, x, y, z float64) float64 { return n.Value1D(x) }, false},
+		{"value 2D", func(n *Noise, x, y, z float64) float64 { return n.Value2D(x, y) }, false},
+		{"value 3D", func(n *Noise, x, y, z float64) float64 { return n.Value3D(x, y, z) }, false},
+	}
+
+	for _, test := range tests {
+
+		differs := false
+		varies := false
+		first := test.sample(n, 0.5, 0.5, 0.5)
+
+		for i := 0; i < 500; i++ {
+
+			x, y, z := float64(i)*0.37-50, float64(i)*0.61-80, float64(i)*0.13+3
+
+			value := test.sample(n, x, y, z)
+
+			if value < -1.01 || value > 1.01 || math.IsNaN(value) {
+				t.Errorf("%s: value at %f, %f, %f = %f, out of range", test.name, x, y, z, value)
+				break
+			}
+
+			if value != test.sample(same, x, y, z) {
+				t.Errorf("%s: generators with the same seed gave different values at %f, %f, %f", test.name, x, y, z)
+				break
+			}
+
+			if value != test.sample(other, x, y, z) {
+				differs = true
+			}
+
+			if value != first {
+				varies = true
+			}
+
+			if test.lattice {
+				if v := test.sample(n, float64(i-250), float64(i-100), float64(i)); math.Abs(v) > 1e-9 {
+					t.Errorf("%s: value at integer coordinates = %f, want 0", test.name, v)
+					break
+				}
+			}
+
+		}
+
+		if !differs {
+			t.Errorf("%s: generators with different seeds gave identical values", test.name)
+		}
+
+		if !varies {
+			t.Errorf("%s: noise is constant", test.name)
+		}
+
+	}
+
+}
+
+func TestFractal(t *testing.T) {
+
+	tests := []struct {
+		octaves     int
+		persistence float64
+		noise       func(frequency float64) float64
+		want        float64
+	}{
+		{0, 0.5, func(f float64) float64 { return 1 }, 0},
+		{1, 0.5, func(f float64) float64 { return 0.5 }, 0.5},
+		{4, 0.5, func(f float64) float64 { return 1 }, 1},
+		{2, 0.5, func(f float64) float64 { return f - 1 }, 1.0 / 3},
+	}
+
+	for _, test := range tests {
+		if value := Fractal(test.octaves, test.persistence, test.noise); math.Abs(value-test.want) > 1e-9 {
+			t.Errorf("Fractal(%d, %f) = %f, want %f", test.octaves, test.persistence, value, test.want)
+		}
+	}
+
+}
@@ -0,0 +1,288 @@
+package tetra3d
+
+import (
+	"math"
+)
+
+// Octree holds settings for a Scene's octree, a spatial partition of the Models in the Scene that Camera.RenderNodes() uses to quickly find the
+// Models in view when rendering the Scene's Root, culling whole regions of the Scene at once. Unlike hierarchical culling (see
+// Camera.HierarchicalCulling), the octree doesn't depend on how the Scene's tree is organized, so it works well for large levels where hundreds
+// of Models are children of the Root. The octree is updated incrementally when the Scene is rendered: only Models that have moved, changed, or
+// been added to or removed from the Scene since the last render are moved between its cells, so Scenes where most Models are stationary cost
+// very little to keep partitioned. The octree is loose, so each Model is placed in a single cell according to its bounding sphere, and grows to
+// fit Models as they're added.
+type Octree struct {
+	On       bool
+	MaxDepth int // The maximum depth of the octree's cells; deeper octrees cull small Models more precisely, but take more tests to traverse. Defaults to 8.
+
+	root     *octreeCell
+	unculled *octreeCell // Models that can't be culled (i.e. they have FrustumCulling off or own dynamically batched Models); these are always rendered
+	rootNode INode       // The Node the octree partitions the subtree of
+}
+
+func newOctree() Octree {
+	return Octree{
+		MaxDepth: 8,
+	}
+}
+
+// octreeCell is a cell of an Octree. As the octree is loose, the Models in a cell can extend beyond it by up to half of its size on each side.
+type octreeCell struct {
+	parent   *octreeCell
+	children [8]*octreeCell
+	center   Vector3
+	halfSize float64
+	models   []*Model
+	count    int // The number of Models in the cell and its children
+}
+
+// update brings the Octree up to date with the subtree of the root Node provided, only visiting the branches that have changed since it was last
+// updated.
+func (octree *Octree) update(root INode) {
+
+	if octree.rootNode != root {
+		octree.clear()
+		octree.rootNode = root
+		octree.unculled = &octreeCell{}
+	}
+
+	octree.updateNode(root)
+
+}
+
+// updateNode updates the Octree with the Node provided and its subtree.
+func (octree *Octree) updateNode(node INode) {
+
+	bounds := getSubtreeBounds(node)
+
+	if !bounds.octreeDirty {
+		return
+	}
+
+	bounds.octreeDirty = false
+
+	if model, isModel := node.(*Model); isModel {
+		octree.updateModel(model)
+	}
+
+	for _, child := range rawChildren(node) {
+		octree.updateNode(child)
+	}
+
+}
+
+// updateModel moves the Model provided to the Octree cell that fits it.
+func (octree *Octree) updateModel(model *Model) {
+
+	if model.octreeCell != nil {
+		model.octreeCell.remove(model)
+	}
+
+	if model.Mesh == nil {
+		return
+	}
+
+	model.Transform()
+
+	center := NewVector3FromVector(model.BoundingSphere.WorldPosition())
+	radius := model.BoundingSphere.WorldRadius()
+
+	if !model.frustumCulling || len(model.DynamicBatchModels) > 0 || math.IsInf(radius, 0) || math.IsNaN(radius) {
+		octree.unculled.add(model)
+		return
+	}
+
+	if octree.root == nil {
+		octree.root = &octreeCell{center: center, halfSize: math.Max(radius, 1)}
+	}
+
+	for !octree.root.fits(center, radius) {
+		octree.grow(center)
+	}
+
+	cell := octree.root
+
+	// Models are placed in the smallest cell that fits them; as cells are loose, a cell fits any Model no bigger than it whose center is within it.
+	for depth := 0; depth < octree.MaxDepth && radius <= cell.halfSize/2; depth++ {
+
+		index, childCenter := cell.octant(center)
+
+		if cell.children[index] == nil {
+			cell.children[index] = &octreeCell{parent: cell, center: childCenter, halfSize: cell.halfSize / 2}
+		}
+
+		cell = cell.children[index]
+
+	}
+
+	cell.add(model)
+
+}
+
+// grow doubles the size of the Octree's root cell in the direction of the point provided, making the old root cell one of its children.
+func (octree *Octree) grow(towards Vector3) {
+
+	old := octree.root
+
+	center := old.center
+
+	if towards.X < center.X {
+		center.X -= old.halfSize
+	} else {
+		center.X += old.halfSize
+	}
+
+	if towards.Y < center.Y {
+		center.Y -= old.halfSize
+	} else {
+		center.Y += old.halfSize
+	}
+
+	if towards.Z < center.Z {
+		center.Z -= old.halfSize
+	} else {
+		center.Z += old.halfSize
+	}
+
+	root := &octreeCell{center: center, halfSize: old.halfSize * 2, count: old.count}
+
+	index, _ := root.octant(old.center)
+	root.children[index] = old
+	old.parent = root
+
+	octree.root = root
+
+}
+
+// clear removes all Models from the Octree.
+func (octree *Octree) clear() {
+
+	if octree.rootNode != nil {
+		resetOctree(octree.rootNode)
+	}
+
+	octree.root = nil
+	octree.unculled = nil
+	octree.rootNode = nil
+
+}
+
+// resetOctree removes the Node provided and its subtree from any Octree they're in, marking them as needing to be added to an Octree again.
+func resetOctree(node INode) {
+
+	getSubtreeBounds(node).octreeDirty = true
+
+	if model, isModel := node.(*Model); isModel && model.octreeCell != nil {
+		model.octreeCell.remove(model)
+	}
+
+	for _, child := range rawChildren(node) {
+		resetOctree(child)
+	}
+
+}
+
+// fits returns if the cell fits a sphere of the radius provided at the given center.
+func (cell *octreeCell) fits(center Vector3, radius float64) bool {
+	return radius <= cell.halfSize &&
+		math.Abs(center.X-cell.center.X) <= cell.halfSize &&
+		math.Abs(center.Y-cell.center.Y) <= cell.halfSize &&
+		math.Abs(center.Z-cell.center.Z) <= cell.halfSize
+}
+
+// octant returns the index and center of the child of the cell that the point provided is in.
+func (cell *octreeCell) octant(point Vector3) (int, Vector3) {
+
+	index := 0
+	center := cell.center
+	offset := cell.halfSize / 2
+
+	if point.X >= cell.center.X {
+		index |= 1
+		center.X += offset
+	} else {
+		center.X -= offset
+	}
+
+	if point.Y >= cell.center.Y {
+		index |= 2
+		center.Y += offset
+	} else {
+		center.Y -= offset
+	}
+
+	if point.Z >= cell.center.Z {
+		index |= 4
+		center.Z += offset
+	} else {
+		center.Z -= offset
+	}
+
+	return index, center
+
+}
+
+// add adds the Model provided to the cell.
+func (cell *octreeCell) add(model *Model) {
+
+	model.octreeCell = cell
+	model.octreeIndex = len(cell.models)
+	cell.models = append(cell.models, model)
+
+	for c := cell; c != nil; c = c.parent {
+		c.count++
+	}
+
+}
+
+// remove removes the Model provided from the cell, removing any cells that are left empty from the octree.
+func (cell *octreeCell) remove(model *Model) {
+
+	last := len(cell.models) - 1
+	moved := cell.models[last]
+	cell.models[model.octreeIndex] = moved
+	moved.octreeIndex = model.octreeIndex
+	cell.models[last] = nil
+	cell.models = cell.models[:last]
+
+	model.octreeCell = nil
+
+	for c := cell; c != nil; c = c.parent {
+
+		c.count--
+
+		if c.count == 0 && c.parent != nil {
+			for i, child := range c.parent.children {
+				if child == c {
+					c.parent.children[i] = nil
+				}
+			}
+		}
+
+	}
+
+}
+
+// appendOctreeModels appends the Models in the octree cell provided and its children that may be visible to the Camera to the provided slice,
+// returning the result. Models that are culled are counted in the Camera's RenderStats.
+func (camera *Camera) appendOctreeModels(models []*Model, cell *octreeCell) []*Model {
+
+	if cell == nil || cell.count == 0 {
+		return models
+	}
+
+	// The Models in a cell can extend beyond it by up to half of its size on each side, so the sphere encompassing the cell is twice as big.
+	if !camera.sphereInFrustum(cell.center, cell.halfSize*2*math.Sqrt(3)) {
+		camera.RenderStats.ModelsCulled += cell.count
+		return models
+	}
+
+	models = append(models, cell.models...)
+
+	for _, child := range cell.children {
+		models = camera.appendOctreeModels(models, child)
+	}
+
+	return models
+
+}
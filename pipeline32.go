@@ -0,0 +1,99 @@
+//go:build tetra3d_float32
+// +build tetra3d_float32
+
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/kvartborg/vector"
+)
+
+// Float32Pipeline indicates if Tetra3D was built with the tetra3d_float32 build tag, in which case vertex transformation and lighting are
+// calculated using float32 math rather than float64. Vertex data is still stored as float64 and converted for each operation, so this
+// lowers precision to match what Ebiten renders with, but isn't a guaranteed speedup. By default (i.e. without the build tag), this is false.
+const Float32Pipeline = true
+
+// pipelineMatrix is the matrix type used for per-vertex transformations in the render pipeline. With the float32 pipeline, it's a flat
+// array of float32s, laid out so that the coefficients for each output component are contiguous.
+type pipelineMatrix [16]float32
+
+func newPipelineMatrix(matrix Matrix4) pipelineMatrix {
+
+	m := pipelineMatrix{}
+
+	for column := 0; column < 4; column++ {
+		for row := 0; row < 4; row++ {
+			m[column*4+row] = float32(matrix[row][column])
+		}
+	}
+
+	return m
+
+}
+
+// pipelineMultVecW multiplies the vector by the pipeline matrix, returning the X, Y, Z, and W components of the result.
+func pipelineMultVecW(matrix *pipelineMatrix, vect vector.Vector) (x, y, z, w float64) {
+
+	vx, vy, vz := float32(vect[0]), float32(vect[1]), float32(vect[2])
+
+	x = float64(matrix[0]*vx + matrix[1]*vy + matrix[2]*vz + matrix[3])
+	y = float64(matrix[4]*vx + matrix[5]*vy + matrix[6]*vz + matrix[7])
+	z = float64(matrix[8]*vx + matrix[9]*vy + matrix[10]*vz + matrix[11])
+	w = float64(matrix[12]*vx + matrix[13]*vy + matrix[14]*vz + matrix[15])
+
+	return
+
+}
+
+// pipelineMultVec3W multiplies the Vector3 by the pipeline matrix, returning the X, Y, Z, and W components of the result.
+func pipelineMultVec3W(matrix *pipelineMatrix, vect Vector3) (x, y, z, w float64) {
+
+	vx, vy, vz := float32(vect.X), float32(vect.Y), float32(vect.Z)
+
+	x = float64(matrix[0]*vx + matrix[1]*vy + matrix[2]*vz + matrix[3])
+	y = float64(matrix[4]*vx + matrix[5]*vy + matrix[6]*vz + matrix[7])
+	z = float64(matrix[8]*vx + matrix[9]*vy + matrix[10]*vz + matrix[11])
+	w = float64(matrix[12]*vx + matrix[13]*vy + matrix[14]*vz + matrix[15])
+
+	return
+
+}
+
+// pipelineMultVecRotation multiplies the vector by the upper 3x3 portion of the pipeline matrix (i.e. ignoring translation), returning the result.
+func pipelineMultVecRotation(matrix *pipelineMatrix, vect vector.Vector) (x, y, z float64) {
+
+	vx, vy, vz := float32(vect[0]), float32(vect[1]), float32(vect[2])
+
+	x = float64(matrix[0]*vx + matrix[1]*vy + matrix[2]*vz)
+	y = float64(matrix[4]*vx + matrix[5]*vy + matrix[6]*vz)
+	z = float64(matrix[8]*vx + matrix[9]*vy + matrix[10]*vz)
+
+	return
+
+}
+
+// pipelinePointDiffuse returns the diffuse lighting factor and squared distance from a light at lightPos to a vertex at vertPos with the given normal.
+func pipelinePointDiffuse(lightPos, vertPos, vertNormal Vector3) (diffuse, distanceSquared float64) {
+
+	dx := float32(lightPos.X - vertPos.X)
+	dy := float32(lightPos.Y - vertPos.Y)
+	dz := float32(lightPos.Z - vertPos.Z)
+
+	dist := dx*dx + dy*dy + dz*dz
+
+	if dist == 0 {
+		return 0, 0
+	}
+
+	length := float32(math.Sqrt(float64(dist)))
+
+	d := (float32(vertNormal.X)*dx + float32(vertNormal.Y)*dy + float32(vertNormal.Z)*dz) / length
+
+	if d < 0 {
+		d = 0
+	}
+
+	return float64(d), float64(dist)
+
+}
@@ -0,0 +1,66 @@
+//go:build !tetra3d_float32
+// +build !tetra3d_float32
+
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/kvartborg/vector"
+)
+
+// Float32Pipeline indicates if Tetra3D was built with the tetra3d_float32 build tag, in which case vertex transformation and lighting are
+// calculated using float32 math rather than float64. Vertex data is still stored as float64 and converted for each operation, so this
+// lowers precision to match what Ebiten renders with, but isn't a guaranteed speedup. By default (i.e. without the build tag), this is false.
+const Float32Pipeline = false
+
+// pipelineMatrix is the matrix type used for per-vertex transformations in the render pipeline. With the default pipeline, it's simply a Matrix4.
+type pipelineMatrix = Matrix4
+
+func newPipelineMatrix(matrix Matrix4) pipelineMatrix {
+	return matrix
+}
+
+// pipelineMultVecW multiplies the vector by the pipeline matrix, returning the X, Y, Z, and W components of the result.
+func pipelineMultVecW(matrix *pipelineMatrix, vect vector.Vector) (x, y, z, w float64) {
+	return fastMatrixMultVecW(*matrix, vect)
+}
+
+// pipelineMultVec3W multiplies the Vector3 by the pipeline matrix, returning the X, Y, Z, and W components of the result.
+func pipelineMultVec3W(matrix *pipelineMatrix, vect Vector3) (x, y, z, w float64) {
+	v := matrix.MultVec3W(vect)
+	return v.X, v.Y, v.Z, v.W
+}
+
+// pipelineMultVecRotation multiplies the vector by the upper 3x3 portion of the pipeline matrix (i.e. ignoring translation), returning the result.
+func pipelineMultVecRotation(matrix *pipelineMatrix, vect vector.Vector) (x, y, z float64) {
+	x = matrix[0][0]*vect[0] + matrix[1][0]*vect[1] + matrix[2][0]*vect[2]
+	y = matrix[0][1]*vect[0] + matrix[1][1]*vect[1] + matrix[2][1]*vect[2]
+	z = matrix[0][2]*vect[0] + matrix[1][2]*vect[1] + matrix[2][2]*vect[2]
+	return
+}
+
+// pipelinePointDiffuse returns the diffuse lighting factor and squared distance from a light at lightPos to a vertex at vertPos with the given normal.
+func pipelinePointDiffuse(lightPos, vertPos, vertNormal Vector3) (diffuse, distanceSquared float64) {
+
+	dx := lightPos.X - vertPos.X
+	dy := lightPos.Y - vertPos.Y
+	dz := lightPos.Z - vertPos.Z
+
+	distanceSquared = dx*dx + dy*dy + dz*dz
+
+	if distanceSquared == 0 {
+		return 0, 0
+	}
+
+	length := math.Sqrt(distanceSquared)
+
+	diffuse = (vertNormal.X*dx + vertNormal.Y*dy + vertNormal.Z*dz) / length
+
+	if diffuse < 0 {
+		diffuse = 0
+	}
+
+	return
+
+}
@@ -0,0 +1,33 @@
+package tetra3d
+
+// ProfileStage indicates a stage of Tetra3D's rendering or animation pipelines; it's passed to the OnStageBegin and OnStageEnd hooks.
+type ProfileStage string
+
+const (
+	ProfileStageRender    ProfileStage = "render"    // Rendering a set of Models with Camera.Render() or Camera.RenderNodes().
+	ProfileStageTransform ProfileStage = "transform" // Transforming (and possibly skinning) the vertices of a MeshPart.
+	ProfileStageSort      ProfileStage = "sort"      // Sorting the triangles of a MeshPart.
+	ProfileStageLighting  ProfileStage = "lighting"  // Coloring and lighting the vertices of a MeshPart; only happens for lit MeshParts.
+	ProfileStageRasterize ProfileStage = "rasterize" // Issuing draw calls to rasterize triangles.
+	ProfileStageAnimation ProfileStage = "animation" // Updating an AnimationPlayer.
+)
+
+// OnStageBegin, if set, is called when Tetra3D begins a stage of its rendering or animation pipelines, allowing you to forward timings
+// to your own profiler or telemetry. Note that some stages (transforming, sorting, lighting, and rasterizing) happen once for each
+// MeshPart rendered, and stages can be nested (i.e. the transform stage happens within the render stage).
+var OnStageBegin func(stage ProfileStage)
+
+// OnStageEnd, if set, is called when Tetra3D ends a stage of its rendering or animation pipelines. See OnStageBegin.
+var OnStageEnd func(stage ProfileStage)
+
+func stageBegin(stage ProfileStage) {
+	if OnStageBegin != nil {
+		OnStageBegin(stage)
+	}
+}
+
+func stageEnd(stage ProfileStage) {
+	if OnStageEnd != nil {
+		OnStageEnd(stage)
+	}
+}
@@ -10,6 +10,47 @@ func NewQuaternion(x, y, z, w float64) *Quaternion {
 	return &Quaternion{x, y, z, w}
 }
 
+// NewQuaternionFromMatrix4 returns a new Quaternion representing the rotation of the provided rotation Matrix4 (which should not be scaled).
+func NewQuaternionFromMatrix4(matrix Matrix4) *Quaternion {
+
+	trace := matrix[0][0] + matrix[1][1] + matrix[2][2]
+
+	if trace > 0 {
+		s := 0.5 / math.Sqrt(trace+1)
+		return NewQuaternion(
+			(matrix[1][2]-matrix[2][1])*s,
+			(matrix[2][0]-matrix[0][2])*s,
+			(matrix[0][1]-matrix[1][0])*s,
+			0.25/s,
+		)
+	} else if matrix[0][0] > matrix[1][1] && matrix[0][0] > matrix[2][2] {
+		s := 2 * math.Sqrt(1+matrix[0][0]-matrix[1][1]-matrix[2][2])
+		return NewQuaternion(
+			0.25*s,
+			(matrix[0][1]+matrix[1][0])/s,
+			(matrix[2][0]+matrix[0][2])/s,
+			(matrix[1][2]-matrix[2][1])/s,
+		)
+	} else if matrix[1][1] > matrix[2][2] {
+		s := 2 * math.Sqrt(1+matrix[1][1]-matrix[0][0]-matrix[2][2])
+		return NewQuaternion(
+			(matrix[0][1]+matrix[1][0])/s,
+			0.25*s,
+			(matrix[1][2]+matrix[2][1])/s,
+			(matrix[2][0]-matrix[0][2])/s,
+		)
+	}
+
+	s := 2 * math.Sqrt(1+matrix[2][2]-matrix[0][0]-matrix[1][1])
+	return NewQuaternion(
+		(matrix[2][0]+matrix[0][2])/s,
+		(matrix[1][2]+matrix[2][1])/s,
+		0.25*s,
+		(matrix[0][1]-matrix[1][0])/s,
+	)
+
+}
+
 func (quat *Quaternion) Clone() *Quaternion {
 	return NewQuaternion(quat.X, quat.Y, quat.Z, quat.W)
 }
@@ -0,0 +1,111 @@
+package tetra3d
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/kvartborg/vector"
+)
+
+// The random helpers below use math/rand's global source, so they can be seeded (or made reproducible) with rand.Seed().
+
+// RandomUnitVector returns a random normalized 3D vector, uniformly distributed over the surface of a unit sphere.
+func RandomUnitVector() vector.Vector {
+	return randomUnitVector3().ToVector()
+}
+
+func randomUnitVector3() Vector3 {
+	z := rand.Float64()*2 - 1
+	angle := rand.Float64() * math.Pi * 2
+	r := math.Sqrt(1 - z*z)
+	return NewVector3(r*math.Cos(angle), r*math.Sin(angle), z)
+}
+
+// RandomPointOnSphere returns a random point, uniformly distributed over the surface of a sphere of the given radius centered on the origin.
+func RandomPointOnSphere(radius float64) vector.Vector {
+	return randomUnitVector3().Scale(radius).ToVector()
+}
+
+// RandomPointInSphere returns a random point, uniformly distributed within the volume of a sphere of the given radius centered on the origin.
+func RandomPointInSphere(radius float64) vector.Vector {
+	return randomUnitVector3().Scale(radius * math.Cbrt(rand.Float64())).ToVector()
+}
+
+// RandomVectorInCone returns a random normalized vector within a cone around the direction provided, with angle being the
+// maximum angle (in radians) away from the direction. The results are uniformly distributed over the cone's spherical cap.
+func RandomVectorInCone(direction vector.Vector, angle float64) vector.Vector {
+
+	dir := NewVector3FromVector(direction).Unit()
+
+	// Build two axes perpendicular to the direction to spread the result around it.
+	up := NewVector3(0, 1, 0)
+	if math.Abs(dir.Dot(up)) > 0.999 {
+		up = NewVector3(1, 0, 0)
+	}
+	right := up.Cross(dir).Unit()
+	up = dir.Cross(right)
+
+	z := 1 - rand.Float64()*(1-math.Cos(angle))
+	r := math.Sqrt(1 - z*z)
+	spin := rand.Float64() * math.Pi * 2
+
+	return dir.Scale(z).Add(right.Scale(r * math.Cos(spin))).Add(up.Scale(r * math.Sin(spin))).ToVector()
+
+}
+
+// RandomPointOnTriangle returns a random point, uniformly distributed over the surface of the triangle made up of the three points provided.
+func RandomPointOnTriangle(a, b, c vector.Vector) vector.Vector {
+	return randomPointOnTriangle3(NewVector3FromVector(a), NewVector3FromVector(b), NewVector3FromVector(c)).ToVector()
+}
+
+func randomPointOnTriangle3(a, b, c Vector3) Vector3 {
+	u := rand.Float64()
+	v := rand.Float64()
+	if u+v > 1 {
+		u = 1 - u
+		v = 1 - v
+	}
+	return a.Add(b.Sub(a).Scale(u)).Add(c.Sub(a).Scale(v))
+}
+
+// RandomPointOnMesh returns a random point in local space, uniformly distributed over the surface of the Mesh (so larger triangles are
+// more likely to be chosen than smaller ones). If the Mesh has no triangles, the origin is returned. Note that this walks all of the
+// Mesh's triangles on each call, so it's best to pick several points at once for large Meshes.
+func RandomPointOnMesh(mesh *Mesh) vector.Vector {
+
+	if len(mesh.Triangles) == 0 {
+		return vector.Vector{0, 0, 0}
+	}
+
+	totalArea := 0.0
+	areas := make([]float64, len(mesh.Triangles))
+
+	for i, tri := range mesh.Triangles {
+		a, b, c := meshTriangleVertices(mesh, tri)
+		totalArea += b.Sub(a).Cross(c.Sub(a)).Magnitude() / 2
+		areas[i] = totalArea
+	}
+
+	target := rand.Float64() * totalArea
+
+	for i, tri := range mesh.Triangles {
+		if target <= areas[i] || i == len(mesh.Triangles)-1 {
+			a, b, c := meshTriangleVertices(mesh, tri)
+			return randomPointOnTriangle3(a, b, c).ToVector()
+		}
+	}
+
+	return vector.Vector{0, 0, 0}
+
+}
+
+// RandomPointOnModel returns a random point in world space, uniformly distributed over the surface of the Model's Mesh. See RandomPointOnMesh().
+func RandomPointOnModel(model *Model) vector.Vector {
+	return model.Transform().MultVec(RandomPointOnMesh(model.Mesh))
+}
+
+func meshTriangleVertices(mesh *Mesh, tri *Triangle) (Vector3, Vector3, Vector3) {
+	return NewVector3FromVector(mesh.VertexPositions[tri.ID*3]),
+		NewVector3FromVector(mesh.VertexPositions[tri.ID*3+1]),
+		NewVector3FromVector(mesh.VertexPositions[tri.ID*3+2])
+}
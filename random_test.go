@@ -0,0 +1,43 @@
+package tetra3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kvartborg/vector"
+)
+
+func TestRandomPoints(t *testing.T) {
+
+	a := vector.Vector{0, 0, 0}
+	b := vector.Vector{2, 0, 0}
+	c := vector.Vector{0, 2, 0}
+	coneDirection := vector.Vector{0, 0, -1}
+	coneAngle := math.Pi / 8
+
+	tests := []struct {
+		name  string
+		point func() vector.Vector
+		valid func(point vector.Vector) bool
+	}{
+		{"unit vector", RandomUnitVector, func(p vector.Vector) bool { return math.Abs(p.Magnitude()-1) < testEpsilon }},
+		{"on sphere", func() vector.Vector { return RandomPointOnSphere(3) }, func(p vector.Vector) bool { return math.Abs(p.Magnitude()-3) < testEpsilon }},
+		{"in sphere", func() vector.Vector { return RandomPointInSphere(3) }, func(p vector.Vector) bool { return p.Magnitude() <= 3+testEpsilon }},
+		{"in cone", func() vector.Vector { return RandomVectorInCone(coneDirection, coneAngle) }, func(p vector.Vector) bool {
+			return math.Abs(p.Magnitude()-1) < testEpsilon && math.Acos(math.Min(p.Dot(coneDirection), 1)) <= coneAngle+testEpsilon
+		}},
+		{"on triangle", func() vector.Vector { return RandomPointOnTriangle(a, b, c) }, func(p vector.Vector) bool {
+			return p[0] >= -testEpsilon && p[1] >= -testEpsilon && p[0]+p[1] <= 2+testEpsilon && math.Abs(p[2]) < testEpsilon
+		}},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 1000; i++ {
+			if point := test.point(); !test.valid(point) {
+				t.Errorf("%s: invalid point %v", test.name, point)
+				break
+			}
+		}
+	}
+
+}
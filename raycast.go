@@ -0,0 +1,240 @@
+package tetra3d
+
+import (
+	"math"
+	"sort"
+
+	"github.com/kvartborg/vector"
+)
+
+// RayHit represents a Ray hitting a Node, as returned by RayTest().
+type RayHit struct {
+	Node     INode         // The Node that was hit
+	Position vector.Vector // The world position the Ray hit the Node at
+	Normal   vector.Vector // The world-space normal of the surface that was hit; for triangles, this faces back towards the Ray's origin
+	Distance float64       // The distance from the Ray's origin to the hit position
+	Triangle *Triangle     // The Triangle that was hit if the Node is a Model or BoundingTriangles; otherwise, this is nil
+}
+
+// RayTest casts a ray from the origin in the direction provided against the Nodes provided, returning a RayHit for each Node hit, sorted
+// from nearest to furthest. BoundingAABBs, BoundingSpheres, and BoundingCapsules are tested against their shapes, while BoundingTriangles
+// and Models are tested against each of their triangles (after a broadphase test against their bounds); other Nodes are ignored. A ray that
+// starts inside of a bounding shape hits it at a distance of 0. To test against a whole Scene, pass the Nodes underneath its root (i.e.
+// RayTest(origin, direction, scene.Root.ChildrenRecursive()...)); to find what's under the mouse cursor, use the Ray from Camera.MouseRay().
+// If nothing was hit, an empty slice is returned.
+func RayTest(origin, direction vector.Vector, nodes ...INode) []*RayHit {
+	return NewRay(origin, direction).Test(nodes...)
+}
+
+// Test tests the Ray against the Nodes provided, returning a RayHit for each Node hit, sorted from nearest to furthest. See RayTest() for
+// more information.
+func (ray Ray) Test(nodes ...INode) []*RayHit {
+
+	hits := []*RayHit{}
+
+	for _, node := range nodes {
+		if hit := ray.test(node); hit != nil {
+			hits = append(hits, hit)
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Distance < hits[j].Distance })
+
+	return hits
+
+}
+
+// test tests the Ray against the Node provided, returning the nearest RayHit, or nil if the Ray doesn't hit it.
+func (ray Ray) test(node INode) *RayHit {
+
+	switch n := node.(type) {
+
+	case *BoundingAABB:
+
+		dist, hit := ray.IntersectAABB(n)
+
+		if !hit {
+			return nil
+		}
+
+		position := ray.PointAt(dist)
+		normal := ray.Direction.Invert()
+
+		if dist > 0 {
+			// The face that was hit is the one the hit position is furthest along, relative to the size of the box.
+			diff := position.Sub(n.WorldPosition())
+			for axis := 0; axis < 3; axis++ {
+				if n.Size[axis] > 0 {
+					diff[axis] /= n.Size[axis]
+				}
+			}
+			normal = aabbNormalGuess(diff)
+		}
+
+		return &RayHit{Node: n, Position: position, Normal: normal, Distance: dist}
+
+	case *BoundingSphere:
+
+		n.Transform()
+		center := n.WorldPosition()
+
+		dist, hit := ray.IntersectSphere(center, n.WorldRadius())
+
+		if !hit {
+			return nil
+		}
+
+		position := ray.PointAt(dist)
+		normal := ray.Direction.Invert()
+
+		if dist > 0 {
+			normal = position.Sub(center).Unit()
+		}
+
+		return &RayHit{Node: n, Position: position, Normal: normal, Distance: dist}
+
+	case *BoundingCapsule:
+
+		n.Transform()
+
+		dist, hit := ray.IntersectCapsule(n.lineBottom(), n.lineTop(), n.WorldRadius())
+
+		if !hit {
+			return nil
+		}
+
+		position := ray.PointAt(dist)
+		normal := ray.Direction.Invert()
+
+		if dist > 0 {
+			normal = position.Sub(n.ClosestPoint(position)).Unit()
+		}
+
+		return &RayHit{Node: n, Position: position, Normal: normal, Distance: dist}
+
+	case *BoundingTriangles:
+
+		transform := n.Transform()
+
+		if _, hit := ray.IntersectAABB(n.BoundingAABB); !hit {
+			return nil
+		}
+
+		return ray.testTriangles(n, n.Mesh, n.Mesh.VertexPositions, transform)
+
+	case *Model:
+
+		if n.Mesh == nil {
+			return nil
+		}
+
+		transform := n.Transform()
+
+		if _, hit := ray.IntersectSphere(n.BoundingSphere.WorldPosition(), n.BoundingSphere.WorldRadius()); !hit {
+			return nil
+		}
+
+		// Skinned and Static Models are tested using the world-space vertices cached when they were last rendered, so skinned Models are
+		// tested in the pose they were last rendered in.
+		if positions, _ := n.worldSpaceVertices(); positions != nil {
+			return ray.testWorldTriangles(n, n.Mesh, positions)
+		}
+
+		return ray.testTriangles(n, n.Mesh, n.vertexPositions(), transform)
+
+	}
+
+	return nil
+
+}
+
+// testTriangles tests the Ray against the triangles of the Mesh provided, using the local vertex positions and world transform provided.
+func (ray Ray) testTriangles(node INode, mesh *Mesh, positions []vector.Vector, transform Matrix4) *RayHit {
+
+	// Rather than transforming each vertex into world space, the Ray is transformed into the Mesh's local space. As the transformation is
+	// affine, distances along the local Ray (with its direction left unnormalized) match distances along the world Ray.
+	inverse := transform.Inverted()
+	localOrigin := inverse.MultVec3(NewVector3FromVector(ray.Origin))
+	localDir := inverse.MultVec3(NewVector3FromVector(ray.Origin.Add(ray.Direction))).Sub(localOrigin)
+
+	nearest := math.MaxFloat64
+	var nearestTri *Triangle
+
+	for _, tri := range mesh.Triangles {
+
+		id := tri.ID * 3
+
+		a := NewVector3FromVector(positions[id])
+		b := NewVector3FromVector(positions[id+1])
+		c := NewVector3FromVector(positions[id+2])
+
+		if dist, hit := intersectRayTriangle(localOrigin, localDir, a, b, c); hit && dist < nearest {
+			nearest = dist
+			nearestTri = tri
+		}
+
+	}
+
+	if nearestTri == nil {
+		return nil
+	}
+
+	id := nearestTri.ID * 3
+
+	return ray.newTriangleHit(node, nearestTri, nearest,
+		transform.MultVec(positions[id]),
+		transform.MultVec(positions[id+1]),
+		transform.MultVec(positions[id+2]),
+	)
+
+}
+
+// testWorldTriangles tests the Ray against the triangles of the Mesh provided, using the world-space vertex positions provided.
+func (ray Ray) testWorldTriangles(node INode, mesh *Mesh, positions []Vector3) *RayHit {
+
+	origin := NewVector3FromVector(ray.Origin)
+	dir := NewVector3FromVector(ray.Direction)
+
+	nearest := math.MaxFloat64
+	var nearestTri *Triangle
+
+	for _, tri := range mesh.Triangles {
+
+		id := tri.ID * 3
+
+		if dist, hit := intersectRayTriangle(origin, dir, positions[id], positions[id+1], positions[id+2]); hit && dist < nearest {
+			nearest = dist
+			nearestTri = tri
+		}
+
+	}
+
+	if nearestTri == nil {
+		return nil
+	}
+
+	id := nearestTri.ID * 3
+
+	return ray.newTriangleHit(node, nearestTri, nearest, positions[id].ToVector(), positions[id+1].ToVector(), positions[id+2].ToVector())
+
+}
+
+// newTriangleHit returns a new RayHit for the Ray hitting the triangle with the world-space vertices provided at the distance given.
+func (ray Ray) newTriangleHit(node INode, tri *Triangle, distance float64, a, b, c vector.Vector) *RayHit {
+
+	normal := calculateNormal(a, b, c)
+
+	// Triangles are hit from both sides, so the normal is flipped to face the side that was hit.
+	if normal.Dot(ray.Direction) > 0 {
+		normal = normal.Invert()
+	}
+
+	return &RayHit{
+		Node:     node,
+		Position: ray.PointAt(distance),
+		Normal:   normal,
+		Distance: distance,
+		Triangle: tri,
+	}
+
+}
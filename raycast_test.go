@@ -0,0 +1,63 @@
+package tetra3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kvartborg/vector"
+)
+
+func TestRayTest(t *testing.T) {
+
+	cube := NewModel(NewCube(), "cube")
+	cube.SetLocalPosition(vector.Vector{0, 0, -10})
+
+	sphere := NewBoundingSphere("sphere", 1)
+	sphere.SetLocalPosition(vector.Vector{0, 0, -5})
+
+	box := NewBoundingAABB("box", 2, 2, 2)
+	box.SetLocalPosition(vector.Vector{5, 0, -5})
+
+	tests := []struct {
+		name      string
+		origin    vector.Vector
+		direction vector.Vector
+		nodes     []INode
+		hits      []INode
+		distance  float64
+		normal    vector.Vector
+	}{
+		{"nearest first", vector.Vector{0, 0, 0}, vector.Vector{0, 0, -1}, []INode{cube, sphere}, []INode{sphere, cube}, 4, vector.Vector{0, 0, 1}},
+		{"model", vector.Vector{0, 0, 0}, vector.Vector{0, 0, -1}, []INode{cube}, []INode{cube}, 9, vector.Vector{0, 0, 1}},
+		{"model from side", vector.Vector{-10, 0, -10}, vector.Vector{1, 0, 0}, []INode{cube}, []INode{cube}, 9, vector.Vector{-1, 0, 0}},
+		{"aabb", vector.Vector{5, 10, -5}, vector.Vector{0, -1, 0}, []INode{box, sphere}, []INode{box}, 9, vector.Vector{0, 1, 0}},
+		{"miss", vector.Vector{0, 10, 0}, vector.Vector{0, 1, 0}, []INode{cube, sphere, box}, []INode{}, 0, nil},
+	}
+
+	for _, test := range tests {
+
+		hits := RayTest(test.origin, test.direction, test.nodes...)
+
+		if len(hits) != len(test.hits) {
+			t.Errorf("%s: got %d hits, want %d", test.name, len(hits), len(test.hits))
+			continue
+		}
+
+		for i, hit := range hits {
+			if hit.Node != test.hits[i] {
+				t.Errorf("%s: hit %d is %s, want %s", test.name, i, hit.Node.Name(), test.hits[i].Name())
+			}
+		}
+
+		if len(hits) > 0 {
+			if math.Abs(hits[0].Distance-test.distance) > testEpsilon {
+				t.Errorf("%s: distance = %f, want %f", test.name, hits[0].Distance, test.distance)
+			}
+			if !vectorsEqual(hits[0].Normal, test.normal) {
+				t.Errorf("%s: normal = %v, want %v", test.name, hits[0].Normal, test.normal)
+			}
+		}
+
+	}
+
+}
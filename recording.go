@@ -0,0 +1,233 @@
+package tetra3d
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// cameraRecorder captures a Camera's rendered frames, either into an animated GIF or as a sequence of PNG images.
+type cameraRecorder struct {
+	path     string
+	gif      bool
+	interval time.Duration
+
+	scaled      *ebiten.Image
+	width       int
+	height      int
+	frameCount  int
+	lastCapture time.Time
+
+	frames       []*image.Paletted
+	captureTimes []time.Time
+
+	err error
+}
+
+// StartRecording starts recording the Camera's rendered output at the number of frames per second provided. If the path ends with ".gif",
+// the recording is saved as an animated GIF when StopRecording() is called. Otherwise, the path is treated as a directory (which is created
+// if it doesn't exist), and each frame is saved into it as a PNG image as it's captured (frame00000.png, frame00001.png, and so on).
+//
+// Frames are captured when Camera.Clear() is called (so the Camera's finished render from the previous frame is recorded), and are paced
+// by the wall clock, so frames are skipped if the game runs faster than the recording's frame rate, and GIF frame delays reflect the real
+// time between captures. The recording's frame size is the size of the Camera's color texture when StartRecording() is called, scaled by
+// Camera.RecordingScale (which keeps file sizes and capture times down); if the color texture's size changes while recording (for example,
+// from adaptive resolution), captured frames are stretched to fit the recording's frame size.
+// GIF frames are reduced to a 256-color palette, and as GIFs don't support partial transparency, transparent areas of the Camera's color
+// texture are recorded as black. Recording is meant for sharing development clips, and capturing frames is slow, so don't leave it on.
+func (camera *Camera) StartRecording(path string, fps int) error {
+
+	if camera.recorder != nil {
+		return errors.New("camera is already recording")
+	}
+
+	if fps <= 0 {
+		return errors.New("recording frame rate must be above 0")
+	}
+
+	scale := camera.RecordingScale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	w, h := camera.ColorTexture().Size()
+
+	recorder := &cameraRecorder{
+		path:     path,
+		gif:      strings.EqualFold(filepath.Ext(path), ".gif"),
+		interval: time.Second / time.Duration(fps),
+		width:    int(math.Max(math.Round(float64(w)*scale), 1)),
+		height:   int(math.Max(math.Round(float64(h)*scale), 1)),
+	}
+
+	if !recorder.gif {
+		if err := os.MkdirAll(path, 0755); err != nil {
+			return err
+		}
+	}
+
+	camera.recorder = recorder
+
+	return nil
+
+}
+
+// StopRecording stops recording the Camera's output. If recording to a GIF, the GIF is saved at this point. StopRecording returns any error
+// that occurred while recording or saving.
+func (camera *Camera) StopRecording() error {
+
+	recorder := camera.recorder
+
+	if recorder == nil {
+		return errors.New("camera is not recording")
+	}
+
+	camera.recorder = nil
+
+	if recorder.scaled != nil {
+		recorder.scaled.Dispose()
+	}
+
+	if recorder.err != nil {
+		return recorder.err
+	}
+
+	if recorder.gif {
+		return recorder.saveGIF()
+	}
+
+	return nil
+
+}
+
+// IsRecording returns if the Camera is currently recording its output (i.e. Camera.StartRecording() has been called without a
+// following call to Camera.StopRecording()).
+func (camera *Camera) IsRecording() bool {
+	return camera.recorder != nil
+}
+
+// capture records the texture provided as a frame, scaled to the recording's frame size, if enough time has passed since the previous
+// frame was captured.
+func (recorder *cameraRecorder) capture(texture *ebiten.Image) {
+
+	if recorder.err != nil {
+		return
+	}
+
+	now := time.Now()
+
+	if recorder.frameCount > 0 && now.Sub(recorder.lastCapture) < recorder.interval {
+		return
+	}
+
+	recorder.lastCapture = now
+
+	if recorder.scaled == nil {
+		recorder.scaled = ebiten.NewImage(recorder.width, recorder.height)
+	}
+
+	// The texture's size can change (for example, from adaptive resolution), but every frame of a GIF has to be the same size, so we
+	// always scale it to fit the size set when recording started.
+	srcW, srcH := texture.Size()
+	w, h := recorder.width, recorder.height
+	recorder.scaled.Clear()
+	opt := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	opt.GeoM.Scale(float64(w)/float64(srcW), float64(h)/float64(srcH))
+	recorder.scaled.DrawImage(texture, opt)
+
+	frame := ImageFromTexture(recorder.scaled)
+
+	if recorder.gif {
+
+		paletted := image.NewPaletted(frame.Bounds(), palette.Plan9)
+
+		// Composite the frame onto black first, as the palette doesn't have any transparent colors.
+		opaque := image.NewRGBA(frame.Bounds())
+		draw.Draw(opaque, opaque.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+		draw.Draw(opaque, opaque.Bounds(), frame, image.Point{}, draw.Over)
+
+		draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), opaque, image.Point{})
+
+		recorder.frames = append(recorder.frames, paletted)
+		recorder.captureTimes = append(recorder.captureTimes, now)
+
+	} else {
+
+		file, err := os.Create(filepath.Join(recorder.path, fmt.Sprintf("frame%05d.png", recorder.frameCount)))
+		if err != nil {
+			recorder.err = err
+			return
+		}
+
+		err = png.Encode(file, frame)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err != nil {
+			recorder.err = err
+			return
+		}
+
+	}
+
+	recorder.frameCount++
+
+}
+
+// saveGIF saves the captured frames to the recorder's path as an animated GIF.
+func (recorder *cameraRecorder) saveGIF() error {
+
+	if len(recorder.frames) == 0 {
+		return errors.New("no frames were recorded")
+	}
+
+	anim := &gif.GIF{
+		Image: recorder.frames,
+		Delay: make([]int, len(recorder.frames)),
+	}
+
+	// GIF delays are in hundredths of a second; we track the total elapsed time so that rounding errors don't accumulate.
+	start := recorder.captureTimes[0]
+	elapsed := 0
+
+	for i := range recorder.frames {
+
+		var end time.Duration
+		if i < len(recorder.frames)-1 {
+			end = recorder.captureTimes[i+1].Sub(start)
+		} else {
+			end = recorder.captureTimes[i].Sub(start) + recorder.interval
+		}
+
+		total := int(math.Round(end.Seconds() * 100))
+		anim.Delay[i] = int(math.Max(float64(total-elapsed), 1))
+		elapsed += anim.Delay[i]
+
+	}
+
+	file, err := os.Create(recorder.path)
+	if err != nil {
+		return err
+	}
+
+	if err := gif.EncodeAll(file, anim); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+
+}
@@ -0,0 +1,195 @@
+package tetra3d
+
+import (
+	"image"
+	"math"
+)
+
+// ReflectionProbe is a Node that captures a low-resolution cubemap of its surroundings, which Models within its Radius whose Materials have
+// a Reflectivity above 0 reflect. This gives plausible local reflections (for example, in interiors), without rendering the Scene again for
+// each reflective Model. Each Model reflects the nearest ReflectionProbe whose Radius it's within. As Tetra3D lights per-vertex, reflections
+// are also calculated per-vertex, so they're best suited to glossy or low-detail surfaces rather than mirrors.
+//
+// A ReflectionProbe captures its surroundings the first time a Scene containing it is rendered. As capturing renders the Scene six times, it's
+// relatively expensive, so to update it afterwards (for example, after moving it or its surroundings), call ReflectionProbe.Capture() when
+// needed or ReflectionProbe.QueueCapture() to capture it the next time the Scene is rendered.
+type ReflectionProbe struct {
+	*Node
+	Resolution int     // The width and height of each of the six faces of the ReflectionProbe's cubemap, in pixels. Defaults to 16.
+	Radius     float64 // How close a Model must be to the ReflectionProbe to reflect it. Defaults to 10.
+	Near       float64 // The near clipping plane of the ReflectionProbe's Camera when capturing. Defaults to 0.1.
+	Far        float64 // The far clipping plane of the ReflectionProbe's Camera when capturing. Defaults to 100.
+
+	camera        *Camera
+	faces         [6]*image.RGBA
+	faceRights    [6]Vector3
+	faceUps       [6]Vector3
+	faceLooks     [6]Vector3
+	captureNeeded bool
+}
+
+// reflectionProbeFaceRotations are the rotations of the Camera capturing each face of a ReflectionProbe's cubemap. As Cameras look down -Z,
+// these face -Z, +Z, -X, +X, +Y, and -Y.
+var reflectionProbeFaceRotations = [6]Matrix4{
+	NewMatrix4(),
+	NewMatrix4Rotate(0, 1, 0, math.Pi),
+	NewMatrix4Rotate(0, 1, 0, math.Pi/2),
+	NewMatrix4Rotate(0, 1, 0, -math.Pi/2),
+	NewMatrix4Rotate(1, 0, 0, math.Pi/2),
+	NewMatrix4Rotate(1, 0, 0, -math.Pi/2),
+}
+
+// NewReflectionProbe returns a new ReflectionProbe with the name provided.
+func NewReflectionProbe(name string) *ReflectionProbe {
+
+	probe := &ReflectionProbe{
+		Node:          NewNode(name),
+		Resolution:    16,
+		Radius:        10,
+		Near:          0.1,
+		Far:           100,
+		captureNeeded: true,
+	}
+
+	for i, rotation := range reflectionProbeFaceRotations {
+		probe.faceRights[i] = NewVector3FromVector(rotation.Right())
+		probe.faceUps[i] = NewVector3FromVector(rotation.Up())
+		probe.faceLooks[i] = NewVector3FromVector(rotation.Forward()).Invert()
+	}
+
+	return probe
+
+}
+
+// Clone returns a clone of the ReflectionProbe. The clone doesn't share the original's captured cubemap, and so captures its own the first
+// time a Scene containing it is rendered.
+func (probe *ReflectionProbe) Clone() INode {
+
+	clone := NewReflectionProbe(probe.name)
+	clone.Resolution = probe.Resolution
+	clone.Radius = probe.Radius
+	clone.Near = probe.Near
+	clone.Far = probe.Far
+
+	clone.Node = probe.Node.Clone().(*Node)
+	for _, child := range clone.children {
+		child.setParent(clone)
+	}
+
+	return clone
+
+}
+
+// Capture renders the Scene provided from the ReflectionProbe's world position into its cubemap. As this renders the Scene six times and
+// reads the results back from the GPU, it's relatively slow, and so shouldn't be called every frame. Like ebiten.Image.At(), Capture can
+// only be called from within Ebiten's game loop.
+func (probe *ReflectionProbe) Capture(scene *Scene) {
+
+	probe.captureNeeded = false
+
+	if probe.Resolution <= 0 {
+		panic("error: ReflectionProbe resolution must be above 0")
+	}
+
+	if probe.camera == nil {
+		probe.camera = NewCamera(probe.Resolution, probe.Resolution)
+		probe.camera.SetPerspective(90)
+	} else if w, _ := probe.camera.ColorTexture().Size(); w != probe.Resolution {
+		probe.camera.Resize(probe.Resolution, probe.Resolution)
+	}
+
+	camera := probe.camera
+	camera.Near = probe.Near
+	camera.Far = probe.Far
+	camera.SetWorldPosition(probe.WorldPosition())
+
+	// The faces are only replaced once they've all been captured, as reflective Models rendered while capturing can reflect this probe.
+	faces := [6]*image.RGBA{}
+
+	for i, rotation := range reflectionProbeFaceRotations {
+
+		camera.SetWorldRotation(rotation)
+		camera.Clear()
+		camera.resultColorTexture.Fill(scene.world().ClearColor.ToRGBA64())
+		camera.RenderNodes(scene, scene.Root)
+
+		faces[i] = ImageFromTexture(camera.ColorTexture())
+
+	}
+
+	probe.faces = faces
+
+}
+
+// QueueCapture queues the ReflectionProbe to capture its surroundings the next time a Scene containing it is rendered.
+func (probe *ReflectionProbe) QueueCapture() {
+	probe.captureNeeded = true
+}
+
+// Captured returns if the ReflectionProbe has captured its surroundings.
+func (probe *ReflectionProbe) Captured() bool {
+	return probe.faces[0] != nil
+}
+
+// sample returns the color of the ReflectionProbe's cubemap in the world-space direction provided.
+func (probe *ReflectionProbe) sample(dir Vector3) (r, g, b float32) {
+
+	face := 0
+	look := dir.Dot(probe.faceLooks[0])
+
+	for i := 1; i < len(probe.faceLooks); i++ {
+		if d := dir.Dot(probe.faceLooks[i]); d > look {
+			face = i
+			look = d
+		}
+	}
+
+	if look <= 0 {
+		return 0, 0, 0
+	}
+
+	img := probe.faces[face]
+	size := img.Bounds().Dx()
+
+	// As each face is captured with a 90 degree field of view, the direction's position on the face ranges from -1 to 1 on each axis.
+	x := dir.Dot(probe.faceRights[face]) / look
+	y := dir.Dot(probe.faceUps[face]) / look
+
+	px := int((x*0.5 + 0.5) * float64(size))
+	py := int((0.5 - y*0.5) * float64(size))
+
+	if px < 0 {
+		px = 0
+	} else if px >= size {
+		px = size - 1
+	}
+
+	if py < 0 {
+		py = 0
+	} else if py >= size {
+		py = size - 1
+	}
+
+	c := img.RGBAAt(px, py)
+
+	return float32(c.R) / 255, float32(c.G) / 255, float32(c.B) / 255
+
+}
+
+// AddChildren parents the provided children Nodes to the passed parent Node, inheriting its transformations and being under it in the scenegraph
+// hierarchy. If the children are already parented to other Nodes, they are unparented before doing so.
+func (probe *ReflectionProbe) AddChildren(children ...INode) {
+	probe.addChildren(probe, children...)
+}
+
+// Unparent unparents the ReflectionProbe from its parent, removing it from the scenegraph.
+func (probe *ReflectionProbe) Unparent() {
+	if probe.parent != nil {
+		probe.parent.RemoveChildren(probe)
+	}
+}
+
+// Type returns the NodeType for this object.
+func (probe *ReflectionProbe) Type() NodeType {
+	return NodeTypeReflectionProbe
+}
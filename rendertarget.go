@@ -0,0 +1,197 @@
+package tetra3d
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// cameraTextures is a set of the textures a Camera renders with, all of the same size, along with the picking state that goes with them.
+type cameraTextures struct {
+	color                 *ebiten.Image
+	accumulatedColor      *ebiten.Image
+	accumulatedBackBuffer *ebiten.Image
+	depth                 *ebiten.Image
+	colorIntermediate     *ebiten.Image
+	depthIntermediate     *ebiten.Image
+	clipAlphaIntermediate *ebiten.Image
+	clipBehind            *ebiten.Image
+	picking               *ebiten.Image
+	pickingNodes          []INode
+	pickingIDs            map[INode]int
+
+	ambientOcclusion      *ebiten.Image
+	colorGrading          *ebiten.Image
+	colorGradingLUT       *ebiten.Image
+	colorGradingLUTSource *ebiten.Image
+}
+
+// renderTarget is a render target the Camera has been pointed at with Camera.PushRenderTarget(), along with the state to restore afterwards.
+type renderTarget struct {
+	target      *ebiten.Image
+	previous    cameraTextures
+	renderScale float64
+}
+
+// textures returns the set of textures the Camera is currently rendering with.
+func (camera *Camera) textures() cameraTextures {
+	return cameraTextures{
+		color:                 camera.resultColorTexture,
+		accumulatedColor:      camera.resultAccumulatedColorTexture,
+		accumulatedBackBuffer: camera.accumulatedBackBuffer,
+		depth:                 camera.resultDepthTexture,
+		colorIntermediate:     camera.colorIntermediate,
+		depthIntermediate:     camera.depthIntermediate,
+		clipAlphaIntermediate: camera.clipAlphaIntermediate,
+		clipBehind:            camera.clipBehind,
+		picking:               camera.pickingTexture,
+		pickingNodes:          camera.pickingNodes,
+		pickingIDs:            camera.pickingIDs,
+		ambientOcclusion:      camera.AmbientOcclusion.texture,
+		colorGrading:          camera.ColorGrading.texture,
+		colorGradingLUT:       camera.ColorGrading.lutTexture,
+		colorGradingLUTSource: camera.ColorGrading.lutSource,
+	}
+}
+
+// setTextures sets the Camera to render with the set of textures provided.
+func (camera *Camera) setTextures(textures cameraTextures) {
+	camera.resultColorTexture = textures.color
+	camera.resultAccumulatedColorTexture = textures.accumulatedColor
+	camera.accumulatedBackBuffer = textures.accumulatedBackBuffer
+	camera.resultDepthTexture = textures.depth
+	camera.colorIntermediate = textures.colorIntermediate
+	camera.depthIntermediate = textures.depthIntermediate
+	camera.clipAlphaIntermediate = textures.clipAlphaIntermediate
+	camera.clipBehind = textures.clipBehind
+	camera.pickingTexture = textures.picking
+	camera.pickingNodes = textures.pickingNodes
+	camera.pickingIDs = textures.pickingIDs
+	camera.AmbientOcclusion.texture = textures.ambientOcclusion
+	camera.ColorGrading.texture = textures.colorGrading
+	camera.ColorGrading.lutTexture = textures.colorGradingLUT
+	camera.ColorGrading.lutSource = textures.colorGradingLUTSource
+	camera.sphereFactorCalculated = false
+}
+
+// PushRenderTarget points the Camera at the target image provided, which can be of any size. Until the target is popped with
+// Camera.PopRenderTarget(), the Camera renders at the target's size (regardless of its render scale), and its ColorTexture(), DepthTexture(),
+// and picking all refer to the target's render. This is useful for rendering UI portraits, thumbnails, or reflections with the same Camera
+// (and settings) as the main view within a single frame. The textures the Camera renders with for each target size are created the first time
+// that size is pushed and kept afterwards, so retargeting every frame doesn't allocate new textures; to free them, call
+// Camera.DisposeRenderTargets(). Render targets can be nested, with each pop restoring the previous target. Note that if the Camera is
+// resized (or its render scale is set) while it has a render target, the change is applied once the last target is popped, and that its
+// adaptive resolution and recording are paused.
+func (camera *Camera) PushRenderTarget(target *ebiten.Image) {
+
+	if target == nil {
+		panic("error: Camera.PushRenderTarget() called with a nil target")
+	}
+
+	size := target.Bounds().Size()
+
+	camera.renderTargets = append(camera.renderTargets, renderTarget{
+		target:      target,
+		previous:    camera.textures(),
+		renderScale: camera.renderScale,
+	})
+
+	if camera.renderTargetTextures == nil {
+		camera.renderTargetTextures = map[image.Point]cameraTextures{}
+	}
+
+	textures, exists := camera.renderTargetTextures[size]
+
+	if !exists {
+		textures = cameraTextures{
+			color:                 ebiten.NewImage(size.X, size.Y),
+			accumulatedColor:      ebiten.NewImage(size.X, size.Y),
+			accumulatedBackBuffer: ebiten.NewImage(size.X, size.Y),
+			depth:                 ebiten.NewImage(size.X, size.Y),
+			colorIntermediate:     ebiten.NewImage(size.X, size.Y),
+			depthIntermediate:     ebiten.NewImage(size.X, size.Y),
+			clipAlphaIntermediate: ebiten.NewImage(size.X, size.Y),
+			clipBehind:            ebiten.NewImage(size.X, size.Y),
+		}
+	}
+
+	// While a set of textures is in use, it's removed from the cache so that nested targets of the same size don't share it.
+	delete(camera.renderTargetTextures, size)
+
+	camera.setTextures(textures)
+	camera.renderScale = 1
+	camera.AmbientOcclusion.dirty = true
+	camera.ColorGrading.dirty = true
+
+}
+
+// PopRenderTarget draws the Camera's ColorTexture() into the current render target (as set with Camera.PushRenderTarget()), replacing its
+// contents, and then restores the Camera to the render target it had before. This should be called after rendering to the target.
+func (camera *Camera) PopRenderTarget() {
+
+	if len(camera.renderTargets) == 0 {
+		panic("error: Camera.PopRenderTarget() called without a render target to pop")
+	}
+
+	last := camera.renderTargets[len(camera.renderTargets)-1]
+	camera.renderTargets = camera.renderTargets[:len(camera.renderTargets)-1]
+
+	last.target.DrawImage(camera.ColorTexture(), &ebiten.DrawImageOptions{CompositeMode: ebiten.CompositeModeCopy})
+
+	camera.renderTargetTextures[last.target.Bounds().Size()] = camera.textures()
+
+	camera.setTextures(last.previous)
+	camera.renderScale = last.renderScale
+
+	if len(camera.renderTargets) == 0 && camera.resizePending {
+		camera.resizePending = false
+		camera.resizeTextures(int(float64(camera.baseWidth)*camera.renderScale), int(float64(camera.baseHeight)*camera.renderScale))
+	}
+
+	// Post-processing is tracked for the Camera as a whole rather than for each set of textures, so it's redone for the restored set.
+	camera.AmbientOcclusion.dirty = true
+	camera.ColorGrading.dirty = true
+
+}
+
+// RenderTarget returns the render target the Camera is currently pointed at with Camera.PushRenderTarget(), or nil if it isn't pointed at one.
+func (camera *Camera) RenderTarget() *ebiten.Image {
+	if len(camera.renderTargets) == 0 {
+		return nil
+	}
+	return camera.renderTargets[len(camera.renderTargets)-1].target
+}
+
+// DisposeRenderTargets disposes of the textures the Camera has created for rendering to render targets that aren't currently in use.
+func (camera *Camera) DisposeRenderTargets() {
+
+	for size, textures := range camera.renderTargetTextures {
+		textures.dispose()
+		delete(camera.renderTargetTextures, size)
+	}
+
+}
+
+// dispose disposes of all of the textures in the set.
+func (textures cameraTextures) dispose() {
+
+	for _, texture := range []*ebiten.Image{
+		textures.color,
+		textures.accumulatedColor,
+		textures.accumulatedBackBuffer,
+		textures.depth,
+		textures.colorIntermediate,
+		textures.depthIntermediate,
+		textures.clipAlphaIntermediate,
+		textures.clipBehind,
+		textures.picking,
+		textures.ambientOcclusion,
+		textures.colorGrading,
+		textures.colorGradingLUT,
+	} {
+		if texture != nil {
+			texture.Dispose()
+		}
+	}
+
+}
@@ -0,0 +1,82 @@
+package tetra3d
+
+import (
+	"errors"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var errRunOnceFinished = errors.New("run once finished")
+
+type runOnceGame struct {
+	run      func() error
+	err      error
+	finished bool
+}
+
+func (game *runOnceGame) Update() error {
+	if !game.finished {
+		game.finished = true
+		game.err = game.run()
+	}
+	return errRunOnceFinished
+}
+
+func (game *runOnceGame) Draw(screen *ebiten.Image) {}
+
+func (game *runOnceGame) Layout(w, h int) (int, int) {
+	return 1, 1
+}
+
+// RunOnce allows you to render scenes without running your own game loop, which is useful for golden-image tests, generating thumbnails,
+// or batch rendering. Ebiten can only create, draw to, and read back images from within its game loop, so RunOnce starts a minimal
+// game loop, calls the function provided once from within it, and then stops the loop, returning any error that the function returned.
+// Within the function, you can create Cameras, render scenes, and read back the results as image.Images with ImageFromTexture(). For example:
+//
+//	tetra3d.RunOnce(func() error {
+//		camera := tetra3d.NewCamera(320, 180)
+//		camera.Clear()
+//		camera.RenderNodes(scene, scene.Root)
+//		return png.Encode(file, tetra3d.ImageFromTexture(camera.ColorTexture()))
+//	})
+//
+// Note that this is not truly headless: Ebiten can't run its game loop without a window, so RunOnce opens a tiny (1x1), undecorated,
+// unfocused window for the duration of the call. This means a display (or a virtual framebuffer, like Xvfb on Linux) is required, even on
+// CI servers. Because Ebiten can't run its game loop more than once per process, RunOnce can only be called once per process (and not
+// alongside ebiten.RunGame()), so do all of your rendering within a single call (in tests, call it from TestMain and run the tests within it).
+func RunOnce(run func() error) error {
+
+	ebiten.SetWindowSize(1, 1)
+	ebiten.SetWindowDecorated(false)
+	ebiten.SetInitFocused(false)
+	ebiten.SetRunnableOnUnfocused(true)
+
+	game := &runOnceGame{run: run}
+
+	if err := ebiten.RunGame(game); err != nil && err != errRunOnceFinished {
+		return err
+	}
+
+	return game.err
+
+}
+
+// ImageFromTexture reads the pixels of the provided *ebiten.Image (like a Camera's ColorTexture() or DepthTexture()) back from the GPU and
+// returns them as a new *image.RGBA, suitable for saving to disk or comparing against a reference image. Like ebiten.Image.At(), this can only
+// be called from within Ebiten's game loop (or from within RunOnce()), and it's slow, so it shouldn't be called every frame.
+func ImageFromTexture(texture *ebiten.Image) *image.RGBA {
+
+	bounds := texture.Bounds()
+	result := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			result.SetRGBA(x, y, texture.At(bounds.Min.X+x, bounds.Min.Y+y).(color.RGBA))
+		}
+	}
+
+	return result
+
+}
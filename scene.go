@@ -1,5 +1,10 @@
 package tetra3d
 
+import (
+	"sync"
+	"sync/atomic"
+)
+
 const (
 	FogOff       = iota // No fog
 	FogAdd              // Additive blended fog
@@ -11,6 +16,11 @@ type FogMode int
 
 // Scene represents a world of sorts, and can contain a variety of Meshes and Nodes, which organize the scene into a
 // graph of parents and children. Models (visual instances of Meshes), Cameras, and "empty" NodeBases all are kinds of Nodes.
+//
+// Scenes aren't safe to modify from multiple goroutines at once. Instead, Scenes are owned by the goroutine that updates and renders them (usually
+// Ebiten's game loop), and other goroutines (like loaders, AI, or networking) should use Scene.Queue() to queue changes (like adding, moving,
+// or removing Nodes), which are then made on the owning goroutine when it calls Scene.Update() or Scene.ProcessQueue(). Nodes that aren't
+// in a Scene yet (for example, a freshly loaded or cloned tree) can be freely built up on any one goroutine before being queued to be added.
 type Scene struct {
 	Name    string   // The name of the Scene. Set automatically to the scene name in your 3D modeler if the DAE file exports it.
 	library *Library // The library from which this Scene was created. If the Scene was instantiated through code, this will be nil.
@@ -18,29 +28,42 @@ type Scene struct {
 	// scene graph by simply adding them into the tree via parenting anywhere under the Root. For them to be removed from rendering,
 	// they simply need to be removed from the tree.
 	// See this page for more information on how a scene graph works: https://webglfundamentals.org/webgl/lessons/webgl-scene-graph.html
-	Root       INode
-	ClearColor *Color // The clear color of the screen; note that this doesn't clear the color of the camera buffer or screen automatically;
-	// this is just what the color is if the scene was exported using the Tetra3D addon from Blender. It's up to you as to how you'd like to
-	// use it.
-	FogColor *Color  // The Color of any fog present in the Scene.
-	FogMode  FogMode // The FogMode, indicating how the fog color is blended if it's on (not FogOff).
-	// FogRange is the depth range at which the fog is active. FogRange consists of two numbers,
-	// ranging from 0 to 1. The first indicates the start of the fog, and the second the end, in
-	// terms of total depth of the near / far clipping plane. The default is [0, 1].
-	FogRange   []float32
-	LightingOn bool // If lighting is enabled when rendering the scene.
+	Root INode
+	// World holds the Scene's environmental settings, like its clear color, fog, and ambient lighting. It can be swapped out
+	// at runtime to change all of them at once. If it's nil, the Scene is rendered with the default World settings.
+	// Note that this is a breaking change: these settings used to be the Scene.ClearColor, Scene.FogColor, Scene.FogMode, Scene.FogRange, and
+	// Scene.LightingOn fields, which have been removed. Code using those fields needs to be updated to use the World (or the deprecated
+	// Scene methods of the same names, which read and write the World's settings).
+	World *World
+
+	// UpdateWorkers is how many goroutines Scene.Update() spreads the work of updating the Scene's Nodes across. If above 1, each of the Root's
+	// children (and its subtree) is updated independently on one of the workers, so this is a good fit for Scenes with many independent,
+	// animated characters or props. Defaults to 1, which updates the Scene on the calling goroutine.
+	UpdateWorkers int
+
+	// OnNodeUpdate, if set, is called by Scene.Update() for each Node in the Scene after its AnimationPlayer is updated. If the Scene has more than
+	// one UpdateWorker, OnNodeUpdate is called from multiple goroutines at once, so it should only modify the Node passed and its subtree.
+	OnNodeUpdate func(node INode, dt float64)
+
+	// Octree holds settings for the Scene's octree, a spatial partition that speeds up frustum culling large Scenes when rendering them with
+	// Camera.RenderNodes(). It's off by default.
+	Octree Octree
+
+	queueLock sync.Mutex
+	queue     []func()
+	running   []func()
+	updating  int32 // Set to 1 while the Scene is being updated; it's accessed atomically, as it's checked from Scene.Update()'s workers.
 }
 
 // NewScene creates a new Scene by the name given.
 func NewScene(name string) *Scene {
 
 	scene := &Scene{
-		Name:       name,
-		Root:       NewNode("Root"),
-		FogColor:   NewColor(0, 0, 0, 0),
-		FogRange:   []float32{0, 1},
-		LightingOn: true,
-		ClearColor: NewColor(0.08, 0.09, 0.1, 1),
+		Name:          name,
+		Root:          NewNode("Root"),
+		World:         NewWorld(name),
+		UpdateWorkers: 1,
+		Octree:        newOctree(),
 	}
 
 	scene.Root.(*Node).scene = scene
@@ -48,45 +71,272 @@ func NewScene(name string) *Scene {
 	return scene
 }
 
-// Clone clones the Scene, returning a copy. Models and Meshes are shared between them.
+// Clone clones the Scene, returning a copy. Models and Meshes are shared between them, while the Scene's World is cloned.
 func (scene *Scene) Clone() *Scene {
 
 	newScene := NewScene(scene.Name)
 	newScene.library = scene.library
-	newScene.LightingOn = scene.LightingOn
 
 	// newScene.Models = append(newScene.Models, scene.Models...)
 	newScene.Root = scene.Root.Clone()
 	newScene.Root.(*Node).scene = newScene
 
-	newScene.FogColor = scene.FogColor.Clone()
-	newScene.FogMode = scene.FogMode
-	newScene.FogRange[0] = scene.FogRange[0]
-	newScene.FogRange[1] = scene.FogRange[1]
-	newScene.ClearColor = scene.ClearColor.Clone()
+	if scene.World != nil {
+		newScene.World = scene.World.Clone()
+	} else {
+		newScene.World = nil
+	}
+
+	newScene.UpdateWorkers = scene.UpdateWorkers
+	newScene.OnNodeUpdate = scene.OnNodeUpdate
+
+	newScene.Octree.On = scene.Octree.On
+	newScene.Octree.MaxDepth = scene.Octree.MaxDepth
+
 	return newScene
 
 }
 
-func (scene *Scene) fogAsFloatSlice() []float32 {
-
-	fog := []float32{
-		float32(scene.FogColor.R),
-		float32(scene.FogColor.G),
-		float32(scene.FogColor.B),
-		float32(scene.FogMode),
+// world returns the Scene's World, or a World with the default settings if the Scene's World is nil.
+func (scene *Scene) world() *World {
+	if scene.World == nil {
+		return defaultWorld
 	}
+	return scene.World
+}
 
-	if scene.FogMode == FogMultiply {
-		fog[0] = 1 - fog[0]
-		fog[1] = 1 - fog[1]
-		fog[2] = 1 - fog[2]
+// ensureWorld returns the Scene's World, creating one with the default settings first if the Scene's World is nil.
+func (scene *Scene) ensureWorld() *World {
+	if scene.World == nil {
+		scene.World = NewWorld(scene.Name)
 	}
+	return scene.World
+}
+
+// ClearColor returns the clear color of the Scene's World. If the Scene has no World, the default clear color is returned, and shouldn't be altered.
+//
+// Deprecated: Use Scene.World.ClearColor instead.
+func (scene *Scene) ClearColor() *Color {
+	return scene.world().ClearColor
+}
+
+// SetClearColor sets the clear color of the Scene's World.
+//
+// Deprecated: Use Scene.World.ClearColor instead.
+func (scene *Scene) SetClearColor(color *Color) {
+	scene.ensureWorld().ClearColor = color
+}
+
+// FogColor returns the fog color of the Scene's World. If the Scene has no World, the default fog color is returned, and shouldn't be altered.
+//
+// Deprecated: Use Scene.World.FogColor instead.
+func (scene *Scene) FogColor() *Color {
+	return scene.world().FogColor
+}
+
+// SetFogColor sets the fog color of the Scene's World.
+//
+// Deprecated: Use Scene.World.FogColor instead.
+func (scene *Scene) SetFogColor(color *Color) {
+	scene.ensureWorld().FogColor = color
+}
+
+// FogMode returns the FogMode of the Scene's World.
+//
+// Deprecated: Use Scene.World.FogMode instead.
+func (scene *Scene) FogMode() FogMode {
+	return scene.world().FogMode
+}
 
-	return fog
+// SetFogMode sets the FogMode of the Scene's World.
+//
+// Deprecated: Use Scene.World.FogMode instead.
+func (scene *Scene) SetFogMode(mode FogMode) {
+	scene.ensureWorld().FogMode = mode
+}
+
+// FogRange returns the fog range of the Scene's World. If the Scene has no World, the default fog range is returned, and shouldn't be altered.
+//
+// Deprecated: Use Scene.World.FogRange instead.
+func (scene *Scene) FogRange() []float32 {
+	return scene.world().FogRange
+}
+
+// SetFogRange sets the fog range of the Scene's World.
+//
+// Deprecated: Use Scene.World.FogRange instead.
+func (scene *Scene) SetFogRange(start, end float32) {
+	world := scene.ensureWorld()
+	world.FogRange[0] = start
+	world.FogRange[1] = end
+}
+
+// LightingOn returns if lighting is enabled in the Scene's World.
+//
+// Deprecated: Use Scene.World.LightingOn instead.
+func (scene *Scene) LightingOn() bool {
+	return scene.world().LightingOn
+}
+
+// SetLightingOn sets if lighting is enabled in the Scene's World.
+//
+// Deprecated: Use Scene.World.LightingOn instead.
+func (scene *Scene) SetLightingOn(on bool) {
+	scene.ensureWorld().LightingOn = on
 }
 
 // Library returns the Library from which this Scene was loaded. If it was created through code and not associated with a Library, this function will return nil.
 func (scene *Scene) Library() *Library {
 	return scene.library
 }
+
+// Queue queues the command provided to be run the next time Scene.Update() or Scene.ProcessQueue() is called on the goroutine that owns the Scene,
+// in the order they were queued. Unlike other Scene and Node functions, Queue is safe to call from any goroutine, making it the way to spawn,
+// move, or remove Nodes from background goroutines.
+func (scene *Scene) Queue(command func()) {
+	scene.queueLock.Lock()
+	scene.queue = append(scene.queue, command)
+	scene.queueLock.Unlock()
+}
+
+// ProcessQueue runs the commands queued with Scene.Queue(). Commands queued while processing the queue are run the next time it's processed.
+// ProcessQueue is called automatically by Scene.Update(), so you only need to call it if you're not using Scene.Update().
+func (scene *Scene) ProcessQueue() {
+
+	scene.queueLock.Lock()
+	scene.queue, scene.running = scene.running[:0], scene.queue
+	scene.queueLock.Unlock()
+
+	for i, command := range scene.running {
+		command()
+		scene.running[i] = nil
+	}
+
+}
+
+// updatingScenes is the number of Scenes currently being updated with Scene.Update(); it's used to quickly skip checking if a Node is being
+// added to or removed from an updating Scene.
+var updatingScenes int32
+
+// Update processes the Scene's queued commands (see Scene.Queue()), and then updates the Scene's Nodes by the delta provided in seconds,
+// advancing the AnimationPlayer of each Node that's playing and calling Scene.OnNodeUpdate for each Node, if it's set. As the Scene's Nodes may
+// be updated in parallel (see Scene.UpdateWorkers), Nodes can't be added to or removed from the Scene while it's updating (other than by queueing
+// commands), and an AnimationPlayer should only animate Nodes in its own subtree. Note that animation callbacks (like AnimationPlayer.OnFinish)
+// and profiling hooks (like OnStageBegin) may be called from multiple goroutines at once when updating in parallel.
+func (scene *Scene) Update(dt float64) {
+
+	scene.ProcessQueue()
+
+	atomic.StoreInt32(&scene.updating, 1)
+	atomic.AddInt32(&updatingScenes, 1)
+
+	defer func() {
+		atomic.AddInt32(&updatingScenes, -1)
+		atomic.StoreInt32(&scene.updating, 0)
+	}()
+
+	children := rawChildren(scene.Root)
+
+	workers := scene.UpdateWorkers
+	if workers > len(children) {
+		workers = len(children)
+	}
+
+	if workers <= 1 {
+		scene.updateNodeRecursive(scene.Root, dt)
+		return
+	}
+
+	scene.updateNode(scene.Root, dt)
+
+	// Each subtree is updated independently, so anything the workers could share (the Root's transform and subtree bounds, as well as the bounds
+	// of Meshes shared between Models) is brought up to date here; otherwise, multiple workers could try to update it at once.
+	resolveSharedState(scene.Root)
+	scene.Root.(nodeInternals).dirtySubtreeBounds()
+
+	next := int64(-1)
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+
+		go func() {
+
+			defer wg.Done()
+
+			for {
+				index := int(atomic.AddInt64(&next, 1))
+				if index >= len(children) {
+					return
+				}
+				scene.updateNodeRecursive(children[index], dt)
+			}
+
+		}()
+
+	}
+
+	wg.Wait()
+
+}
+
+// resolveSharedState resolves the transforms of the Node provided and its subtree, and recalculates the bounds of their Meshes if they're dirty,
+// so that Scene.Update()'s workers don't need to update any of them lazily at the same time.
+func resolveSharedState(node INode) {
+
+	switch n := node.(type) {
+	case *Model:
+		if n.Mesh != nil {
+			n.Mesh.Bounds()
+		}
+	case *BoundingTriangles:
+		if n.Mesh != nil {
+			n.Mesh.Bounds()
+		}
+	}
+
+	node.Transform()
+
+	for _, child := range rawChildren(node) {
+		resolveSharedState(child)
+	}
+
+}
+
+// updateNode updates the Node provided as part of Scene.Update().
+func (scene *Scene) updateNode(node INode, dt float64) {
+
+	if ap := node.AnimationPlayer(); ap.Playing || ap.blending {
+		ap.Update(dt)
+	}
+
+	if scene.OnNodeUpdate != nil {
+		scene.OnNodeUpdate(node, dt)
+	}
+
+}
+
+// updateNodeRecursive updates the Node provided and its subtree as part of Scene.Update().
+func (scene *Scene) updateNodeRecursive(node INode, dt float64) {
+
+	scene.updateNode(node, dt)
+
+	for _, child := range rawChildren(node) {
+		scene.updateNodeRecursive(child, dt)
+	}
+
+}
+
+// panicIfUpdating panics if the Node provided is in a Scene that's currently being updated with Scene.Update(), as adding or removing Nodes
+// then isn't safe.
+func panicIfUpdating(node INode) {
+
+	if atomic.LoadInt32(&updatingScenes) == 0 {
+		return
+	}
+
+	if scene := node.Scene(); scene != nil && atomic.LoadInt32(&scene.updating) == 1 {
+		panic("error: Nodes can't be added to or removed from a Scene while it's updating; use Scene.Queue() instead")
+	}
+
+}
@@ -0,0 +1,174 @@
+package tetra3d
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	TerrainModeNone         = iota // The Material isn't a terrain Material
+	TerrainModeSplatMap            // The Material blends its terrain layers according to the red, green, and blue channels of a splat map texture
+	TerrainModeVertexColors        // The Material blends its terrain layers according to the red, green, and blue channels of the Mesh's vertex colors
+)
+
+// terrainSplatMapShaderSrc blends up to three tiling layers (images 1 - 3) according to the splat map (image 0), which covers the
+// whole terrain. The vertex color holds the vertex lighting, as usual.
+var terrainSplatMapShaderSrc = []byte(
+	`package main
+
+	var Tiling float
+
+	func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+
+		origin, size := imageSrcRegionOnTexture()
+		tiled := origin + fract((texCoord-origin)/size*Tiling)*size
+
+		weights := imageSrc0At(texCoord).rgb
+		total := weights.r + weights.g + weights.b
+		if total > 0 {
+			weights /= total
+		}
+
+		blended := imageSrc1At(tiled).rgb*weights.r + imageSrc2At(tiled).rgb*weights.g + imageSrc3At(tiled).rgb*weights.b
+
+		return vec4(blended*color.rgb, 1)
+
+	}
+	`,
+)
+
+// terrainVertexColorShaderSrc blends up to three tiling layers (images 0 - 2) according to the vertex colors. As the vertex color holds the
+// splat weights, the renderer normalizes them and passes only the first two in the red and green channels (the third is whatever remains),
+// the fog amount in the blue channel, and the vertex lighting's brightness in the alpha channel. Fog is applied after blending and lighting,
+// according to the Fog uniform (the fog color, with the FogMode in the alpha channel, or 0 if the shader shouldn't apply fog).
+var terrainVertexColorShaderSrc = []byte(
+	`package main
+
+	var Tiling float
+	var Fog vec4
+
+	func Fragment(position vec4, texCoord vec2, color vec4) vec4 {
+
+		origin, size := imageSrcRegionOnTexture()
+		tiled := origin + fract((texCoord-origin)/size*Tiling)*size
+
+		weights := vec3(color.r, color.g, max(1-color.r-color.g, 0))
+
+		blended := imageSrc0At(tiled).rgb*weights.r + imageSrc1At(tiled).rgb*weights.g + imageSrc2At(tiled).rgb*weights.b
+		blended *= color.a
+
+		if Fog.a == 1 {
+			blended += Fog.rgb * color.b
+		} else if Fog.a == 2 {
+			blended *= Fog.rgb * color.b
+		}
+
+		return vec4(blended, 1)
+
+	}
+	`,
+)
+
+// SetTerrainSplatMap turns the Material into a terrain Material that blends between up to three tiling texture layers according to the red,
+// green, and blue channels of the splat map provided (i.e. where the splat map is red, the first layer is shown, where it's green, the second
+// layer is shown, and so on). The splat map is mapped across the terrain using the Mesh's UV values, while the layers tile Material.TerrainTiling
+// times across it, so large landscapes don't look like a single stretched texture. The splat map becomes the Material's Texture, and the
+// terrain is rendered through a custom fragment shader (replacing any existing one). Due to a limitation in Ebiten, the splat map and all
+// layers must be the same size. SetTerrainSplatMap returns an error if the terrain shader fails to compile.
+func (material *Material) SetTerrainSplatMap(splatMap *ebiten.Image, layers ...*ebiten.Image) error {
+
+	if splatMap == nil {
+		panic("error: terrain splat map can't be nil")
+	}
+
+	return material.setTerrain(TerrainModeSplatMap, terrainSplatMapShaderSrc, append([]*ebiten.Image{splatMap}, terrainLayers(layers)...))
+
+}
+
+// SetTerrainVertexColors turns the Material into a terrain Material that blends between up to three tiling texture layers according to the
+// red, green, and blue channels of the active vertex color channel of the Mesh (i.e. where vertices are painted red, the first layer is shown,
+// where they're green, the second layer is shown, and so on). The layers tile Material.TerrainTiling times across the Mesh's UV values.
+// The first layer becomes the Material's Texture, and the terrain is rendered through a custom fragment shader (replacing any existing one).
+// As the vertex colors hold the layers' weights, lighting is applied in grayscale (by brightness) to terrain using vertex colors, and
+// the Mesh's vertex alpha shouldn't be used for transparency. Vertices painted black show all layers equally. Due to a limitation in Ebiten, all layers must be the same size.
+// SetTerrainVertexColors returns an error if the terrain shader fails to compile.
+func (material *Material) SetTerrainVertexColors(layers ...*ebiten.Image) error {
+	return material.setTerrain(TerrainModeVertexColors, terrainVertexColorShaderSrc, terrainLayers(layers))
+}
+
+// terrainLayers validates the layers provided, filling any unused layer slots with the last layer provided.
+func terrainLayers(layers []*ebiten.Image) []*ebiten.Image {
+
+	if len(layers) == 0 || len(layers) > 3 {
+		panic("error: terrain Materials must have between 1 and 3 layers")
+	}
+
+	filled := make([]*ebiten.Image, 3)
+
+	for i := range filled {
+		if i < len(layers) {
+			filled[i] = layers[i]
+		} else {
+			filled[i] = layers[len(layers)-1]
+		}
+		if filled[i] == nil {
+			panic("error: terrain layers can't be nil")
+		}
+	}
+
+	return filled
+
+}
+
+func (material *Material) setTerrain(mode int, src []byte, images []*ebiten.Image) error {
+
+	w, h := images[0].Size()
+	for _, img := range images[1:] {
+		if iw, ih := img.Size(); iw != w || ih != h {
+			panic("error: a terrain Material's splat map and layers must all be the same size")
+		}
+	}
+
+	if _, err := material.SetShader(src); err != nil {
+		return err
+	}
+
+	material.TerrainMode = mode
+	material.Texture = images[0]
+	material.FragmentShaderOn = true
+
+	material.FragmentShaderOptions.Images = [4]*ebiten.Image{}
+	copy(material.FragmentShaderOptions.Images[:], images)
+
+	material.updateTerrainUniforms(nil)
+
+	return nil
+
+}
+
+// ClearTerrain turns the Material back into a regular Material, removing its terrain shader.
+func (material *Material) ClearTerrain() {
+
+	if material.TerrainMode == TerrainModeNone {
+		return
+	}
+
+	material.TerrainMode = TerrainModeNone
+	material.DisposeShader()
+	material.FragmentShaderOptions.Images = [4]*ebiten.Image{}
+
+}
+
+// updateTerrainUniforms passes the Material's terrain settings to its terrain shader, along with the fog to apply (as the fog color followed
+// by the FogMode) for terrain using vertex colors; nil means no fog.
+func (material *Material) updateTerrainUniforms(fog []float32) {
+	if material.FragmentShaderOptions.Uniforms == nil {
+		material.FragmentShaderOptions.Uniforms = map[string]interface{}{}
+	}
+	material.FragmentShaderOptions.Uniforms["Tiling"] = float32(material.TerrainTiling)
+	if material.TerrainMode == TerrainModeVertexColors {
+		if fog == nil {
+			fog = []float32{0, 0, 0, 0}
+		}
+		material.FragmentShaderOptions.Uniforms["Fog"] = fog
+	}
+}
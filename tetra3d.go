@@ -10,13 +10,24 @@ import (
 
 var defaultImg = ebiten.NewImage(1, 1)
 
-var colorVertexList = make([]ebiten.Vertex, ebiten.MaxIndicesNum)
-var depthVertexList = make([]ebiten.Vertex, ebiten.MaxIndicesNum)
-var indexList = make([]uint16, ebiten.MaxIndicesNum)
 var vertexListIndex = 0
 
-const maxTriangleCount = 21845
+// MaxTrianglesPerMeshPart is the maximum number of triangles that can be rendered in a single draw call, due to Ebiten's limit on the number
+// of vertex indices per draw call. As each MeshPart (or dynamic batch) is rendered in one draw call, MeshParts can't have more triangles
+// than this. Meshes loaded from GLTF or DAE files are automatically split to respect this limit; Meshes generated in code can respect it
+// while generating, or be split afterwards with Mesh.SplitMeshParts().
+const MaxTrianglesPerMeshPart = ebiten.MaxIndicesNum / 3
 
 func init() {
 	defaultImg.Fill(color.White)
 }
+
+// Deterministic, if set to true, makes AnimationPlayers advance blends between animations using the delta time passed to AnimationPlayer.Update()
+// rather than the wall clock, so that blending plays out identically given identical inputs (e.g. for replays or lockstep networking).
+// Everything else that affects simulation results is already ordered regardless of this setting: animation channels are processed sorted by
+// name, and collision and ray test results are sorted stably, so results at equal distances come back in the order the objects were passed.
+// Note that Tetra3D simulates using float64 math, and bit-identical results across machines can't be guaranteed, as Go may fuse
+// multiplications and additions into FMA instructions on some architectures (e.g. arm64, or amd64 when built with GOAMD64=v3), and some math
+// package functions are implemented differently per architecture. For lockstep networking, peers should run builds with the same GOARCH
+// and GOAMD64 (or equivalent) settings. Defaults to false.
+var Deterministic = false
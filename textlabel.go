@@ -0,0 +1,224 @@
+package tetra3d
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+)
+
+// TextLabel is a Node that renders a string of text into a texture and displays it on a quad that always faces the Camera, which is
+// useful for nameplates, damage numbers, or debug labels tied to Nodes. To attach a TextLabel to a Node, just parent it to that Node.
+// The text is drawn by a Model parented to the TextLabel, so the label's color can be changed through TextLabel.Model().Color, and its
+// Material (which is shadeless, transparent, and billboarded on all axes by default) can be customized through TextLabel.Material().
+// By default, TextLabels scale with distance like any other object in the world, and are hidden behind closer objects; both can be
+// changed with TextLabel.SetDistanceScaling() and TextLabel.SetDepthTest().
+type TextLabel struct {
+	*Node
+	Face      font.Face // The font face the text is rendered with. Changing this requires calling TextLabel.SetText(). Defaults to basicfont.Face7x13.
+	PixelSize float64   // How large each pixel of the rendered text is in world units. Changing this requires calling TextLabel.SetText(). Defaults to 0.05.
+
+	text     string
+	texture  *ebiten.Image
+	model    *Model
+	material *Material
+}
+
+// NewTextLabel returns a new TextLabel with the name provided, displaying the given text using the font face provided. If the font face
+// is nil, basicfont.Face7x13 is used.
+func NewTextLabel(name, txt string, face font.Face) *TextLabel {
+
+	if face == nil {
+		face = basicfont.Face7x13
+	}
+
+	material := NewMaterial(name)
+	material.Shadeless = true
+	material.BackfaceCulling = false
+	material.TransparencyMode = TransparencyModeTransparent
+	material.BillboardMode = BillboardModeAll
+
+	label := &TextLabel{
+		Node:      NewNode(name),
+		Face:      face,
+		PixelSize: 0.05,
+		material:  material,
+		model:     NewModel(nil, name+"Text"),
+	}
+
+	label.addChildren(label, label.model)
+
+	label.SetText(txt)
+
+	return label
+
+}
+
+// Clone returns a clone of the TextLabel. The clone has its own Material and texture.
+func (label *TextLabel) Clone() INode {
+
+	clone := NewTextLabel(label.name, "", label.Face)
+	clone.PixelSize = label.PixelSize
+
+	clone.material = label.material.Clone()
+	clone.material.Texture = nil
+
+	modelIndex := -1
+	for i, child := range label.children {
+		if child == label.model {
+			modelIndex = i
+		}
+	}
+
+	clone.Node = label.Node.Clone().(*Node)
+
+	// The text Model is recreated rather than cloned, so we discard the clone of the original's text Model.
+	if modelIndex >= 0 {
+		clone.Node.RemoveChildren(clone.children[modelIndex])
+	}
+
+	for _, child := range clone.children {
+		child.setParent(clone)
+	}
+
+	clone.model.Color = label.model.Color.Clone()
+	clone.model.frustumCulling = label.model.frustumCulling
+	clone.model.fixedSizeDistance = label.model.fixedSizeDistance
+
+	clone.addChildren(clone, clone.model)
+
+	clone.SetText(label.text)
+
+	return clone
+
+}
+
+// SetText sets the text displayed by the TextLabel, rendering it into the TextLabel's texture and resizing the TextLabel's quad to fit
+// it. Multiple lines are supported by using newlines. The text is rendered in white, so that it can be tinted using the TextLabel's
+// Model's Color. As the text is re-rendered each time SetText() is called, it shouldn't be called every frame with the same text.
+func (label *TextLabel) SetText(txt string) {
+
+	label.text = txt
+
+	if label.texture != nil {
+		label.texture.Dispose()
+		label.texture = nil
+		label.material.Texture = nil
+	}
+
+	// Models without Meshes aren't rendered, so an empty TextLabel doesn't draw anything.
+	if txt == "" {
+		label.model.Mesh = nil
+		return
+	}
+
+	// The text is padded by a pixel on each side so that the texture's edges don't bleed into the text.
+	bounds := text.BoundString(label.Face, txt)
+	w := bounds.Dx() + 2
+	h := bounds.Dy() + 2
+
+	label.texture = ebiten.NewImage(w, h)
+	text.Draw(label.texture, txt, label.Face, -bounds.Min.X+1, -bounds.Min.Y+1, color.White)
+
+	label.material.Texture = label.texture
+
+	halfW := float64(w) * label.PixelSize / 2
+	halfH := float64(h) * label.PixelSize / 2
+
+	mesh := NewMesh(label.name + "Text")
+	part := mesh.AddMeshPart(label.material)
+	part.AddTriangles(
+		NewVertex(-halfW, halfH, 0, 0, 0),
+		NewVertex(-halfW, -halfH, 0, 0, 1),
+		NewVertex(halfW, -halfH, 0, 1, 1),
+
+		NewVertex(-halfW, halfH, 0, 0, 0),
+		NewVertex(halfW, -halfH, 0, 1, 1),
+		NewVertex(halfW, halfH, 0, 1, 0),
+	)
+
+	mesh.UpdateBounds()
+
+	label.model.Mesh = mesh
+	label.model.BoundingSphere.Radius = mesh.Bounds().MaxSpan() / 2
+
+}
+
+// Text returns the text displayed by the TextLabel.
+func (label *TextLabel) Text() string {
+	return label.text
+}
+
+// Texture returns the texture the TextLabel's text is rendered into. This is nil if the TextLabel's text is empty.
+func (label *TextLabel) Texture() *ebiten.Image {
+	return label.texture
+}
+
+// Model returns the Model that displays the TextLabel's text.
+func (label *TextLabel) Model() *Model {
+	return label.model
+}
+
+// Material returns the Material the TextLabel's text is displayed with.
+func (label *TextLabel) Material() *Material {
+	return label.material
+}
+
+// SetDistanceScaling sets whether the TextLabel scales with distance like other objects. If scaling is false, the TextLabel instead keeps
+// the size on screen it would have at the reference distance provided from a perspective Camera, so that it remains readable when far away
+// (which is useful for nameplates or objective markers). Note that this doesn't affect orthographic Cameras, as objects don't change
+// size with distance in orthographic projection anyway.
+func (label *TextLabel) SetDistanceScaling(scaling bool, referenceDistance float64) {
+
+	if scaling {
+		label.model.fixedSizeDistance = 0
+		label.model.SetFrustumCulling(true)
+		return
+	}
+
+	if referenceDistance <= 0 {
+		panic("error: TextLabel reference distance must be above 0")
+	}
+
+	label.model.fixedSizeDistance = referenceDistance
+
+	// Scaled TextLabels can grow past their bounding sphere, so they aren't frustum culled.
+	label.model.SetFrustumCulling(false)
+
+}
+
+// DistanceScaling returns whether the TextLabel scales with distance like other objects.
+func (label *TextLabel) DistanceScaling() bool {
+	return label.model.fixedSizeDistance == 0
+}
+
+// SetDepthTest sets whether the TextLabel is hidden behind closer objects. If depth testing is disabled, the TextLabel is drawn over
+// any opaque objects, which is useful for labels that should always be visible, like debug labels.
+func (label *TextLabel) SetDepthTest(depthTest bool) {
+	label.material.DepthTest = depthTest
+}
+
+// DepthTest returns whether the TextLabel is hidden behind closer objects.
+func (label *TextLabel) DepthTest() bool {
+	return label.material.DepthTest
+}
+
+// AddChildren parents the provided children Nodes to the passed parent Node, inheriting its transformations and being under it in the scenegraph
+// hierarchy. If the children are already parented to other Nodes, they are unparented before doing so.
+func (label *TextLabel) AddChildren(children ...INode) {
+	label.addChildren(label, children...)
+}
+
+// Unparent unparents the TextLabel from its parent, removing it from the scenegraph.
+func (label *TextLabel) Unparent() {
+	if label.parent != nil {
+		label.parent.RemoveChildren(label)
+	}
+}
+
+// Type returns the NodeType for this object.
+func (label *TextLabel) Type() NodeType {
+	return NodeTypeTextLabel
+}
@@ -0,0 +1,69 @@
+package tetra3d
+
+import (
+	"github.com/kvartborg/vector"
+)
+
+// Transform represents a pose - a position, scale, and rotation - that can be manipulated without having to deal with raw transformation
+// Matrix4s. Nodes store their local transformation properties as a Transform.
+type Transform struct {
+	Position vector.Vector // The position of the Transform as a 3D vector
+	Scale    vector.Vector // The scale of the Transform as a 3D vector
+	Rotation Matrix4       // The rotation of the Transform as a rotation Matrix4
+}
+
+// NewTransform returns a new identity Transform (i.e. a position of [0, 0, 0], a scale of [1, 1, 1], and no rotation).
+func NewTransform() Transform {
+	return Transform{
+		Position: vector.Vector{0, 0, 0},
+		Scale:    vector.Vector{1, 1, 1},
+		Rotation: NewMatrix4(),
+	}
+}
+
+// NewTransformFromMatrix decomposes the provided transformation Matrix4 into a new Transform. See Matrix4.Decompose() for caveats.
+func NewTransformFromMatrix(matrix Matrix4) Transform {
+	position, scale, rotation := matrix.Decompose()
+	return Transform{
+		Position: position,
+		Scale:    scale,
+		Rotation: rotation,
+	}
+}
+
+// Clone returns a deep copy of the Transform.
+func (transform Transform) Clone() Transform {
+	return Transform{
+		Position: transform.Position.Clone(),
+		Scale:    transform.Scale.Clone(),
+		Rotation: transform.Rotation.Clone(),
+	}
+}
+
+// Compose composes the Transform into a transformation Matrix4, scaling first, then rotating, then translating.
+func (transform Transform) Compose() Matrix4 {
+	matrix := NewMatrix4Scale(transform.Scale[0], transform.Scale[1], transform.Scale[2])
+	matrix = matrix.Mult(transform.Rotation)
+	return matrix.Mult(NewMatrix4Translate(transform.Position[0], transform.Position[1], transform.Position[2]))
+}
+
+// Lerp returns a new Transform interpolated between the Transform and the other Transform by the percentage provided (0 to 1). Position
+// and scale are linearly interpolated, while rotation is interpolated using (normalized) Quaternions.
+func (transform Transform) Lerp(other Transform, percentage float64) Transform {
+
+	start := NewQuaternionFromMatrix4(transform.Rotation)
+	end := NewQuaternionFromMatrix4(other.Rotation)
+
+	return Transform{
+		Position: transform.Position.Add(other.Position.Sub(transform.Position).Scale(percentage)),
+		Scale:    transform.Scale.Add(other.Scale.Sub(transform.Scale).Scale(percentage)),
+		Rotation: NewMatrix4RotateFromQuaternion(start.Lerp(end, percentage).Normalized()),
+	}
+
+}
+
+// Inverse returns the inverse of the Transform (i.e. a Transform that undoes this one). Note that a Transform with non-uniform scale
+// and rotation can't be perfectly inverted into another Transform, in which case the result is approximate.
+func (transform Transform) Inverse() Transform {
+	return NewTransformFromMatrix(transform.Compose().Inverted())
+}
@@ -0,0 +1,95 @@
+package tetra3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/kvartborg/vector"
+)
+
+func matricesEqual(a, b Matrix4) bool {
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if math.Abs(a[y][x]-b[y][x]) > testEpsilon {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestEuler(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		yaw, pitch, roll float64
+		matrix           Matrix4
+	}{
+		{"identity", 0, 0, 0, NewMatrix4()},
+		{"yaw", math.Pi / 2, 0, 0, NewMatrix4Rotate(0, 1, 0, math.Pi/2)},
+		{"pitch", 0, math.Pi / 4, 0, NewMatrix4Rotate(1, 0, 0, math.Pi/4)},
+		{"roll", 0, 0, -math.Pi / 3, NewMatrix4Rotate(0, 0, 1, -math.Pi/3)},
+		{"combined", 0.5, -0.25, 1, NewMatrix4Rotate(0, 0, 1, 1).Mult(NewMatrix4Rotate(1, 0, 0, -0.25)).Mult(NewMatrix4Rotate(0, 1, 0, 0.5))},
+	}
+
+	for _, test := range tests {
+
+		matrix := NewMatrix4RotateEuler(test.yaw, test.pitch, test.roll)
+
+		if !matricesEqual(matrix, test.matrix) {
+			t.Errorf("%s: NewMatrix4RotateEuler() = %v, want %v", test.name, matrix, test.matrix)
+		}
+
+		yaw, pitch, roll := matrix.Euler()
+
+		if math.Abs(yaw-test.yaw) > testEpsilon || math.Abs(pitch-test.pitch) > testEpsilon || math.Abs(roll-test.roll) > testEpsilon {
+			t.Errorf("%s: Euler() = %f, %f, %f, want %f, %f, %f", test.name, yaw, pitch, roll, test.yaw, test.pitch, test.roll)
+		}
+
+	}
+
+	// When pitched straight up, yaw and roll can't be told apart, so the angles don't round trip, but the rotation should.
+	gimbalLocked := NewMatrix4RotateEuler(0.5, math.Pi/2, 0.25)
+	if !matricesEqual(NewMatrix4RotateEuler(gimbalLocked.Euler()), gimbalLocked) {
+		t.Errorf("gimbal locked rotation didn't round trip")
+	}
+
+}
+
+func TestTransform(t *testing.T) {
+
+	transform := Transform{
+		Position: vector.Vector{1, 2, 3},
+		Scale:    vector.Vector{2, 2, 2},
+		Rotation: NewMatrix4Rotate(0, 1, 0, math.Pi/2),
+	}
+
+	other := Transform{
+		Position: vector.Vector{3, 2, 1},
+		Scale:    vector.Vector{4, 4, 4},
+		Rotation: NewMatrix4Rotate(0, 1, 0, -math.Pi/2),
+	}
+
+	tests := []struct {
+		name      string
+		transform Transform
+		point     vector.Vector
+		result    vector.Vector
+	}{
+		{"identity", NewTransform(), vector.Vector{1, 2, 3}, vector.Vector{1, 2, 3}},
+		{"compose", transform, vector.Vector{1, 0, 0}, vector.Vector{1, 2, 1}},
+		{"from matrix", NewTransformFromMatrix(transform.Compose()), vector.Vector{1, 0, 0}, vector.Vector{1, 2, 1}},
+		{"clone", transform.Clone(), vector.Vector{0, 1, 0}, vector.Vector{1, 4, 3}},
+		{"inverse", transform.Inverse(), vector.Vector{1, 2, 1}, vector.Vector{1, 0, 0}},
+		{"lerp start", transform.Lerp(other, 0), vector.Vector{1, 0, 0}, vector.Vector{1, 2, 1}},
+		{"lerp middle", transform.Lerp(other, 0.5), vector.Vector{1, 0, 0}, vector.Vector{5, 2, 2}},
+		{"lerp end", transform.Lerp(other, 1), vector.Vector{1, 0, 0}, vector.Vector{3, 2, 5}},
+	}
+
+	for _, test := range tests {
+		if result := test.transform.Compose().MultVec(test.point); !vectorsEqual(result, test.result) {
+			t.Errorf("%s: transformed %v = %v, want %v", test.name, test.point, result, test.result)
+		}
+	}
+
+}
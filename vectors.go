@@ -0,0 +1,195 @@
+package tetra3d
+
+import (
+	"math"
+
+	"github.com/kvartborg/vector"
+)
+
+// Vector3 is a 3D vector value type. Unlike vector.Vector, Vector3 is not backed by a slice, so it can be passed around and operated upon without
+// allocating on the heap. Tetra3D uses Vector3 internally; the public API still largely uses vector.Vector for the time being, so conversion
+// functions (NewVector3FromVector() and Vector3.ToVector()) are provided to convert between the two.
+type Vector3 struct {
+	X, Y, Z float64
+}
+
+// NewVector3 returns a new Vector3 with the provided X, Y, and Z values.
+func NewVector3(x, y, z float64) Vector3 {
+	return Vector3{X: x, Y: y, Z: z}
+}
+
+// NewVector3FromVector returns a new Vector3 from the first three components of the provided vector.Vector. Missing components are left at 0.
+func NewVector3FromVector(vec vector.Vector) Vector3 {
+	v := Vector3{}
+	if len(vec) > 0 {
+		v.X = vec[0]
+	}
+	if len(vec) > 1 {
+		v.Y = vec[1]
+	}
+	if len(vec) > 2 {
+		v.Z = vec[2]
+	}
+	return v
+}
+
+// ToVector returns the Vector3 as a newly allocated 3D vector.Vector.
+func (vec Vector3) ToVector() vector.Vector {
+	return vector.Vector{vec.X, vec.Y, vec.Z}
+}
+
+// Add returns a copy of the Vector3 with the other Vector3 added to it.
+func (vec Vector3) Add(other Vector3) Vector3 {
+	return Vector3{vec.X + other.X, vec.Y + other.Y, vec.Z + other.Z}
+}
+
+// Sub returns a copy of the Vector3 with the other Vector3 subtracted from it.
+func (vec Vector3) Sub(other Vector3) Vector3 {
+	return Vector3{vec.X - other.X, vec.Y - other.Y, vec.Z - other.Z}
+}
+
+// Scale returns a copy of the Vector3 scaled by the provided scalar.
+func (vec Vector3) Scale(scalar float64) Vector3 {
+	return Vector3{vec.X * scalar, vec.Y * scalar, vec.Z * scalar}
+}
+
+// Invert returns a copy of the Vector3 with all components negated.
+func (vec Vector3) Invert() Vector3 {
+	return Vector3{-vec.X, -vec.Y, -vec.Z}
+}
+
+// Dot returns the dot product of the Vector3 and the other Vector3.
+func (vec Vector3) Dot(other Vector3) float64 {
+	return vec.X*other.X + vec.Y*other.Y + vec.Z*other.Z
+}
+
+// Cross returns the cross product of the Vector3 and the other Vector3.
+func (vec Vector3) Cross(other Vector3) Vector3 {
+	return Vector3{
+		vec.Y*other.Z - other.Y*vec.Z,
+		vec.Z*other.X - other.Z*vec.X,
+		vec.X*other.Y - other.X*vec.Y,
+	}
+}
+
+// Magnitude returns the length of the Vector3.
+func (vec Vector3) Magnitude() float64 {
+	return math.Sqrt(vec.X*vec.X + vec.Y*vec.Y + vec.Z*vec.Z)
+}
+
+// MagnitudeSquared returns the squared length of the Vector3; this is faster than Magnitude() as it avoids a square root.
+func (vec Vector3) MagnitudeSquared() float64 {
+	return vec.X*vec.X + vec.Y*vec.Y + vec.Z*vec.Z
+}
+
+// Distance returns the distance from the Vector3 to the other Vector3.
+func (vec Vector3) Distance(other Vector3) float64 {
+	return vec.Sub(other).Magnitude()
+}
+
+// DistanceSquared returns the squared distance from the Vector3 to the other Vector3.
+func (vec Vector3) DistanceSquared(other Vector3) float64 {
+	return vec.Sub(other).MagnitudeSquared()
+}
+
+// Unit returns a normalized copy of the Vector3 (i.e. a vector of length 1 pointing in the same direction). A zero-length Vector3 is returned as-is.
+func (vec Vector3) Unit() Vector3 {
+	l := vec.Magnitude()
+	if l == 0 {
+		return vec
+	}
+	return Vector3{vec.X / l, vec.Y / l, vec.Z / l}
+}
+
+// Lerp returns a Vector3 linearly interpolated between the Vector3 and the other Vector3 by the percentage provided (0 to 1).
+func (vec Vector3) Lerp(other Vector3, percentage float64) Vector3 {
+	return Vector3{
+		vec.X + ((other.X - vec.X) * percentage),
+		vec.Y + ((other.Y - vec.Y) * percentage),
+		vec.Z + ((other.Z - vec.Z) * percentage),
+	}
+}
+
+// AngleBetween returns the unsigned angle between the two vectors provided in radians (ranging from 0 to pi).
+func AngleBetween(a, b vector.Vector) float64 {
+	return angleBetween(NewVector3FromVector(a), NewVector3FromVector(b))
+}
+
+func angleBetween(a, b Vector3) float64 {
+	// atan2 is more precise than acos for nearly parallel vectors.
+	return math.Atan2(a.Cross(b).Magnitude(), a.Dot(b))
+}
+
+// SignedAngleBetween returns the angle between the two vectors provided in radians (ranging from -pi to pi), signed depending on the direction of
+// rotation around the axis provided. For example, with an axis of +Y, turning from a to b counter-clockwise (when viewed from above) returns a positive angle.
+func SignedAngleBetween(a, b, axis vector.Vector) float64 {
+
+	av := NewVector3FromVector(a)
+	bv := NewVector3FromVector(b)
+
+	angle := angleBetween(av, bv)
+
+	if av.Cross(bv).Dot(NewVector3FromVector(axis)) < 0 {
+		return -angle
+	}
+
+	return angle
+
+}
+
+// Vector4 is a 4D vector value type, used primarily for holding clip-space (homogenous) coordinates. Like Vector3, it's not backed by a slice.
+type Vector4 struct {
+	X, Y, Z, W float64
+}
+
+// NewVector4 returns a new Vector4 with the provided X, Y, Z, and W values.
+func NewVector4(x, y, z, w float64) Vector4 {
+	return Vector4{X: x, Y: y, Z: z, W: w}
+}
+
+// NewVector4FromVector returns a new Vector4 from the first four components of the provided vector.Vector. Missing components are left at 0.
+func NewVector4FromVector(vec vector.Vector) Vector4 {
+	v := Vector4{}
+	if len(vec) > 0 {
+		v.X = vec[0]
+	}
+	if len(vec) > 1 {
+		v.Y = vec[1]
+	}
+	if len(vec) > 2 {
+		v.Z = vec[2]
+	}
+	if len(vec) > 3 {
+		v.W = vec[3]
+	}
+	return v
+}
+
+// ToVector returns the Vector4 as a newly allocated 4D vector.Vector.
+func (vec Vector4) ToVector() vector.Vector {
+	return vector.Vector{vec.X, vec.Y, vec.Z, vec.W}
+}
+
+// Vector3 returns the X, Y, and Z components of the Vector4 as a Vector3.
+func (vec Vector4) Vector3() Vector3 {
+	return Vector3{vec.X, vec.Y, vec.Z}
+}
+
+// MultVec3 multiplies the Vector3 by the Matrix4 (including translation), returning the result as a Vector3 without allocating.
+func (matrix Matrix4) MultVec3(vect Vector3) Vector3 {
+	return Vector3{
+		matrix[0][0]*vect.X + matrix[1][0]*vect.Y + matrix[2][0]*vect.Z + matrix[3][0],
+		matrix[0][1]*vect.X + matrix[1][1]*vect.Y + matrix[2][1]*vect.Z + matrix[3][1],
+		matrix[0][2]*vect.X + matrix[1][2]*vect.Y + matrix[2][2]*vect.Z + matrix[3][2],
+	}
+}
+
+// MultVec3W multiplies the Vector3 by the Matrix4 (including translation), returning the result as a Vector4 (including the W component) without allocating.
+func (matrix Matrix4) MultVec3W(vect Vector3) Vector4 {
+	return Vector4{
+		matrix[0][0]*vect.X + matrix[1][0]*vect.Y + matrix[2][0]*vect.Z + matrix[3][0],
+		matrix[0][1]*vect.X + matrix[1][1]*vect.Y + matrix[2][1]*vect.Z + matrix[3][1],
+		matrix[0][2]*vect.X + matrix[1][2]*vect.Y + matrix[2][2]*vect.Z + matrix[3][2],
+		matrix[0][3]*vect.X + matrix[1][3]*vect.Y + matrix[2][3]*vect.Z + matrix[3][3],
+	}
+}
@@ -0,0 +1,76 @@
+package tetra3d
+
+// World holds the environmental settings used to render a Scene, like its clear color, fog, and ambient lighting. A World can be cloned and
+// shared between Scenes, and swapped out at runtime by setting Scene.World (i.e. to switch between day and night, or to change the ambience
+// when the player goes underwater) - as all of the settings change together, there's no frame where some settings have changed and others haven't.
+type World struct {
+	Name       string // The name of the World.
+	ClearColor *Color // The clear color of the screen; note that this doesn't clear the color of the camera buffer or screen automatically;
+	// this is just what the color is if the scene was exported using the Tetra3D addon from Blender. It's up to you as to how you'd like to
+	// use it.
+	FogColor *Color  // The Color of any fog present in the World.
+	FogMode  FogMode // The FogMode, indicating how the fog color is blended if it's on (not FogOff).
+	// FogRange is the depth range at which the fog is active. FogRange consists of two numbers,
+	// ranging from 0 to 1. The first indicates the start of the fog, and the second the end, in
+	// terms of total depth of the near / far clipping plane. The default is [0, 1].
+	FogRange   []float32
+	LightingOn bool // If lighting is enabled when rendering Scenes using the World.
+	// AmbientLight is the ambient lighting applied to all Models in Scenes using the World, in addition to any Lights in the Scenes themselves.
+	// The AmbientLight doesn't need to be (and shouldn't be) parented to a Scene's tree. If it's nil, no ambient lighting is applied by the World.
+	AmbientLight *AmbientLight
+}
+
+// defaultWorld is used to render Scenes that don't have a World; it shouldn't be altered.
+var defaultWorld = NewWorld("Default")
+
+// NewWorld creates a new World with the name given and the default settings - lighting on, no fog, and no ambient lighting.
+func NewWorld(name string) *World {
+	return &World{
+		Name:       name,
+		FogColor:   NewColor(0, 0, 0, 0),
+		FogRange:   []float32{0, 1},
+		LightingOn: true,
+		ClearColor: NewColor(0.08, 0.09, 0.1, 1),
+	}
+}
+
+// Clone creates a clone of the World, including its AmbientLight.
+func (world *World) Clone() *World {
+
+	newWorld := NewWorld(world.Name)
+	newWorld.ClearColor = world.ClearColor.Clone()
+	newWorld.FogColor = world.FogColor.Clone()
+	newWorld.FogMode = world.FogMode
+	newWorld.FogRange[0] = world.FogRange[0]
+	newWorld.FogRange[1] = world.FogRange[1]
+	newWorld.LightingOn = world.LightingOn
+
+	if world.AmbientLight != nil {
+		newWorld.AmbientLight = world.AmbientLight.Clone().(*AmbientLight)
+	}
+
+	return newWorld
+
+}
+
+// fogAsFloatSlice returns the fog color and mode as a slice of float32s for passing to shaders. The values are written into the
+// provided slice if it's large enough, so that it can be reused from frame to frame without allocating.
+func (world *World) fogAsFloatSlice(fog []float32) []float32 {
+
+	if len(fog) < 4 {
+		fog = make([]float32, 4)
+	}
+
+	fog[0] = float32(world.FogColor.R)
+	fog[1] = float32(world.FogColor.G)
+	fog[2] = float32(world.FogColor.B)
+	fog[3] = float32(world.FogMode)
+
+	if world.FogMode == FogMultiply {
+		fog[0] = 1 - fog[0]
+		fog[1] = 1 - fog[1]
+		fog[2] = 1 - fog[2]
+	}
+
+	return fog
+}